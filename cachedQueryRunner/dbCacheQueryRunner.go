@@ -38,6 +38,13 @@ func (r *cachedQueryRunner) RelatedFactory(factories []preformShare.IQueryFactor
 	return &rr
 }
 
+// ReplicaOf the replica shares the cache, writes on the primary clear it
+func (r *cachedQueryRunner) ReplicaOf(conn preformShare.DbQueryRunner) preformShare.QueryRunner {
+	rr := *r
+	rr.DbQueryRunner = conn
+	return &rr
+}
+
 func (r *cachedQueryRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	value, saver, ok := r.cacher.Load(r.hashKey(bytes.NewBuffer([]byte(query)), args))
 	if !ok {
@@ -91,6 +98,9 @@ func (r *cachedQueryRunner) InsertAndReturnAutoId(ctx context.Context, lastIdMet
 		}
 		r.cacher.ClearByFactories(r.relatedFactories)
 		return res.LastInsertId()
+	} else if lastIdMethod == dialect.LastInsertIdMethodByOutParam {
+		_, err = r.ExecContext(ctx, query, append(args, sql.Out{Dest: &lastId})...)
+		return
 	} else {
 		err = r.QueryRowContext(ctx, query, args...).Scan(&lastId)
 		return
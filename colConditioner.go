@@ -1,10 +1,12 @@
 package preform
 
 import (
+	"encoding/json"
 	"fmt"
 	preformShare "github.com/go-preform/preform/share"
 	preformSqlizer "github.com/go-preform/preform/sqlizer"
 	"github.com/go-preform/squirrel"
+	"reflect"
 )
 
 type ICond = squirrel.Sqlizer
@@ -13,6 +15,7 @@ type colConditioner struct {
 	col        ICol
 	dialect    preformShare.IDialect
 	conditions []func(ICol, preformShare.IDialect) ICond //prevent render before init
+	nested     map[int]ICond                             //And / Or conditions by position, see mapNested
 	isOr       bool
 }
 
@@ -28,6 +31,13 @@ type IColConditioner interface {
 	Lt(v any) colConditioner
 	LtOrEq(v any) colConditioner
 	Between(v1, v2 any) colConditioner
+	NotBetween(v1, v2 any) colConditioner
+	In(values ...any) colConditioner
+	NotIn(values ...any) colConditioner
+	IsNull() colConditioner
+	IsNotNull() colConditioner
+	NotLike(v any) colConditioner
+	ILike(v any) colConditioner
 	And(cond ...ICond) colConditioner
 	Or(cond ...ICond) colConditioner
 	Contains(arrCol any) colConditioner
@@ -35,6 +45,10 @@ type IColConditioner interface {
 	HasAny(arrCol any) colConditioner
 	Any(v any) colConditioner
 	Concat(arrCol any) colConditioner
+	JsonContains(v any) colConditioner
+	JsonContainsRaw(raw string) colConditioner
+	JsonHasKey(path ...string) colConditioner
+	Match(text string) colConditioner
 	NoParentCode() colConditioner
 }
 
@@ -134,13 +148,87 @@ func (c colConditioner) Between(v1, v2 any) colConditioner {
 	return c
 }
 
-func (c colConditioner) And(cond ...ICond) colConditioner {
-	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return squirrel.And(cond) })
+// NotBetween
+func (c colConditioner) NotBetween(v1, v2 any) colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond {
+		return d.NotBetween(c, v1, v2)
+	})
+	return c
+}
+
+// In In(1, 2), In([]int32{1, 2}) or In(subQuery)
+func (c colConditioner) In(values ...any) colConditioner {
+	v := inValues(values)
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return d.In(c, v) })
+	return c
+}
+
+// NotIn
+func (c colConditioner) NotIn(values ...any) colConditioner {
+	v := inValues(values)
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return d.NotIn(c, v) })
 	return c
 }
 
+// IsNull
+func (c colConditioner) IsNull() colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return d.IsNull(c) })
+	return c
+}
+
+// IsNotNull
+func (c colConditioner) IsNotNull() colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return d.IsNotNull(c) })
+	return c
+}
+
+// NotLike
+func (c colConditioner) NotLike(v any) colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return d.NotLike(c, v) })
+	return c
+}
+
+// ILike case insensitive
+func (c colConditioner) ILike(v any) colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return d.ILike(c, v) })
+	return c
+}
+
+func (c colConditioner) And(cond ...ICond) colConditioner {
+	return c.nest(squirrel.And(cond))
+}
+
 func (c colConditioner) Or(cond ...ICond) colConditioner {
-	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return squirrel.Or(cond) })
+	return c.nest(squirrel.Or(cond))
+}
+
+func (c colConditioner) nest(cond ICond) colConditioner {
+	nested := make(map[int]ICond, len(c.nested)+1)
+	for i, n := range c.nested {
+		nested[i] = n
+	}
+	nested[len(c.conditions)] = cond
+	c.nested = nested
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond { return cond })
+	return c
+}
+
+// mapNested the And / Or conditions replaced by fn, e.g. to re-alias their columns
+func (c colConditioner) mapNested(fn func(ICond) ICond) colConditioner {
+	if len(c.nested) == 0 {
+		return c
+	}
+	var (
+		conditions = make([]func(ICol, preformShare.IDialect) ICond, len(c.conditions))
+		nested     = make(map[int]ICond, len(c.nested))
+	)
+	copy(conditions, c.conditions)
+	for i, n := range c.nested {
+		mapped := fn(n)
+		nested[i] = mapped
+		conditions[i] = func(c ICol, d preformShare.IDialect) ICond { return mapped }
+	}
+	c.conditions, c.nested = conditions, nested
 	return c
 }
 
@@ -179,3 +267,45 @@ func (c colConditioner) ContainsBy(arrayCol any) colConditioner {
 	})
 	return c
 }
+
+// JsonContains json column contains v, marshaled to json, so a go string matches a json string
+func (c colConditioner) JsonContains(v any) colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond {
+		return preformSqlizer.JsonContains{Col: c, Value: v}.WithDialect(d)
+	})
+	return c
+}
+
+// JsonContainsRaw json column contains the encoded json, e.g. JsonContainsRaw(`{"enableCookie":true}`)
+func (c colConditioner) JsonContainsRaw(raw string) colConditioner {
+	return c.JsonContains(json.RawMessage(raw))
+}
+
+// JsonHasKey json column has the nested key, e.g. JsonHasKey("config", "enableCookie")
+func (c colConditioner) JsonHasKey(path ...string) colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond {
+		return preformSqlizer.JsonHasKey{Col: c, Path: path}.WithDialect(d)
+	})
+	return c
+}
+
+// Match full-text search of the column, see MatchRank for ordering by relevance
+func (c colConditioner) Match(text string) colConditioner {
+	c.conditions = append(c.conditions, func(c ICol, d preformShare.IDialect) ICond {
+		return preformSqlizer.FullTextMatch{Col: c, Text: text}.WithDialect(d)
+	})
+	return c
+}
+
+// inValues a single list or sub query as it is
+func inValues(values []any) any {
+	if len(values) == 1 {
+		if _, ok := values[0].(squirrel.Sqlizer); ok {
+			return values[0]
+		}
+		if k := reflect.TypeOf(values[0]); k != nil && (k.Kind() == reflect.Slice || k.Kind() == reflect.Array) {
+			return values[0]
+		}
+	}
+	return values
+}
@@ -59,6 +59,7 @@ type ICol interface {
 	SetAlias(alias string) ICol
 	Alias() string
 	QueryFactory() IQuery
+	rawPtrScanner() (v any, toScanner func(*any) any, direct bool) //direct: toScanner returns the *any as is, scan into the body field instead
 	unwrapPtr(any) any
 	unwrapPtrForInsert(any) any //care default value
 	unwrapPtrForUpdate(any) any
@@ -87,6 +88,11 @@ type ICol interface {
 	JsonAgg() iAggregateCol
 	ArrayAgg() iAggregateCol
 	GroupConcat(splitter string) iAggregateCol
+	RowNumber() iAggregateCol
+	Rank() iAggregateCol
+	DenseRank() iAggregateCol
+	Lag(offset int) iAggregateCol
+	Lead(offset int) iAggregateCol
 	CaseStmt(pkName string) *CaseStmt
 	IsSame(ICol) bool
 }
@@ -507,6 +513,11 @@ func (c column[T]) properties() (isArray bool, isPtr bool, isPk bool, isAuto boo
 	return
 }
 func (c column[T]) GetRawPtrScanner() (vv any, toScanner func(*any) any) {
+	vv, toScanner, _ = c.rawPtrScanner()
+	return
+}
+
+func (c column[T]) rawPtrScanner() (vv any, toScanner func(*any) any, direct bool) {
 	var (
 		v T
 	)
@@ -515,15 +526,15 @@ func (c column[T]) GetRawPtrScanner() (vv any, toScanner func(*any) any) {
 			// deprecated
 			return v, func(a *any) any {
 				return (&scanners.ScannerAny[T]{}).PtrAny(a)
-			}
+			}, false
 		}
 		return v, func(a *any) any {
 			return a
-		}
+		}, true
 	}
 	return v, func(a *any) any {
 		return c.sqlScanner().PtrAny(a)
-	}
+	}, false
 }
 
 func (c column[T]) wrapScanner(ptr any) any {
@@ -673,7 +684,42 @@ func (c *Column[T]) setValueParser() {
 		} else {
 			c.insertValueParser = c.valueParser
 		}
+		if parser, ok := parsers[anySliceType]; ok && c.isArray && vt != anySliceType {
+			//list parser of the dialect for every array type, e.g. duckdb binds no slices
+			var (
+				listParser                     = parser.(func(string, bool) func(*[]any) any)(c.dbType, false)
+				valueParser, insertValueParser = c.valueParser, c.insertValueParser
+			)
+			c.valueParser = func(v *T) any {
+				return parseList(listParser, valueParser(v))
+			}
+			c.insertValueParser = func(v *T) any {
+				return parseList(listParser, insertValueParser(v))
+			}
+		}
+	}
+}
+
+var anySliceType = reflect.TypeOf([]any{})
+
+func parseList(listParser func(*[]any) any, v any) any {
+	var items []any
+	switch vv := v.(type) {
+	case []any:
+		items = vv
+	case preformShare.IArrayTypes:
+		items = vv.IterAny()
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return v //DEFAULT_VALUE or nil
+		}
+		items = make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
 	}
+	return listParser(&items)
 }
 
 func (c column[T]) Name() string {
@@ -19,6 +19,7 @@ type AggregateCol[T any] struct {
 	params     []any
 	alias      string
 	dialect    preformShare.IDialect
+	over       []WindowClause
 }
 
 type iTypedCol interface {
@@ -28,6 +29,7 @@ type iTypedCol interface {
 type iAggregateCol interface {
 	ICol
 	WithDialect(iDialect preformShare.IDialect) squirrel.Sqlizer
+	Over(clauses ...WindowClause) iAggregateCol
 }
 
 func (a *AggregateCol[T]) SetAlias(alias string) ICol {
@@ -36,13 +38,19 @@ func (a *AggregateCol[T]) SetAlias(alias string) ICol {
 }
 
 func (a *AggregateCol[T]) GetRawPtrScanner() (vv any, toScanner func(*any) any) {
+	vv, toScanner, _ = a.rawPtrScanner()
+	return
+}
+
+func (a *AggregateCol[T]) rawPtrScanner() (vv any, toScanner func(*any) any, direct bool) {
 	var (
 		v        T
 		toParse  any
 		dummyCol = &column[T]{}
 	)
 	switch a.Aggregator {
-	case dialect.AggSum, dialect.AggAvg, dialect.AggMax, dialect.AggMin, dialect.AggMean, dialect.AggMedian, dialect.AggMode, dialect.AggStdDev:
+	case dialect.AggSum, dialect.AggAvg, dialect.AggMax, dialect.AggMin, dialect.AggMean, dialect.AggMedian, dialect.AggMode, dialect.AggStdDev,
+		dialect.WinRowNumber, dialect.WinRank, dialect.WinDenseRank, dialect.WinLag, dialect.WinLead:
 		prepareColumnTypeFunc[T](dummyCol, &v)
 	case dialect.AggCount, dialect.AggCountDistinct:
 		u64 := uint64(0)
@@ -64,15 +72,15 @@ func (a *AggregateCol[T]) GetRawPtrScanner() (vv any, toScanner func(*any) any)
 			}
 			return toParse, func(a *any) any {
 				return toParse
-			}
+			}, false
 		}
 		return v, func(a *any) any {
 			return a
-		}
+		}, true
 	}
 	return v, func(a *any) any {
 		return dummyCol.sqlScanner().PtrAny(a)
-	}
+	}, false
 }
 
 func (a AggregateCol[T]) WithDialect(d preformShare.IDialect) ICond {
@@ -82,6 +90,9 @@ func (a AggregateCol[T]) WithDialect(d preformShare.IDialect) ICond {
 
 func (a AggregateCol[T]) ToSql() (string, []any, error) {
 	q, args, err := a.dialect.Aggregate(a.Aggregator, a.body, a.params...).ToSql()
+	if a.over != nil {
+		q = fmt.Sprintf("%s OVER (%s)", q, windowClausesToSql(a.over))
+	}
 	if a.alias != "" {
 		q = fmt.Sprintf("%s AS %s", q, a.dialect.QuoteIdentifier(a.alias))
 	} else if a.ICol != nil {
@@ -154,7 +165,7 @@ func (a aggr) ArrayAgg(colOrString any) iAggregateCol {
 }
 
 func (a Column[T]) Aggregate(fn preformShare.Aggregator, params ...any) iAggregateCol {
-	return &AggregateCol[T]{ICol: &a, Aggregator: fn, body: a.GetCode(), params: params, alias: a.Alias()}
+	return &AggregateCol[T]{ICol: &a, Aggregator: fn, body: a.GetCode(), params: params, alias: a.Alias(), dialect: a.factory.Db().dialect}
 }
 
 func (a Column[T]) Sum() iAggregateCol {
@@ -126,27 +126,31 @@ func (c ForeignKeyJoin) ToJoinSql(queryFactory IQuery) (IQuery, string, []any, e
 		fixedCond    = f.FixedCondition()
 		extraCondLen = len(c.extraConditions)
 		cond         = make(squirrel.And, len(fromCols)+extraCondLen)
-		toCol        IColFromFactory
 	)
 	copy(cond, c.extraConditions)
 	for i, fromCol := range fromCols {
-		toCol = toCols[i]
-		if isArray, _, _, _ := fromCol.properties(); isArray {
-			if isArray, _, _, _ := toCol.properties(); isArray {
-				cond[i+extraCondLen] = toCol.HasAny(fromCol)
-			} else {
-				cond[i+extraCondLen] = fromCol.Any(toCol)
-			}
-		} else if isArray, _, _, _ := toCol.properties(); isArray {
-			cond[i+extraCondLen] = toCol.Any(fromCol)
-		} else {
-			cond[i+extraCondLen] = fromCol.Eq(toCol)
-		}
+		cond[i+extraCondLen] = keyPairCond(fromCol, toCols[i])
 	}
 	if fixedCond != nil {
 		cond = append(cond, f.FixedCondition())
 	}
+	if trashed := notTrashedCond(f); trashed != nil {
+		cond = append(cond, trashed)
+	}
 
 	q, args, err := cond.ToSql()
 	return f, fmt.Sprintf("%s ON %s", f.fromClause(), q), args, err
 }
+
+// keyPairCond matching keys, array keys by ANY
+func keyPairCond(fromCol, toCol ICol) ICond {
+	if isArray, _, _, _ := fromCol.properties(); isArray {
+		if isArray, _, _, _ := toCol.properties(); isArray {
+			return toCol.HasAny(fromCol)
+		}
+		return fromCol.Any(toCol)
+	} else if isArray, _, _, _ := toCol.properties(); isArray {
+		return toCol.Any(fromCol)
+	}
+	return fromCol.Eq(toCol)
+}
@@ -0,0 +1,68 @@
+package preform
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+)
+
+// MatchRankCol full-text relevance of a column, higher is more relevant
+//
+//	rank := f.Description.MatchRank("red shoes").SetAlias("Rank")
+//	f.Select(f.Id, rank).Where(f.Description.Match("red shoes")).OrderBy(rank.Desc())
+type MatchRankCol struct {
+	col     ICol
+	text    string
+	alias   string
+	dialect preformShare.IDialect
+}
+
+func newMatchRankCol(col ICol, text string, d preformShare.IDialect) *MatchRankCol {
+	return &MatchRankCol{col: col, text: text, alias: col.Alias() + "Rank", dialect: d}
+}
+
+func (r *MatchRankCol) SetAlias(alias string) *MatchRankCol {
+	rr := *r
+	rr.alias = alias
+	return &rr
+}
+
+func (r MatchRankCol) Alias() string {
+	return r.alias
+}
+
+// Asc order by the alias, as the rank must be selected for its args
+func (r MatchRankCol) Asc() string {
+	return r.dialect.QuoteIdentifier(r.alias) + " ASC"
+}
+
+func (r MatchRankCol) Desc() string {
+	return r.dialect.QuoteIdentifier(r.alias) + " DESC"
+}
+
+func (r MatchRankCol) ToSql() (string, []any, error) {
+	q, args, err := r.dialect.FullTextRank(r.col, r.text)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s AS %s", q, r.dialect.QuoteIdentifier(r.alias)), args, nil
+}
+
+func (r *MatchRankCol) GetRawPtrScanner() (vv any, toScanner func(*any) any) {
+	var (
+		v        float64
+		dummyCol = &column[float64]{}
+	)
+	prepareColumnTypeFunc[float64](dummyCol, &v)
+	return v, func(a *any) any {
+		return dummyCol.sqlScanner().PtrAny(a)
+	}
+}
+
+// MatchRank full-text relevance of text to the column, to select or order by alias
+func (c *Column[T]) MatchRank(text string) *MatchRankCol {
+	return newMatchRankCol(c, text, c.factory.Db().dialect)
+}
+
+func (c *ColumnWrap[C]) MatchRank(text string) *MatchRankCol {
+	return newMatchRankCol(c, text, c.altFactory.Db().dialect)
+}
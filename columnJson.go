@@ -0,0 +1,182 @@
+package preform
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+	"strings"
+)
+
+const jsonFieldMaxDepth = 5
+
+// JsonPathCol value at the path of a json column, typed by V for scanning and to pick the extract function of the dialect
+//
+//	f.Detail.JsonPath("address", "city").Eq("HK")
+//	preform.JsonField(f.Detail, func(d *types.UserDetail) *uint32 { return &d.Age }).Gt(10)
+type JsonPathCol[V any] struct {
+	ICol
+	src     ICol
+	path    []string
+	alias   string
+	dialect preformShare.IDialect
+	err     error
+}
+
+// JsonPathOf value at path as V, digits are taken as array indexes
+func JsonPathOf[V any](col ICol, path ...string) *JsonPathCol[V] {
+	var (
+		c = &JsonPathCol[V]{src: col, path: path}
+	)
+	if len(path) != 0 {
+		c.alias = path[len(path)-1]
+	}
+	f, ok := col.QueryFactory().(IFactory)
+	if !ok {
+		c.ICol, c.err = col, fmt.Errorf("json path of %s: column not from a factory", col.Alias())
+		return c
+	}
+	c.dialect = f.Db().dialect
+	cc := newColWrap(col, f, c.alias)
+	cc.code, c.err = c.dialect.JsonExtract(col, path, reflect.TypeOf((*V)(nil)).Elem().Kind())
+	if c.err != nil {
+		f.Db().Error("json path", c.err)
+	}
+	c.ICol = cc
+	return c
+}
+
+// JsonField value of a field of the json struct S, path is taken from the json tags so it follows renames
+func JsonField[S, V any](col ICol, field func(*S) *V) *JsonPathCol[V] {
+	var (
+		s    = new(S)
+		sv   = reflect.ValueOf(s).Elem()
+		path []string
+		ok   bool
+	)
+	if sv.Kind() == reflect.Struct {
+		jsonFieldAlloc(sv, 0)
+		path, ok = jsonFieldPath(sv, reflect.ValueOf(field(s)).Pointer(), reflect.TypeOf((*V)(nil)).Elem(), 0)
+	}
+	if !ok {
+		return &JsonPathCol[V]{ICol: col, src: col, err: fmt.Errorf("json field of %s: field not found in %T", col.Alias(), *s)}
+	}
+	return JsonPathOf[V](col, path...)
+}
+
+// jsonFieldAlloc nested struct pointers must be allocated for the field func to return their fields
+func jsonFieldAlloc(v reflect.Value, depth int) {
+	if depth == jsonFieldMaxDepth {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Kind() == reflect.Struct:
+			jsonFieldAlloc(f, depth+1)
+		case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.Struct:
+			f.Set(reflect.New(f.Type().Elem()))
+			jsonFieldAlloc(f.Elem(), depth+1)
+		}
+	}
+}
+
+func jsonFieldPath(v reflect.Value, addr uintptr, vType reflect.Type, depth int) ([]string, bool) {
+	if depth == jsonFieldMaxDepth {
+		return nil, false
+	}
+	var (
+		t = v.Type()
+	)
+	for i := 0; i < v.NumField(); i++ {
+		var (
+			ft        = t.Field(i)
+			f         = v.Field(i)
+			name      = strings.Split(ft.Tag.Get("json"), ",")[0]
+			sub       []string
+			ok        bool
+			flattened = ft.Anonymous && name == ""
+		)
+		if !ft.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = ft.Name
+		}
+		if f.UnsafeAddr() == addr && ft.Type == vType {
+			return []string{name}, true
+		}
+		if f.Kind() == reflect.Ptr && !f.IsNil() {
+			f = f.Elem()
+		}
+		if f.Kind() != reflect.Struct {
+			continue
+		}
+		if sub, ok = jsonFieldPath(f, addr, vType, depth+1); ok {
+			if flattened {
+				return sub, true
+			}
+			return append([]string{name}, sub...), true
+		}
+	}
+	return nil, false
+}
+
+func (c *JsonPathCol[V]) SetAlias(alias string) ICol {
+	cc := *c
+	cc.alias = alias
+	return &cc
+}
+
+func (c JsonPathCol[V]) Alias() string {
+	return c.alias
+}
+
+// Path keys of the value in the json column
+func (c JsonPathCol[V]) Path() []string {
+	return c.path
+}
+
+func (c JsonPathCol[V]) ToSql() (string, []any, error) {
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	return fmt.Sprintf("%s AS %s", c.GetCode(), c.dialect.QuoteIdentifier(c.alias)), nil, nil
+}
+
+func (c *JsonPathCol[V]) GetRawPtrScanner() (vv any, toScanner func(*any) any) {
+	vv, toScanner, _ = c.rawPtrScanner()
+	return
+}
+
+func (c *JsonPathCol[V]) rawPtrScanner() (vv any, toScanner func(*any) any, direct bool) {
+	var (
+		v        V
+		dummyCol = &column[V]{}
+	)
+	prepareColumnTypeFunc[V](dummyCol, &v)
+	if dummyCol.sqlScanner == nil {
+		if dummyCol.isScanner {
+			return &v, func(a *any) any {
+				return &v
+			}, false
+		}
+		return v, func(a *any) any {
+			return a
+		}, true
+	}
+	return v, func(a *any) any {
+		return dummyCol.sqlScanner().PtrAny(a)
+	}, false
+}
+
+// JsonPath text value at path, use JsonPathOf or JsonField for other types
+func (c *Column[T]) JsonPath(path ...string) *JsonPathCol[string] {
+	return JsonPathOf[string](c, path...)
+}
+
+func (c *ColumnWrap[C]) JsonPath(path ...string) *JsonPathCol[string] {
+	return JsonPathOf[string](c, path...)
+}
@@ -0,0 +1,159 @@
+package preform
+
+import (
+	"fmt"
+	"github.com/go-preform/preform/dialect"
+	preformShare "github.com/go-preform/preform/share"
+	preformTypes "github.com/go-preform/preform/types"
+	"sort"
+	"strings"
+)
+
+// frame bounds of RowsBetween and RangeBetween
+const (
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+	CurrentRow         = "CURRENT ROW"
+)
+
+type windowClauseKind uint8
+
+const (
+	windowPartition windowClauseKind = iota
+	windowOrder
+	windowFrame
+)
+
+// WindowClause PARTITION BY, ORDER BY or frame of Over
+type WindowClause struct {
+	kind windowClauseKind
+	sql  string
+}
+
+// PartitionBy cols as ICol or raw string
+func PartitionBy(cols ...any) WindowClause {
+	return WindowClause{kind: windowPartition, sql: "PARTITION BY " + windowCols(cols)}
+}
+
+// OrderBy cols as ICol, OrderDesc(col), col.Desc() or raw string
+func OrderBy(cols ...any) WindowClause {
+	return WindowClause{kind: windowOrder, sql: "ORDER BY " + windowCols(cols)}
+}
+
+// RowsBetween frame by rows, e.g. RowsBetween(UnboundedPreceding, CurrentRow) for running totals
+func RowsBetween(from, to string) WindowClause {
+	return WindowClause{kind: windowFrame, sql: fmt.Sprintf("ROWS BETWEEN %s AND %s", from, to)}
+}
+
+// RangeBetween frame by values of the ORDER BY
+func RangeBetween(from, to string) WindowClause {
+	return WindowClause{kind: windowFrame, sql: fmt.Sprintf("RANGE BETWEEN %s AND %s", from, to)}
+}
+
+// Preceding frame bound of n rows before
+func Preceding(n int) string {
+	return fmt.Sprintf("%d PRECEDING", n)
+}
+
+// Following frame bound of n rows after
+func Following(n int) string {
+	return fmt.Sprintf("%d FOLLOWING", n)
+}
+
+func windowCols(cols []any) string {
+	var (
+		codes = make([]string, len(cols))
+	)
+	for i, col := range cols {
+		switch c := col.(type) {
+		case descCol:
+			codes[i] = c.GetCode() + " DESC"
+		case ICol:
+			codes[i] = c.GetCode()
+		default:
+			codes[i] = fmt.Sprintf("%v", c)
+		}
+	}
+	return strings.Join(codes, ", ")
+}
+
+func windowClausesToSql(clauses []WindowClause) string {
+	var (
+		sorted = make([]WindowClause, len(clauses))
+		sqls   = make([]string, len(clauses))
+	)
+	copy(sorted, clauses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].kind < sorted[j].kind
+	})
+	for i, clause := range sorted {
+		sqls[i] = clause.sql
+	}
+	return strings.Join(sqls, " ")
+}
+
+// Over run as a window function instead of grouping
+//
+//	f.Amount.Sum().Over(preform.PartitionBy(f.UserId), preform.OrderBy(f.CreatedAt), preform.RowsBetween(preform.UnboundedPreceding, preform.CurrentRow)).SetAlias("RunningTotal")
+func (a *AggregateCol[T]) Over(clauses ...WindowClause) iAggregateCol {
+	a.over = append([]WindowClause{}, clauses...)
+	return a
+}
+
+func (a Column[T]) RowNumber() iAggregateCol {
+	return &AggregateCol[int64]{ICol: &a, Aggregator: dialect.WinRowNumber, alias: a.Alias(), dialect: a.factory.Db().dialect}
+}
+
+func (a Column[T]) Rank() iAggregateCol {
+	return &AggregateCol[int64]{ICol: &a, Aggregator: dialect.WinRank, alias: a.Alias(), dialect: a.factory.Db().dialect}
+}
+
+func (a Column[T]) DenseRank() iAggregateCol {
+	return &AggregateCol[int64]{ICol: &a, Aggregator: dialect.WinDenseRank, alias: a.Alias(), dialect: a.factory.Db().dialect}
+}
+
+// iNullAggregator window functions returning NULL for missing rows, scanned as Null of the column type
+type iNullAggregator interface {
+	nullAggregate(col ICol, fn preformShare.Aggregator, d preformShare.IDialect, params ...any) iAggregateCol
+}
+
+func (a Column[T]) nullAggregate(col ICol, fn preformShare.Aggregator, d preformShare.IDialect, params ...any) iAggregateCol {
+	return &AggregateCol[preformTypes.Null[T]]{ICol: col, Aggregator: fn, body: col.GetCode(), params: params, alias: col.Alias(), dialect: d}
+}
+
+// Lag value of the row offset rows before, must be used with Over, scanned as Null[T] since the first rows have none
+func (a Column[T]) Lag(offset int) iAggregateCol {
+	return a.nullAggregate(&a, dialect.WinLag, a.factory.Db().dialect, offset)
+}
+
+// Lead value of the row offset rows after, must be used with Over, scanned as Null[T] since the last rows have none
+func (a Column[T]) Lead(offset int) iAggregateCol {
+	return a.nullAggregate(&a, dialect.WinLead, a.factory.Db().dialect, offset)
+}
+
+func (c ColumnWrap[C]) RowNumber() iAggregateCol {
+	return &AggregateCol[int64]{ICol: &c, Aggregator: dialect.WinRowNumber, alias: c.alias, dialect: c.altFactory.Db().dialect}
+}
+
+func (c ColumnWrap[C]) Rank() iAggregateCol {
+	return &AggregateCol[int64]{ICol: &c, Aggregator: dialect.WinRank, alias: c.alias, dialect: c.altFactory.Db().dialect}
+}
+
+func (c ColumnWrap[C]) DenseRank() iAggregateCol {
+	return &AggregateCol[int64]{ICol: &c, Aggregator: dialect.WinDenseRank, alias: c.alias, dialect: c.altFactory.Db().dialect}
+}
+
+func (c ColumnWrap[C]) nullAggregate(col ICol, fn preformShare.Aggregator, d preformShare.IDialect, params ...any) iAggregateCol {
+	if n, ok := any(c.col).(iNullAggregator); ok {
+		return n.nullAggregate(col, fn, d, params...)
+	}
+	return &AggregateCol[C]{ICol: col, Aggregator: fn, body: col.GetCode(), params: params, alias: col.Alias(), dialect: d}
+}
+
+func (c ColumnWrap[C]) Lag(offset int) iAggregateCol {
+	return c.nullAggregate(&c, dialect.WinLag, c.altFactory.Db().dialect, offset)
+}
+
+func (c ColumnWrap[C]) Lead(offset int) iAggregateCol {
+	return c.nullAggregate(&c, dialect.WinLead, c.altFactory.Db().dialect, offset)
+}
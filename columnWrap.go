@@ -14,6 +14,7 @@ type IColWrap interface {
 	properties() (isArray bool, isPtr bool, isPk bool, isAuto bool)
 	SetAlias(alias string) ICol
 	QueryFactory() IQuery
+	rawPtrScanner() (v any, toScanner func(*any) any, direct bool)
 	unwrapPtr(any) any
 	unwrapPtrForInsert(any) any //care default value
 	unwrapPtrForUpdate(any) any
@@ -42,6 +43,11 @@ type IColWrap interface {
 	JsonAgg() iAggregateCol
 	ArrayAgg() iAggregateCol
 	GroupConcat(splitter string) iAggregateCol
+	RowNumber() iAggregateCol
+	Rank() iAggregateCol
+	DenseRank() iAggregateCol
+	Lag(offset int) iAggregateCol
+	Lead(offset int) iAggregateCol
 	IsSame(col ICol) bool
 }
 
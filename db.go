@@ -14,6 +14,7 @@ import (
 	"math/rand"
 	"reflect"
 	"strconv"
+	"sync/atomic"
 )
 
 type db struct {
@@ -29,6 +30,10 @@ type db struct {
 	queryTraceScan      func(ctx context.Context, query string, args ...interface{}) (rows IRows, err error)
 	prepareTrace        func(ctx context.Context, query string) (IStmt, error)
 	tracer              ITracer
+	retryPolicy         *RetryPolicy
+	replicas            atomic.Pointer[[]*replicaDb]
+	replicaSeq          uint32
+	replicaHealthCancel context.CancelFunc
 }
 type DB interface {
 	preformShare.QueryRunner
@@ -63,14 +68,27 @@ func DbFromNative(d *sql.DB, queryRunner ...preformShare.QueryRunner) *db {
 				dd.dialect = dialect.NewSqliteDialect()
 			case "postgres":
 				dd.dialect = dialect.NewPostgresqlDialect()
+			case "cockroachdb":
+				dd.dialect = dialect.NewCockroachdbDialect()
+			case "yugabytedb":
+				dd.dialect = dialect.NewYugabytedbDialect()
 			case "mysql":
 				dd.sqPlaceholderFormat = squirrel.Question
 				dd.sqStmtBuilder = squirrel.StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(squirrel.Question)
 				dd.dialect = dialect.NewMysqlDialect()
 			case "clickhouse":
 				dd.dialect = dialect.NewClickhouseDialect()
+			case "sqlserver", "mssql":
+				dd.sqPlaceholderFormat = squirrel.AtP
+				dd.sqStmtBuilder = squirrel.StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(squirrel.AtP)
+				dd.dialect = dialect.NewMssqlDialect()
+			case "oracle", "godror":
+				dd.sqPlaceholderFormat = squirrel.Colon
+				dd.sqStmtBuilder = squirrel.StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(squirrel.Colon)
+				dd.dialect = dialect.NewOracleDialect()
+			case "duckdb":
+				dd.dialect = dialect.NewDuckdbDialect()
 			}
-			dd.DB = sqlx.NewDb(d, dd.driverName)
 		} else {
 			//some drivers are not exported
 			switch reflect.TypeOf(d.Driver()).String() {
@@ -79,7 +97,7 @@ func DbFromNative(d *sql.DB, queryRunner ...preformShare.QueryRunner) *db {
 				dd.dialect = dialect.NewSqliteDialect()
 			case "*pq.Driver", "*stdlib.Driver":
 				dd.driverName = "postgres"
-				dd.dialect = dialect.NewPostgresqlDialect()
+				dd.dialect = dialect.NewPostgresqlDialect() //cockroachdb and yugabytedb use the same drivers, see DetectDialect
 			case "*mysql.MySQLDriver":
 				dd.driverName = "mysql"
 				dd.sqPlaceholderFormat = squirrel.Question
@@ -88,10 +106,23 @@ func DbFromNative(d *sql.DB, queryRunner ...preformShare.QueryRunner) *db {
 			case "*clickhouse.stdDriver":
 				dd.driverName = "clickhouse"
 				dd.dialect = dialect.NewClickhouseDialect()
+			case "*mssql.Driver":
+				dd.driverName = "sqlserver"
+				dd.sqPlaceholderFormat = squirrel.AtP
+				dd.sqStmtBuilder = squirrel.StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(squirrel.AtP)
+				dd.dialect = dialect.NewMssqlDialect()
+			case "*go_ora.OracleDriver", "*godror.drv":
+				dd.driverName = "oracle"
+				dd.sqPlaceholderFormat = squirrel.Colon
+				dd.sqStmtBuilder = squirrel.StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(squirrel.Colon)
+				dd.dialect = dialect.NewOracleDialect()
+			case "duckdb.Driver":
+				dd.driverName = "duckdb"
+				dd.dialect = dialect.NewDuckdbDialect()
 			}
-			dd.DB = sqlx.NewDb(d, dd.driverName)
-			dd.QueryRunner = queryRunnerWrap{dd.DB}
 		}
+		dd.DB = sqlx.NewDb(d, dd.driverName)
+		dd.QueryRunner = queryRunnerWrap{dd.DB}
 	}
 	if len(queryRunner) != 0 && queryRunner[0] != nil {
 		dd.QueryRunner = queryRunner[0]
@@ -104,6 +135,35 @@ func DbFromNative(d *sql.DB, queryRunner ...preformShare.QueryRunner) *db {
 	return dd
 }
 
+// DetectDialect switch to the cockroachdb or yugabytedb dialect, or the mysql 8.0.19+ upsert by asking the server, postgres and mysql drivers only
+// opt-in as it queries the db, call before use as the dialect is shared by replicas without locking
+func (d *db) DetectDialect(ctx context.Context) error {
+	var (
+		detected preformShare.IDialect
+		err      error
+	)
+	if d.DB == nil {
+		return nil
+	}
+	switch d.driverName {
+	case "postgres":
+		detected, err = dialect.DetectPostgresqlDialect(ctx, d.DB.DB)
+	case "mysql":
+		detected, err = dialect.DetectMysqlDialect(ctx, d.DB.DB)
+	default:
+		return nil
+	}
+	if err != nil {
+		d.Error("detect dialect", err)
+		return err
+	}
+	d.dialect = detected
+	for _, r := range d.replicaList() {
+		r.dialect = detected
+	}
+	return nil
+}
+
 func (d *db) Dialect() preformShare.IDialect {
 	return d.dialect
 }
@@ -141,6 +201,7 @@ func (d *db) SetTracer(tracerNilToOff ITracer) {
 		} else {
 			d.QueryRunner = queryRunnerWrap{d.DB}
 		}
+		d.tracer = nil
 		d.errorLogger = errorLog
 		d.queryTraceScan = d._queryTraceScan
 		d.prepareTrace = d._prepareTrace
@@ -155,12 +216,17 @@ func (d *db) SetTracer(tracerNilToOff ITracer) {
 		healthCtx, d.healthCtxCancel = context.WithCancel(d.ctx)
 		go tracerNilToOff.HealthLoop(healthCtx, d)
 	}
+	for _, r := range d.replicaList() {
+		r.SetTracer(tracerNilToOff)
+	}
 }
 
 func (d *db) Db() *db {
 	return d
 }
 
+// BeginTx nested by savepoint if ctx already carries a tx of d, see Tx.BeginTx
+// the policy of ContextWithRetryPolicy or SetRetryPolicy is kept as Tx.RetryPolicy
 func (d *db) BeginTx(ctx context.Context, opt ...*sql.TxOptions) (*Tx, error) {
 	var (
 		tx  *sqlx.Tx
@@ -168,6 +234,9 @@ func (d *db) BeginTx(ctx context.Context, opt ...*sql.TxOptions) (*Tx, error) {
 		dwt *dbWithTracer
 		ok  bool
 	)
+	if outer := txOfDb(ctx, d); outer != nil {
+		return outer.BeginTx(ctx, opt...)
+	}
 	if len(opt) == 0 {
 		tx, err = d.DB.BeginTxx(ctx, nil)
 	} else {
@@ -178,11 +247,11 @@ func (d *db) BeginTx(ctx context.Context, opt ...*sql.TxOptions) (*Tx, error) {
 	}
 	if dwt, ok = d.QueryRunner.(*dbWithTracer); ok {
 		dwt = &dbWithTracer{QueryRunner: queryRunnerWrap{tx}, driverName: d.driverName, tracer: d.tracer, txId: strconv.FormatInt(rand.Int63(), 36)}
-		return &Tx{QueryRunner: queryRunnerWrap{dwt}, tx: tx, db: d, queryTraceScan: dwt.QueryTraceScan, prepareTrace: dwt.PrepareTrace}, nil
+		return &Tx{QueryRunner: queryRunnerWrap{dwt}, tx: tx, db: d, ctx: ctx, queryTraceScan: dwt.QueryTraceScan, prepareTrace: dwt.PrepareTrace, retryPolicy: d.retryPolicyOf(ctx)}, nil
 	} else if _, ok := d.QueryRunner.BaseRunner().(preformShare.ITestQueryRunner); ok {
-		return &Tx{QueryRunner: d.QueryRunner, tx: tx, db: d, queryTraceScan: d.queryTraceScan, prepareTrace: d.prepareTrace}, nil
+		return &Tx{QueryRunner: d.QueryRunner, tx: tx, db: d, ctx: ctx, queryTraceScan: d.queryTraceScan, prepareTrace: d.prepareTrace, retryPolicy: d.retryPolicyOf(ctx)}, nil
 	} else {
-		return &Tx{QueryRunner: queryRunnerWrap{tx}, tx: tx, db: d, prepareTrace: d.prepareTrace, queryTraceScan: d.queryTraceScan}, nil
+		return &Tx{QueryRunner: queryRunnerWrap{tx}, tx: tx, db: d, ctx: ctx, prepareTrace: d.prepareTrace, queryTraceScan: d.queryTraceScan, retryPolicy: d.retryPolicyOf(ctx)}, nil
 	}
 }
 
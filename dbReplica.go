@@ -0,0 +1,191 @@
+package preform
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/jmoiron/sqlx"
+	"sync/atomic"
+	"time"
+)
+
+type replicaDb struct {
+	*db
+	healthy atomic.Bool
+}
+
+// SetReplicas SelectQuery reads go to conns round robin, except in Tx, with Primary() or row lock, no conns to turn off
+// replicas share the dialect, query runner and tracer of d, a custom query runner must implement IReplicaQueryRunner, safe to call while reading
+func (d *db) SetReplicas(conns ...*sql.DB) {
+	var (
+		replicas = make([]*replicaDb, len(conns))
+	)
+	for i, conn := range conns {
+		r := d.replicaOf(conn)
+		r.healthy.Store(true)
+		if d.tracer != nil {
+			r.SetTracer(d.tracer)
+		}
+		replicas[i] = r
+	}
+	if old := d.replicas.Swap(&replicas); old != nil {
+		for _, r := range *old {
+			if r.healthCtxCancel != nil {
+				r.healthCtxCancel()
+			}
+		}
+	}
+}
+
+// IReplicaQueryRunner a custom QueryRunner of the primary implements it to be rebuilt on each replica conn, e.g. sharing the cache
+type IReplicaQueryRunner interface {
+	ReplicaOf(conn preformShare.DbQueryRunner) preformShare.QueryRunner
+}
+
+// replicaOf a db on conn with the dialect, placeholders and query runner of d instead of detecting them again, the tracer is set on top by SetReplicas
+func (d *db) replicaOf(conn *sql.DB) *replicaDb {
+	var (
+		runner = d.QueryRunner
+	)
+	for dwt, ok := runner.(*dbWithTracer); ok; dwt, ok = runner.(*dbWithTracer) {
+		runner = dwt.QueryRunner
+	}
+	rr, ok := runner.(IReplicaQueryRunner)
+	if !ok {
+		panic(fmt.Errorf("replica: query runner %T does not implement IReplicaQueryRunner", runner))
+	}
+	r := &replicaDb{db: &db{
+		DB:                  sqlx.NewDb(conn, d.driverName),
+		dialect:             d.dialect,
+		ctx:                 d.ctx,
+		sqPlaceholderFormat: d.sqPlaceholderFormat,
+		sqStmtBuilder:       d.sqStmtBuilder,
+		driverName:          d.driverName,
+		errorLogger:         errorLog,
+	}}
+	r.QueryRunner = rr.ReplicaOf(r.DB)
+	r.queryTraceScan = r._queryTraceScan
+	r.prepareTrace = r._prepareTrace
+	return r
+}
+
+// replicaList nil if no replicas
+func (d *db) replicaList() []*replicaDb {
+	if replicas := d.replicas.Load(); replicas != nil {
+		return *replicas
+	}
+	return nil
+}
+
+// SetReplicaHealthCheck poll replicas every interval, replicas failing ping or lagging behind more than maxLag are skipped until they recover
+// maxLag 0 ping only, 0 interval turn it off
+// it runs its own loop instead of ITracer.HealthLoop, which only logs stats, returns nothing and is off without a tracer
+func (d *db) SetReplicaHealthCheck(interval, maxLag time.Duration) {
+	if d.replicaHealthCancel != nil {
+		d.replicaHealthCancel()
+		d.replicaHealthCancel = nil
+	}
+	if interval == 0 {
+		for _, r := range d.replicaList() {
+			r.healthy.Store(true)
+		}
+		return
+	}
+	var healthCtx context.Context
+	healthCtx, d.replicaHealthCancel = context.WithCancel(d.ctx)
+	go d.replicaHealthLoop(healthCtx, interval, maxLag)
+}
+
+func (d *db) replicaHealthLoop(ctx context.Context, interval, maxLag time.Duration) {
+	var (
+		ticker = time.NewTicker(interval)
+	)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, r := range d.replicaList() {
+				err := d.checkReplica(ctx, r, interval, maxLag)
+				if err != nil {
+					d.Error("replica health", err)
+				}
+				r.healthy.Store(err == nil)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *db) checkReplica(ctx context.Context, r *replicaDb, timeout, maxLag time.Duration) error {
+	var (
+		lag float64
+	)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := r.DB.PingContext(ctx); err != nil {
+		return err
+	}
+	if maxLag == 0 {
+		return nil
+	}
+	q, err := d.dialect.ReplicaLag()
+	if err != nil {
+		return err
+	}
+	if err = r.DB.QueryRowContext(ctx, q).Scan(&lag); err != nil {
+		return err
+	}
+	if l := time.Duration(lag * float64(time.Second)); l > maxLag {
+		return fmt.Errorf("replica lagging %v behind", l)
+	}
+	return nil
+}
+
+// reader a healthy replica round robin, d if none
+func (d *db) reader() *db {
+	var (
+		replicas = d.replicaList()
+		l        = uint32(len(replicas))
+	)
+	if l == 0 {
+		return d
+	}
+	seq := atomic.AddUint32(&d.replicaSeq, 1)
+	for i := uint32(0); i < l; i++ {
+		if r := replicas[(seq+i)%l]; r.healthy.Load() {
+			return r.db
+		}
+	}
+	return d
+}
+
+type primaryCtxKey struct{}
+
+// ContextWithPrimary selects with the returned ctx read from the primary, e.g. to read your own writes
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+func isPrimaryCtx(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	primary, _ := ctx.Value(primaryCtxKey{}).(bool)
+	return primary
+}
+
+// Primary read from the primary even if replicas are set, eager loads follow
+func (b *SelectQuery[B]) Primary() *SelectQuery[B] {
+	b.primary = true
+	return b
+}
+
+// runner the replica for reads if any
+func (b SelectQuery[B]) runner() DB {
+	if d, ok := b.db.(*db); ok && !b.primary && b.lock == nil {
+		return d.reader()
+	}
+	return b.db
+}
@@ -0,0 +1,53 @@
+package preform
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy run WithTx again if the error is retryable by the dialect, e.g. serialization failure or deadlock
+type RetryPolicy struct {
+	MaxAttempts int           //including the first run, 1 or less never retry
+	Backoff     time.Duration //wait before the 2nd attempt, doubled per attempt
+	MaxBackoff  time.Duration //0 is unlimited
+	Jitter      float64       //0-1, up to this fraction of the wait is added randomly
+}
+
+// SetRetryPolicy default policy of WithTx and BeginTx, nil to turn off
+func (d *db) SetRetryPolicy(policyNilToOff *RetryPolicy) {
+	d.retryPolicy = policyNilToOff
+}
+
+type retryPolicyCtxKey struct{}
+
+// ContextWithRetryPolicy WithTx and BeginTx with the returned ctx take policy instead of the default, nil never retry
+func ContextWithRetryPolicy(ctx context.Context, policyNilToOff *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyCtxKey{}, policyNilToOff)
+}
+
+// retryPolicyOf the policy carried by ctx if any, the default of d otherwise
+func (d *db) retryPolicyOf(ctx context.Context) *RetryPolicy {
+	if ctx != nil {
+		if policy, ok := ctx.Value(retryPolicyCtxKey{}).(*RetryPolicy); ok {
+			return policy
+		}
+	}
+	return d.retryPolicy
+}
+
+func (p RetryPolicy) wait(attempt int) time.Duration {
+	var (
+		wait = p.Backoff
+	)
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff != 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 && wait > 0 {
+		wait += time.Duration(rand.Int63n(int64(float64(wait)*p.Jitter) + 1))
+	}
+	return wait
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	sql "database/sql"
 	preformShare "github.com/go-preform/preform/share"
+	"time"
 )
 
 type ITracer interface {
@@ -13,3 +14,8 @@ type ITracer interface {
 	HealthLoop(ctx context.Context, db DB)
 	SetLv(logLv preformShare.LogLv)
 }
+
+// IRetryTracer optional for ITracer, WithTx failed with a retryable error and runs again after wait
+type IRetryTracer interface {
+	Retry(ctx context.Context, driver string, attempt int, wait time.Duration, err error)
+}
@@ -2,16 +2,31 @@ package preform
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	preformShare "github.com/go-preform/preform/share"
 	"github.com/jmoiron/sqlx"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrorTxDone          = errors.New("transaction or savepoint has already been committed or rolled back")
+	ErrorNestedTxOptions = errors.New("nested transaction is a savepoint, sql.TxOptions can not be applied")
 )
 
 type Tx struct {
 	preformShare.QueryRunner
 	tx             *sqlx.Tx
 	db             *db
+	ctx            context.Context
 	queryTraceScan func(ctx context.Context, query string, args ...interface{}) (rows IRows, err error)
 	prepareTrace   func(ctx context.Context, query string) (IStmt, error)
+	savepoint      string //not empty if nested by BeginTx
+	savepointSeq   *uint32
+	retryPolicy    *RetryPolicy
+	done           bool
 }
 
 func (t *Tx) GetDialect() preformShare.IDialect {
@@ -22,6 +37,11 @@ func (t *Tx) Db() *db {
 	return t.db
 }
 
+// RetryPolicy the policy of the ctx passed to BeginTx or the default, for Retry
+func (t *Tx) RetryPolicy() *RetryPolicy {
+	return t.retryPolicy
+}
+
 func (t *Tx) Error(msg string, err error) {
 	t.db.errorLogger(t.db.driverName, msg, err)
 }
@@ -34,10 +54,208 @@ func (t *Tx) PrepareTrace(ctx context.Context, query string) (IStmt, error) {
 	return t.prepareTrace(ctx, query)
 }
 
+// Commit release the savepoint if nested
 func (t *Tx) Commit() error {
+	if t.savepoint != "" {
+		if t.done {
+			return ErrorTxDone
+		}
+		t.done = true
+		return t.Release(t.savepoint)
+	}
 	return t.tx.Commit()
 }
 
+// Rollback roll back to the savepoint if nested
 func (t *Tx) Rollback() error {
+	if t.savepoint != "" {
+		if t.done {
+			return ErrorTxDone
+		}
+		t.done = true
+		return t.RollbackTo(t.savepoint)
+	}
 	return t.tx.Rollback()
 }
+
+// BeginTx nested transaction by savepoint, Commit releases and Rollback rolls back to it
+// opt is only for the signature of db.BeginTx, non nil returns ErrorNestedTxOptions
+func (t *Tx) BeginTx(ctx context.Context, opt ...*sql.TxOptions) (*Tx, error) {
+	var (
+		nested = *t
+	)
+	if len(opt) != 0 && opt[0] != nil {
+		return nil, ErrorNestedTxOptions
+	}
+	if t.savepointSeq == nil {
+		t.savepointSeq = new(uint32)
+		nested.savepointSeq = t.savepointSeq
+	}
+	nested.ctx = ctx
+	nested.done = false
+	nested.savepoint = fmt.Sprintf("preform_sp_%d", atomic.AddUint32(t.savepointSeq, 1))
+	if err := nested.Savepoint(nested.savepoint); err != nil {
+		return nil, err
+	}
+	return &nested, nil
+}
+
+// Savepoint rendered by dialect, not supported by clickhouse
+func (t *Tx) Savepoint(name string) error {
+	q, _, _, err := t.db.dialect.Savepoint(name)
+	if err != nil {
+		return err
+	}
+	return t.execSavepoint(q)
+}
+
+// RollbackTo undo statements after the savepoint, the savepoint is kept
+func (t *Tx) RollbackTo(name string) error {
+	_, q, _, err := t.db.dialect.Savepoint(name)
+	if err != nil {
+		return err
+	}
+	return t.execSavepoint(q)
+}
+
+// Release
+func (t *Tx) Release(name string) error {
+	_, _, q, err := t.db.dialect.Savepoint(name)
+	if err != nil {
+		return err
+	}
+	return t.execSavepoint(q)
+}
+
+// Retry run fn again in t after rolling back to the restart savepoint of the dialect, the cockroachdb retry protocol
+// t must be fresh as the savepoint has to be the first statement, the savepoint is released once fn succeeds
+// policy nil never retry, t.RetryPolicy() for the one of BeginTx, ErrorNotSupport if the dialect retries by a new transaction, see WithTxRetry
+func (t *Tx) Retry(policy *RetryPolicy, fn func(ctx context.Context) error) (err error) {
+	name, err := t.db.dialect.RestartSavepoint()
+	if err != nil {
+		return err
+	}
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = t.db.ctx
+	}
+	if err = t.Savepoint(name); err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		if err = fn(ContextWithTx(ctx, t)); err == nil {
+			if err = t.Release(name); err == nil {
+				return nil
+			}
+		}
+		if policy == nil || attempt >= policy.MaxAttempts || !t.db.dialect.IsRetryable(err) {
+			return err
+		}
+		wait := policy.wait(attempt)
+		t.db.traceRetry(ctx, attempt, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		if rollbackErr := t.RollbackTo(name); rollbackErr != nil {
+			return rollbackErr
+		}
+	}
+}
+
+func (t *Tx) execSavepoint(q string) error {
+	if q == "" {
+		return nil //mssql has no release
+	}
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = t.db.ctx
+	}
+	_, err := t.ExecContext(ctx, q)
+	return err
+}
+
+type txCtxKey struct{}
+
+// ContextWithTx queries with the returned ctx run in tx, see WithTx
+func ContextWithTx(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txCtxKey{}, tx)
+}
+
+// TxFromCtx nil if ctx is not in a transaction
+func TxFromCtx(ctx context.Context) *Tx {
+	if ctx == nil {
+		return nil
+	}
+	tx, _ := ctx.Value(txCtxKey{}).(*Tx)
+	return tx
+}
+
+// txOfDb the tx in ctx if it belongs to d
+func txOfDb(ctx context.Context, d *db) *Tx {
+	if tx := TxFromCtx(ctx); tx != nil && tx.db == d {
+		return tx
+	}
+	return nil
+}
+
+// WithTx run fn in a transaction carried by ctx, selects with Ctx(ctx), Insert UpdateByPk DeleteByPk with EditConfig.Ctx and builders with Ctx(ctx) pick it up
+// commit if fn returns nil, rollback on error or panic, nested calls reuse the outer transaction
+// retried by the policy of ContextWithRetryPolicy or SetRetryPolicy, fn must be safe to run again
+func (d *db) WithTx(ctx context.Context, fn func(ctx context.Context) error, opt ...*sql.TxOptions) error {
+	return d.WithTxRetry(ctx, d.retryPolicyOf(ctx), fn, opt...)
+}
+
+// WithTxRetry WithTx by policy instead of the default one, nil never retry
+// nested calls are not retried, the error goes up to the outermost one
+// dialects with a restart savepoint retry in the same transaction by Tx.Retry
+func (d *db) WithTxRetry(ctx context.Context, policy *RetryPolicy, fn func(ctx context.Context) error, opt ...*sql.TxOptions) (err error) {
+	if ctx == nil {
+		ctx = d.ctx
+	}
+	if txOfDb(ctx, d) != nil {
+		return fn(ctx)
+	}
+	if _, err = d.dialect.RestartSavepoint(); err == nil && policy != nil {
+		return d.withTx(ctx, func(ctx context.Context) error {
+			return TxFromCtx(ctx).Retry(policy, fn)
+		}, opt...)
+	}
+	for attempt := 1; ; attempt++ {
+		if err = d.withTx(ctx, fn, opt...); err == nil || policy == nil || attempt >= policy.MaxAttempts || !d.dialect.IsRetryable(err) {
+			return err
+		}
+		wait := policy.wait(attempt)
+		d.traceRetry(ctx, attempt, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (d *db) traceRetry(ctx context.Context, attempt int, wait time.Duration, err error) {
+	if tracer, ok := d.tracer.(IRetryTracer); ok {
+		tracer.Retry(ctx, d.driverName, attempt, wait, err)
+	}
+}
+
+func (d *db) withTx(ctx context.Context, fn func(ctx context.Context) error, opt ...*sql.TxOptions) (err error) {
+	tx, err := d.BeginTx(ctx, opt...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(ContextWithTx(ctx, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
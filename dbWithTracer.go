@@ -63,6 +63,9 @@ func (d *dbWithTracer) InsertAndReturnAutoId(ctx context.Context, lastIdMethod p
 			return 0, err
 		}
 		return res.LastInsertId()
+	} else if lastIdMethod == dialect.LastInsertIdMethodByOutParam {
+		_, err = d.ExecContext(ctx, query, append(args, sql.Out{Dest: &lastId})...)
+		return
 	} else {
 		ctx, end := d.tracer.TraceExec(ctx, d.driverName, query, d.txId, args...)
 		err = d.QueryRunner.QueryRowContext(ctx, query, args...).Scan(&lastId)
@@ -2,6 +2,7 @@ package preform
 
 import (
 	"context"
+	"database/sql"
 	"github.com/go-preform/preform/dialect"
 	preformShare "github.com/go-preform/preform/share"
 )
@@ -17,6 +18,9 @@ func (d queryRunnerWrap) InsertAndReturnAutoId(ctx context.Context, lastIdMethod
 			return 0, err
 		}
 		return res.LastInsertId()
+	} else if lastIdMethod == dialect.LastInsertIdMethodByOutParam {
+		_, err = d.ExecContext(ctx, query, append(args, sql.Out{Dest: &lastId})...)
+		return
 	} else {
 		err = d.QueryRowContext(ctx, query, args...).Scan(&lastId)
 		return
@@ -30,3 +34,7 @@ func (d queryRunnerWrap) BaseRunner() preformShare.DbQueryRunner {
 func (d queryRunnerWrap) RelatedFactory([]preformShare.IQueryFactory) preformShare.QueryRunner {
 	return d
 }
+
+func (d queryRunnerWrap) ReplicaOf(conn preformShare.DbQueryRunner) preformShare.QueryRunner {
+	return queryRunnerWrap{conn}
+}
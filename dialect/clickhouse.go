@@ -22,6 +22,17 @@ func NewClickhouseDialect() *clickhouseDialect {
 	}}
 }
 
+// Aggregate lag and lead of clickhouse only reach rows in the frame, Lead needs a frame up to UNBOUNDED FOLLOWING
+func (d clickhouseDialect) Aggregate(fn preformShare.Aggregator, body any, params ...any) squirrel.Sqlizer {
+	switch fn {
+	case WinLag:
+		fn = "lagInFrame"
+	case WinLead:
+		fn = "leadInFrame"
+	}
+	return d.basicSqlDialect.Aggregate(fn, body, params...)
+}
+
 func (d clickhouseDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
 	var (
 		schemes               []*preformShare.Scheme
@@ -189,6 +200,15 @@ func (d clickhouseDialect) getTableDetails(db *sql.DB, tableByName map[string]*p
 
 }
 
+// SetOperation UNION needs an explicit mode, INTERSECT and EXCEPT keep duplicates unless DISTINCT
+func (d clickhouseDialect) SetOperation(op string) (string, error) {
+	switch op {
+	case "UNION", "INTERSECT", "EXCEPT":
+		return op + " DISTINCT", nil
+	}
+	return op, nil
+}
+
 func (d clickhouseDialect) UpdateSqlizer(builder preformShare.UpdateBuilder) (string, []any, error) {
 	q, args, err := builder.ToSql()
 	if err != nil {
@@ -214,6 +234,11 @@ func (d clickhouseDialect) DeleteSqlizer(builder preformShare.DeleteBuilder) (st
 	//return fmt.Sprintf("ALTER TABLE %s DELETE %s", parts[0][11:], strings.Join(parts[1:], " WHERE ")), args, nil
 }
 
+// Upsert no conflict on clickhouse, ReplacingMergeTree keeps the last inserted row of the same sorting key after merge, query with FINAL to get the merged result
+func (d clickhouseDialect) Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, err error) {
+	return nil, LastInsertIdMethodNone, nil
+}
+
 func clickhouseCalcGoType(col *preformShare.Column) (enums map[string][]string) {
 	var (
 		t        = col.Type
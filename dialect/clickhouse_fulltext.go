@@ -0,0 +1,29 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"strings"
+)
+
+// FullTextMatch all words of text as tokens, which can use tokenbf_v1 indexes
+func (d clickhouseDialect) FullTextMatch(col preformShare.ICol, text string) (query string, args []any, err error) {
+	var (
+		words = strings.Fields(text)
+		conds = make([]string, len(words))
+	)
+	if len(words) == 0 {
+		return "1 = 1", nil, nil
+	}
+	args = make([]any, len(words))
+	for i, word := range words {
+		conds[i] = fmt.Sprintf("hasTokenCaseInsensitive(%s, ?)", col.GetCode())
+		args[i] = word
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// FullTextRank ngram similarity from 0 to 1, which can use ngrambf_v1 indexes
+func (d clickhouseDialect) FullTextRank(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return fmt.Sprintf("ngramSearchCaseInsensitive(%s, ?)", col.GetCode()), []any{text}, nil
+}
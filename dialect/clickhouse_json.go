@@ -0,0 +1,48 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+	"strings"
+)
+
+func (d clickhouseDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	var fn string
+	switch kind {
+	case reflect.String:
+		fn = "JSONExtractString"
+	case reflect.Bool:
+		fn = "JSONExtractBool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fn = "JSONExtractInt"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fn = "JSONExtractUInt"
+	case reflect.Float32, reflect.Float64:
+		fn = "JSONExtractFloat"
+	default:
+		fn = "JSONExtractRaw"
+	}
+	return fmt.Sprintf("%s(%s)", fn, strings.Join(append([]string{col.GetCode()}, clickhouseJsonKeys(path)...), ", ")), nil
+}
+
+func (d clickhouseDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("JSONHas(%s)", strings.Join(append([]string{col.GetCode()}, clickhouseJsonKeys(path)...), ", ")), nil, nil
+}
+
+// clickhouseJsonKeys array indexes of JSONExtract are 1-based
+func clickhouseJsonKeys(path []string) []string {
+	var (
+		keys = make([]string, len(path))
+		idx  int
+	)
+	for i, key := range path {
+		if jsonPathIdxRx.MatchString(key) {
+			fmt.Sscanf(key, "%d", &idx)
+			keys[i] = fmt.Sprintf("%d", idx+1)
+		} else {
+			keys[i] = sqlStringLiteral(key)
+		}
+	}
+	return keys
+}
@@ -16,4 +16,10 @@ const (
 	AggGroupConcat   preformShare.Aggregator = "GROUP_CONCAT"
 	AggJson          preformShare.Aggregator = "JSON_AGG"
 	AggArray         preformShare.Aggregator = "ARRAY_AGG"
+	//window functions, used with Over
+	WinRowNumber preformShare.Aggregator = "ROW_NUMBER"
+	WinRank      preformShare.Aggregator = "RANK"
+	WinDenseRank preformShare.Aggregator = "DENSE_RANK"
+	WinLag       preformShare.Aggregator = "LAG"
+	WinLead      preformShare.Aggregator = "LEAD"
 )
@@ -0,0 +1,302 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"strings"
+)
+
+type duckdbDialect struct {
+	basicSqlDialect
+}
+
+func NewDuckdbDialect() *duckdbDialect {
+	return &duckdbDialect{basicSqlDialect: basicSqlDialect{
+		quoteTpl:           `"%s"`,
+		lastInsertIdMethod: LastInsertIdMethodBySuffix,
+		lastInsertIdSuffix: func(col string) squirrel.Sqlizer {
+			return squirrel.Expr(fmt.Sprintf(`RETURNING "%s"`, col))
+		},
+	}}
+}
+
+func (d duckdbDialect) Aggregate(fn preformShare.Aggregator, body any, params ...any) squirrel.Sqlizer {
+	var (
+		bodyStr string
+		args    []any
+	)
+	switch body.(type) {
+	case string:
+		bodyStr = body.(string)
+	case preformShare.ICol:
+		bodyStr = body.(preformShare.ICol).GetCode()
+	case squirrel.Sqlizer:
+		s := body.(squirrel.Sqlizer)
+		bodyStr, args, _ = s.ToSql()
+		bodyStr, args, _ = preformShare.NestSql(bodyStr, args)
+	}
+	switch fn {
+	case AggGroupConcat:
+		return squirrel.Expr(fmt.Sprintf("STRING_AGG(%s, ?)", bodyStr), append(args, params[0])...)
+	case AggCountDistinct:
+		return squirrel.Expr(fmt.Sprintf("COUNT(DISTINCT %s)", bodyStr), args...)
+	case AggJson:
+		return squirrel.Expr(fmt.Sprintf("JSON_GROUP_ARRAY(%s)", bodyStr), args...)
+	default:
+		//MEDIAN MODE STDDEV are built in
+		if l := len(params); l != 0 {
+			return squirrel.Expr(fmt.Sprintf("%s(%s%s)", fn, bodyStr, strings.Repeat(",?", l)), append(args, params...)...)
+		}
+		return squirrel.Expr(fmt.Sprintf("%s(%s)", fn, bodyStr), args...)
+	}
+}
+
+func (d duckdbDialect) ILike(col preformShare.ICol, v any) squirrel.Sqlizer {
+	return squirrel.ILike{col.GetCode(): v}
+}
+
+func (d duckdbDialect) Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, err error) {
+	suffix, err = d.onConflictDoUpdate(conflictCols, updateCols, "EXCLUDED")
+	return suffix, d.lastInsertIdMethod, err
+}
+
+func (d duckdbDialect) Returning(cols []string) (squirrel.Sqlizer, error) {
+	return d.returning(cols)
+}
+
+// IsRetryable duckdb is optimistic, concurrent writes to the same rows fail with a transaction conflict
+func (d duckdbDialect) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "TransactionContext Error") && strings.Contains(strings.ToLower(msg), "conflict")
+}
+
+// GetStructure empty for all schemas of the current database
+func (d duckdbDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
+	var (
+		schemes                          []*preformShare.Scheme
+		scheme                           *preformShare.Scheme
+		ok                               bool
+		schemaByName                     = make(map[string]*preformShare.Scheme)
+		schemaName, tableName, tableType string
+		table                            *preformShare.Table
+		tableByName                      = make(map[string]*preformShare.Table)
+		allSchemas                       = []string{}
+	)
+	schemaQ := squirrel.Select("table_schema", "table_name", "table_type").From("information_schema.tables").
+		Where("table_catalog = current_database()").PlaceholderFormat(squirrel.Dollar)
+	if len(schemasEmptyIsAll) != 0 {
+		schemaQ = schemaQ.Where(squirrel.Eq{"table_schema": schemasEmptyIsAll})
+	} else {
+		schemaQ = schemaQ.Where(squirrel.NotEq{"table_schema": []string{"information_schema", "pg_catalog"}})
+	}
+	rows, err := schemaQ.OrderBy("table_schema", "table_name").RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &tableType)
+		if err != nil {
+			panic(err)
+		}
+		if scheme, ok = schemaByName[schemaName]; !ok {
+			scheme = &preformShare.Scheme{Name: schemaName, Imports: map[string]struct{}{`"github.com/go-preform/preform/preformBuilder"`: {}}}
+			schemaByName[schemaName] = scheme
+			schemes = append(schemes, scheme)
+			allSchemas = append(allSchemas, schemaName)
+		}
+		table = &preformShare.Table{Name: tableName, Scheme: scheme, ColumnByName: make(map[string]*preformShare.Column), Imports: map[string]struct{}{}, ForeignKeys: map[string]*preformShare.ForeignKey{}, IsView: tableType == "VIEW"}
+		scheme.Tables = append(scheme.Tables, table)
+		tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)] = table
+	}
+	if len(allSchemas) != 0 {
+		d.getTableDetails(db, tableByName, allSchemas)
+	}
+	return schemes
+}
+
+func (d duckdbDialect) getTableDetails(db *sql.DB, tableByName map[string]*preformShare.Table, schemaNames []string) {
+	var (
+		schemaName, tableName, colName, dataType, isNullable, constraintType string
+		colDefault, colComment, fkName, fkTableName, fkColName               sql.NullString
+		pos                                                                  int64
+		ok                                                                   bool
+		table, fkTable                                                       *preformShare.Table
+		col, fkCol                                                           *preformShare.Column
+		schemas                                                              = map[string]struct{}{}
+		fk                                                                   *preformShare.ForeignKey
+	)
+	for _, schemaName = range schemaNames {
+		schemas[schemaName] = struct{}{}
+	}
+	rows, err := squirrel.Select("c.table_schema", "c.table_name", "c.column_name", "c.data_type", "c.is_nullable", "c.column_default", "dc.comment").
+		From("information_schema.columns c").
+		LeftJoin("duckdb_columns() dc ON dc.database_name = c.table_catalog AND dc.schema_name = c.table_schema AND dc.table_name = c.table_name AND dc.column_name = c.column_name").
+		Where("c.table_catalog = current_database()").Where(squirrel.Eq{"c.table_schema": schemaNames}).
+		OrderBy("c.table_schema", "c.table_name", "c.ordinal_position").
+		PlaceholderFormat(squirrel.Dollar).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &colName, &dataType, &isNullable, &colDefault, &colComment)
+		if err != nil {
+			panic(err)
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+			continue
+		}
+		if col, ok = table.ColumnByName[colName]; !ok {
+			col = &preformShare.Column{Name: colName, Table: table}
+			table.Columns = append(table.Columns, col)
+			table.ColumnByName[colName] = col
+		}
+		col.DefaultValue = colDefault
+		col.Nullable = isNullable == "YES"
+		col.IsAutoKey = strings.HasPrefix(colDefault.String, "nextval(") //sequence default
+		col.Type = dataType
+		col.Comment = colComment.String
+		duckdbCalcGoType(col)
+	}
+	_ = rows.Close()
+
+	//unnest of lists in different lengths pads with NULL, pk has no referenced columns
+	rows, err = squirrel.Select("schema_name", "table_name", "constraint_type", "constraint_name", "referenced_table",
+		"UNNEST(constraint_column_names)", "UNNEST(referenced_column_names)", "UNNEST(range(1, len(constraint_column_names) + 1))").
+		From("duckdb_constraints()").
+		Where("database_name = current_database()").
+		Where(squirrel.Eq{"constraint_type": []string{"PRIMARY KEY", "FOREIGN KEY"}, "schema_name": schemaNames}).
+		OrderBy("schema_name", "table_name", "constraint_index").
+		PlaceholderFormat(squirrel.Dollar).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &constraintType, &fkName, &fkTableName, &colName, &fkColName, &pos)
+		if err != nil {
+			panic(err)
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+			continue
+		}
+		if col, ok = table.ColumnByName[colName]; !ok {
+			continue
+		}
+		if constraintType == "PRIMARY KEY" {
+			col.IsPrimaryKey = true
+			col.PkPos = pos
+			continue
+		}
+		if fkTable = d.referencedTable(tableByName, schemaName, schemaNames, fkTableName.String); fkTable == nil {
+			continue
+		}
+		if fkCol, ok = fkTable.ColumnByName[fkColName.String]; !ok {
+			fkCol = &preformShare.Column{Name: fkColName.String, Table: fkTable}
+			fkTable.Columns = append(fkTable.Columns, fkCol)
+			fkTable.ColumnByName[fkColName.String] = fkCol
+		}
+		if !fkName.Valid {
+			fkName.String = fmt.Sprintf("%s_%s_fkey", tableName, fkTableName.String)
+		}
+		if fk, ok = table.ForeignKeys[fkName.String]; !ok {
+			fk = &preformShare.ForeignKey{Name: fkName.String}
+			table.ForeignKeys[fkName.String] = fk
+			col.ForeignKeys = append(col.ForeignKeys, fk)
+		}
+		fk.LocalKeys = append(fk.LocalKeys, col)
+		fk.ForeignKeys = append(fk.ForeignKeys, fkCol)
+	}
+	_ = rows.Close()
+
+	d.commentForeignKeys(tableByName, schemas)
+}
+
+// referencedTable duckdb_constraints has no schema of the referenced table, same schema first
+func (d duckdbDialect) referencedTable(tableByName map[string]*preformShare.Table, schemaName string, schemaNames []string, tableName string) *preformShare.Table {
+	if table, ok := tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; ok {
+		return table
+	}
+	for _, schemaName = range schemaNames {
+		if table, ok := tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; ok {
+			return table
+		}
+	}
+	return nil
+}
+
+func duckdbCalcGoType(col *preformShare.Column) {
+	var (
+		t = col.Type
+	)
+	if col.Nullable {
+		defer func() {
+			col.GoType = fmt.Sprintf("preformTypes.Null[%s]", col.GoType)
+			col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.IsScanner = true
+		}()
+	}
+	if strings.HasSuffix(t, "[]") {
+		defer func() {
+			col.GoType = fmt.Sprintf("preformTypes.Array[%s]", col.GoType)
+			col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.IsScanner = true
+		}()
+		t = t[:len(t)-2]
+	}
+	switch {
+	case t == "BIGINT":
+		col.GoType += "int64"
+	case t == "INTEGER":
+		col.GoType += "int32"
+	case t == "SMALLINT":
+		col.GoType += "int16"
+	case t == "TINYINT":
+		col.GoType += "int8"
+	case t == "UBIGINT":
+		col.GoType += "uint64"
+	case t == "UINTEGER":
+		col.GoType += "uint32"
+	case t == "USMALLINT":
+		col.GoType += "uint16"
+	case t == "UTINYINT":
+		col.GoType += "uint8"
+	case t == "DOUBLE":
+		col.GoType += "float64"
+	case t == "FLOAT":
+		col.GoType += "float32"
+	case t == "BOOLEAN":
+		col.GoType += "bool"
+	case t == "VARCHAR":
+		col.GoType += "string"
+	case t == "BLOB":
+		col.GoType += "[]byte"
+	case t == "DATE", strings.HasPrefix(t, "TIME"):
+		col.GoType += "time.Time"
+		col.Table.Scheme.Imports[`"time"`] = struct{}{}
+		col.Table.Imports[`"time"`] = struct{}{}
+	case t == "UUID":
+		col.GoType += "uuid.UUID"
+		col.Table.Scheme.Imports[`"github.com/satori/go.uuid"`] = struct{}{}
+		col.Table.Imports[`"github.com/satori/go.uuid"`] = struct{}{}
+		col.IsScanner = true
+	case t == "JSON":
+		col.GoType += "preformTypes.JsonRaw[any]"
+		col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+		col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+		col.IsScanner = true
+	case t == "HUGEINT", t == "UHUGEINT", strings.HasPrefix(t, "DECIMAL"):
+		col.GoType += "preformTypes.Rat"
+		col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+		col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+		col.IsScanner = true
+	default:
+		col.GoType = "any"
+	}
+}
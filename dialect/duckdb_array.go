@@ -0,0 +1,111 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"reflect"
+	"strings"
+)
+
+func (d duckdbDialect) ArrayEq(arrColA any, arrColB any) (query string, args []any, err error) {
+	return d.arrayFormatter(arrColA, arrColB, "%s = %s")
+}
+
+func (d duckdbDialect) ArrayConcat(arrColA any, arrColB any) (query string, args []any, err error) {
+	return d.arrayFormatter(arrColA, arrColB, "list_concat(%s, %s)")
+}
+func (d duckdbDialect) ArrayContains(arrColA any, arrColB any) (query string, args []any, err error) {
+	return d.arrayFormatter(arrColA, arrColB, "list_has_all(%s, %s)")
+}
+func (d duckdbDialect) ArrayContainsBy(arrColA any, arrColB any) (query string, args []any, err error) {
+	return d.arrayFormatter(arrColB, arrColA, "list_has_all(%s, %s)")
+}
+
+func (d duckdbDialect) ArrayAny(arrCol any, value any) (query string, args []any, err error) {
+	return d.arrayFormatter(arrCol, value, "list_contains(%s, %s)")
+}
+
+func (d duckdbDialect) ArrayHasAny(arrColA any, arrColB any) (query string, args []any, err error) {
+	return d.arrayFormatter(arrColA, arrColB, "list_has_any(%s, %s)")
+}
+
+// duckdbListArg slices as a [?, ?] list of their items, the driver binds no LIST args
+func duckdbListArg(v any) any {
+	var items []any
+	switch vv := v.(type) {
+	case []any:
+		items = vv
+	case []byte:
+		return v
+	case preformShare.IArrayTypes:
+		items = vv.IterAny()
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return v
+		}
+		items = make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	}
+	return squirrel.Expr("["+strings.TrimSuffix(strings.Repeat("?, ", len(items)), ", ")+"]", items...)
+}
+
+func (d duckdbDialect) ValueParsers() map[reflect.Type]any {
+	return map[reflect.Type]any{
+		reflect.TypeOf([]any{}): func(dbType string, careZero bool) func(v *[]any) any {
+			return func(v *[]any) any {
+				return duckdbListArg(*v)
+			}
+		},
+	}
+}
+
+func (d duckdbDialect) arrayFormatter(v1 any, v2 any, format string) (query string, args []any, err error) {
+	v1, v2 = duckdbListArg(v1), duckdbListArg(v2)
+	switch v1.(type) {
+	case preformShare.ISqlizerWithDialect:
+		return d.arrayFormatter(v1.(preformShare.ISqlizerWithDialect).WithDialect(d), v2, format)
+	case preformShare.ICol:
+		switch v2.(type) {
+		case preformShare.ISqlizerWithDialect:
+			return d.arrayFormatter(v1, v2.(preformShare.ISqlizerWithDialect).WithDialect(d), format)
+		case preformShare.ICol:
+			return fmt.Sprintf(format, v1.(preformShare.ICol).GetCode(), v2.(preformShare.ICol).GetCode()), args, nil
+		case squirrel.Sqlizer:
+			return preformShare.NestCondSql(fmt.Sprintf(format, v1.(preformShare.ICol).GetCode(), "?"), []any{v2}, d)
+		default:
+			return fmt.Sprintf(format, v1.(preformShare.ICol).GetCode(), "?"), []any{v2}, nil
+		}
+	case squirrel.Sqlizer:
+		switch v2.(type) {
+		case preformShare.ISqlizerWithDialect:
+			return d.arrayFormatter(v1, v2.(preformShare.ISqlizerWithDialect).WithDialect(d), format)
+		case preformShare.ICol:
+			return preformShare.NestCondSql(fmt.Sprintf(format, "?", v2.(preformShare.ICol).GetCode()), []any{v1}, d)
+		default:
+			return preformShare.NestCondSql(fmt.Sprintf(format, "?", "?"), []any{v1, v2}, d)
+		}
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		goto caseAny
+	default:
+		if _, ok := v1.(driver.Valuer); ok {
+			goto caseAny
+		}
+		return "", nil, fmt.Errorf("Any not support %T %v", v1, v1)
+	}
+caseAny:
+	switch v2.(type) {
+	case preformShare.ISqlizerWithDialect:
+		return d.arrayFormatter(v1, v2.(preformShare.ISqlizerWithDialect).WithDialect(d), format)
+	case preformShare.ICol:
+		return fmt.Sprintf(format, "?", v2.(preformShare.ICol).GetCode()), []any{v1}, nil
+	case squirrel.Sqlizer:
+		return preformShare.NestCondSql(fmt.Sprintf(format, "?", "?"), []any{v1, v2}, d)
+	default:
+		return fmt.Sprintf(format, "?", "?"), []any{v1, v2}, nil
+	}
+}
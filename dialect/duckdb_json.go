@@ -0,0 +1,31 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+)
+
+func (d duckdbDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	switch {
+	case kind == reflect.String:
+		return fmt.Sprintf("%s->>%s", col.GetCode(), jsonPathLiteral(path)), nil
+	case kind == reflect.Bool:
+		return fmt.Sprintf("CAST(%s->>%s AS BOOLEAN)", col.GetCode(), jsonPathLiteral(path)), nil
+	case isJsonNumberKind(kind):
+		return fmt.Sprintf("CAST(%s->>%s AS DOUBLE)", col.GetCode(), jsonPathLiteral(path)), nil
+	}
+	return fmt.Sprintf("%s->%s", col.GetCode(), jsonPathLiteral(path)), nil
+}
+
+func (d duckdbDialect) JsonContains(col preformShare.ICol, value any) (query string, args []any, err error) {
+	arg, err := jsonArg(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("json_contains(%s, ?)", col.GetCode()), []any{arg}, nil
+}
+
+func (d duckdbDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("json_exists(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil, nil
+}
@@ -0,0 +1,328 @@
+package dialect
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"strings"
+)
+
+type mssqlDialect struct {
+	basicSqlDialect
+}
+
+func NewMssqlDialect() *mssqlDialect {
+	return &mssqlDialect{basicSqlDialect: basicSqlDialect{
+		quoteTpl:           "[%s]",
+		lastInsertIdMethod: LastInsertIdMethodByOutput,
+		lastInsertIdSuffix: func(col string) squirrel.Sqlizer {
+			return squirrel.Expr(fmt.Sprintf("OUTPUT INSERTED.[%s]", col))
+		},
+	}}
+}
+
+func (d mssqlDialect) Aggregate(fn preformShare.Aggregator, body any, params ...any) squirrel.Sqlizer {
+	var (
+		bodyStr string
+		args    []any
+	)
+	switch body.(type) {
+	case string:
+		bodyStr = body.(string)
+	case preformShare.ICol:
+		bodyStr = body.(preformShare.ICol).GetCode()
+	case squirrel.Sqlizer:
+		s := body.(squirrel.Sqlizer)
+		bodyStr, args, _ = s.ToSql()
+		bodyStr, args, _ = preformShare.NestSql(bodyStr, args)
+	}
+	switch fn {
+	case AggGroupConcat:
+		return squirrel.Expr(fmt.Sprintf("STRING_AGG(%s, ?)", bodyStr), append(args, params[0])...)
+	case AggCountDistinct:
+		return squirrel.Expr(fmt.Sprintf("COUNT(DISTINCT %s)", bodyStr), args...)
+	case AggStdDev:
+		return squirrel.Expr(fmt.Sprintf("STDEV(%s)", bodyStr), args...)
+	default:
+		if l := len(params); l != 0 {
+			return squirrel.Expr(fmt.Sprintf("%s(%s%s)", fn, bodyStr, strings.Repeat(",?", l)), append(args, params...)...)
+		}
+		return squirrel.Expr(fmt.Sprintf("%s(%s)", fn, bodyStr), args...)
+	}
+}
+
+// Paging OFFSET FETCH requires ORDER BY, unordered queries are ordered by nothing
+func (d mssqlDialect) Paging(limit, offset uint64, ordered bool) (squirrel.Sqlizer, error) {
+	var (
+		sql = fmt.Sprintf("OFFSET %d ROWS", offset)
+	)
+	if !ordered {
+		sql = "ORDER BY (SELECT NULL) " + sql
+	}
+	if limit != 0 {
+		sql += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return squirrel.Expr(sql), nil
+}
+
+// RowValueCompare row constructors can not be compared
+func (d mssqlDialect) RowValueCompare() bool {
+	return false
+}
+
+// Savepoint savepoints are released with the transaction
+func (d mssqlDialect) Savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	name = d.QuoteIdentifier(name)
+	return "SAVE TRANSACTION " + name, "ROLLBACK TRANSACTION " + name, "", nil
+}
+
+// IsRetryable deadlock victim 1205 and snapshot update conflict 3960, go-mssqldb errors have SQLErrorNumber
+func (d mssqlDialect) IsRetryable(err error) bool {
+	var (
+		mssqlErr interface{ SQLErrorNumber() int32 }
+	)
+	if errors.As(err, &mssqlErr) {
+		switch mssqlErr.SQLErrorNumber() {
+		case 1205, 3960:
+			return true
+		}
+	}
+	return false
+}
+
+// Ddl varchar without length from GetStructure is created as varchar(255), defaults are not altered
+func (d mssqlDialect) Ddl(ddl preformShare.Ddl) (string, error) {
+	var (
+		style = ddlStyle{dropForeignKey: "DROP CONSTRAINT", addColumn: "ADD", colType: func(t string) string {
+			switch strings.ToLower(t) {
+			case "varchar", "nvarchar", "varbinary":
+				return t + "(255)"
+			}
+			return t
+		}}
+	)
+	style.autoKey = func(col *preformShare.Column) string {
+		return fmt.Sprintf("%s %s IDENTITY(1,1) NOT NULL", d.QuoteIdentifier(col.Name), style.colType(col.Type))
+	}
+	if ddl.Kind == preformShare.DdlAlterColumn {
+		if strings.EqualFold(ddl.FromColumn.Type, ddl.Column.Type) && ddl.FromColumn.Nullable == ddl.Column.Nullable {
+			return "", nil
+		}
+		nullable := " NOT NULL"
+		if ddl.Column.Nullable {
+			nullable = " NULL"
+		}
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s%s", d.tableName(ddl.Table), d.QuoteIdentifier(ddl.Column.Name), style.colType(ddl.Column.Type), nullable), nil
+	}
+	return d.ddl(ddl, style)
+}
+
+func (d mssqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
+	var (
+		schemes                          []*preformShare.Scheme
+		scheme                           *preformShare.Scheme
+		ok                               bool
+		schemaByName                     = make(map[string]*preformShare.Scheme)
+		schemaName, tableName, tableType string
+		table                            *preformShare.Table
+		tableByName                      = make(map[string]*preformShare.Table)
+		allSchemas                       = []string{}
+	)
+	schemaQ := squirrel.Select("TABLE_SCHEMA", "TABLE_NAME", "TABLE_TYPE").From("INFORMATION_SCHEMA.TABLES").PlaceholderFormat(squirrel.AtP)
+	if len(schemasEmptyIsAll) != 0 {
+		schemaQ = schemaQ.Where(squirrel.Eq{"TABLE_SCHEMA": schemasEmptyIsAll})
+	} else {
+		schemaQ = schemaQ.Where(squirrel.NotEq{"TABLE_SCHEMA": []string{"sys", "INFORMATION_SCHEMA"}})
+	}
+	rows, err := schemaQ.OrderBy("TABLE_SCHEMA", "TABLE_NAME").RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &tableType)
+		if err != nil {
+			panic(err)
+		}
+		if scheme, ok = schemaByName[schemaName]; !ok {
+			scheme = &preformShare.Scheme{Name: schemaName, Imports: map[string]struct{}{`"github.com/go-preform/preform/preformBuilder"`: {}}}
+			schemaByName[schemaName] = scheme
+			schemes = append(schemes, scheme)
+			allSchemas = append(allSchemas, schemaName)
+		}
+		table = &preformShare.Table{Name: tableName, Scheme: scheme, ColumnByName: make(map[string]*preformShare.Column), Imports: map[string]struct{}{}, ForeignKeys: map[string]*preformShare.ForeignKey{}, IsView: tableType == "VIEW"}
+		scheme.Tables = append(scheme.Tables, table)
+		tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)] = table
+	}
+	if len(allSchemas) != 0 {
+		d.getTableDetails(db, tableByName, allSchemas)
+	}
+	return schemes
+}
+
+func (d mssqlDialect) getTableDetails(db *sql.DB, tableByName map[string]*preformShare.Table, schemaNames []string) {
+	var (
+		schemaName, tableName, colName, dataType, isNullable string
+		fkName, fkSchemaName, fkTableName, fkColName         string
+		colDefault, colComment                               sql.NullString
+		isIdentity                                           sql.NullInt64
+		pkPos                                                int64
+		ok                                                   bool
+		table, fkTable                                       *preformShare.Table
+		col, fkCol                                           *preformShare.Column
+		schemas                                              = map[string]struct{}{}
+		fk                                                   *preformShare.ForeignKey
+	)
+	for _, schemaName = range schemaNames {
+		schemas[schemaName] = struct{}{}
+	}
+	rows, err := squirrel.Select(
+		"c.TABLE_SCHEMA",
+		"c.TABLE_NAME",
+		"c.COLUMN_NAME",
+		"c.DATA_TYPE",
+		"c.IS_NULLABLE",
+		"c.COLUMN_DEFAULT",
+		"COLUMNPROPERTY(OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)), c.COLUMN_NAME, 'IsIdentity')",
+		"CAST(ep.value AS NVARCHAR(4000))",
+	).From("INFORMATION_SCHEMA.COLUMNS c").
+		LeftJoin("sys.extended_properties ep ON ep.major_id = OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)) AND ep.minor_id = COLUMNPROPERTY(OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)), c.COLUMN_NAME, 'ColumnId') AND ep.name = 'MS_Description'").
+		Where(squirrel.Eq{"c.TABLE_SCHEMA": schemaNames}).OrderBy("c.TABLE_SCHEMA", "c.TABLE_NAME", "c.ORDINAL_POSITION").
+		PlaceholderFormat(squirrel.AtP).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &colName, &dataType, &isNullable, &colDefault, &isIdentity, &colComment)
+		if err != nil {
+			panic(err)
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+			continue
+		}
+		if col, ok = table.ColumnByName[colName]; !ok {
+			col = &preformShare.Column{Name: colName, Table: table}
+			table.Columns = append(table.Columns, col)
+			table.ColumnByName[colName] = col
+		}
+		if colDefault.Valid && strings.HasPrefix(colDefault.String, "(") && strings.HasSuffix(colDefault.String, ")") {
+			colDefault.String = colDefault.String[1 : len(colDefault.String)-1] //((0)) ('a') (getdate())
+		}
+		col.DefaultValue = colDefault
+		col.Nullable = isNullable == "YES"
+		col.IsAutoKey = isIdentity.Int64 == 1
+		col.Type = dataType
+		col.Comment = colComment.String
+		mssqlCalcGoType(col)
+	}
+	_ = rows.Close()
+
+	rows, err = squirrel.Select("k.TABLE_SCHEMA", "k.TABLE_NAME", "k.COLUMN_NAME", "k.ORDINAL_POSITION").
+		From("INFORMATION_SCHEMA.TABLE_CONSTRAINTS t").
+		Join("INFORMATION_SCHEMA.KEY_COLUMN_USAGE k ON k.CONSTRAINT_SCHEMA = t.CONSTRAINT_SCHEMA AND k.CONSTRAINT_NAME = t.CONSTRAINT_NAME").
+		Where(squirrel.Eq{"t.CONSTRAINT_TYPE": "PRIMARY KEY", "t.TABLE_SCHEMA": schemaNames}).
+		PlaceholderFormat(squirrel.AtP).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &colName, &pkPos)
+		if err != nil {
+			panic(err)
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; ok {
+			if col, ok = table.ColumnByName[colName]; ok {
+				col.IsPrimaryKey = true
+				col.PkPos = pkPos
+			}
+		}
+	}
+	_ = rows.Close()
+
+	rows, err = squirrel.Select("fk.name", "SCHEMA_NAME(pt.schema_id)", "pt.name", "pc.name", "SCHEMA_NAME(rt.schema_id)", "rt.name", "rc.name").
+		From("sys.foreign_keys fk").
+		Join("sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id").
+		Join("sys.tables pt ON pt.object_id = fkc.parent_object_id").
+		Join("sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id").
+		Join("sys.tables rt ON rt.object_id = fkc.referenced_object_id").
+		Join("sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id").
+		Where(squirrel.Eq{"SCHEMA_NAME(pt.schema_id)": schemaNames}).OrderBy("fk.name", "fkc.constraint_column_id").
+		PlaceholderFormat(squirrel.AtP).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&fkName, &schemaName, &tableName, &colName, &fkSchemaName, &fkTableName, &fkColName)
+		if err != nil {
+			panic(err)
+		}
+		if _, ok = schemas[fkSchemaName]; !ok {
+			continue
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+			continue
+		}
+		if fkTable, ok = tableByName[fmt.Sprintf("%s.%s", fkSchemaName, fkTableName)]; !ok {
+			continue
+		}
+		if col, ok = table.ColumnByName[colName]; !ok {
+			continue
+		}
+		if fkCol, ok = fkTable.ColumnByName[fkColName]; !ok {
+			fkCol = &preformShare.Column{Name: fkColName, Table: fkTable}
+			fkTable.Columns = append(fkTable.Columns, fkCol)
+			fkTable.ColumnByName[fkColName] = fkCol
+		}
+		if fk, ok = table.ForeignKeys[fkName]; !ok {
+			fk = &preformShare.ForeignKey{Name: fkName}
+			table.ForeignKeys[fkName] = fk
+			col.ForeignKeys = append(col.ForeignKeys, fk)
+		}
+		fk.LocalKeys = append(fk.LocalKeys, col)
+		fk.ForeignKeys = append(fk.ForeignKeys, fkCol)
+	}
+	_ = rows.Close()
+
+	d.commentForeignKeys(tableByName, schemas)
+}
+
+func mssqlCalcGoType(col *preformShare.Column) {
+	if col.Nullable {
+		defer func() {
+			col.GoType = fmt.Sprintf("preformTypes.Null[%s]", col.GoType)
+			col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.IsScanner = true
+		}()
+	}
+	switch col.Type {
+	case "float":
+		col.GoType += "float64"
+	case "real":
+		col.GoType += "float32"
+	case "bigint":
+		col.GoType += "int64"
+	case "int":
+		col.GoType += "int32"
+	case "tinyint", "smallint":
+		col.GoType += "int16"
+	case "bit":
+		col.GoType += "bool"
+	case "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "time":
+		col.GoType += "time.Time"
+		col.Table.Scheme.Imports[`"time"`] = struct{}{}
+		col.Table.Imports[`"time"`] = struct{}{}
+	case "binary", "varbinary", "image":
+		col.GoType += "[]byte"
+	case "char", "varchar", "nchar", "nvarchar", "text", "ntext", "xml":
+		col.GoType += "string"
+	case "decimal", "numeric", "money", "smallmoney":
+		col.GoType += "preformTypes.Rat"
+		col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+		col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+	default:
+		col.GoType = "any"
+	}
+}
@@ -0,0 +1,19 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+)
+
+func (d mssqlDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	switch kind {
+	case reflect.Invalid, reflect.Map, reflect.Struct, reflect.Slice, reflect.Interface:
+		return fmt.Sprintf("JSON_QUERY(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil
+	}
+	return fmt.Sprintf("JSON_VALUE(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil
+}
+
+func (d mssqlDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("JSON_PATH_EXISTS(%s, %s) = 1", col.GetCode(), jsonPathLiteral(path)), nil, nil
+}
@@ -1,16 +1,20 @@
 package dialect
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	preformShare "github.com/go-preform/preform/share"
 	"github.com/go-preform/squirrel"
+	"github.com/go-sql-driver/mysql"
 	"github.com/iancoleman/strcase"
 	"strings"
 )
 
 type mysqlDialect struct {
 	basicSqlDialect
+	rowAlias bool
 }
 
 func NewMysqlDialect() *mysqlDialect {
@@ -20,6 +24,108 @@ func NewMysqlDialect() *mysqlDialect {
 	}}
 }
 
+// DetectMysqlDialect upsert by the row alias on mysql 8.0.19+ instead of VALUES(), which is deprecated since 8.0.20, mariadb has no row alias
+func DetectMysqlDialect(ctx context.Context, db *sql.DB) (*mysqlDialect, error) {
+	var (
+		version             string
+		major, minor, patch int
+	)
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return nil, err
+	}
+	d := NewMysqlDialect()
+	if !strings.Contains(version, "MariaDB") {
+		_, _ = fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &patch)
+		d.rowAlias = major > 8 || major == 8 && (minor > 0 || patch >= 19)
+	}
+	return d, nil
+}
+
+// Upsert conflict columns are decided by the unique keys of the table
+func (d mysqlDialect) Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, err error) {
+	var (
+		sets  = make([]string, 0, len(updateCols)+1)
+		alias string
+	)
+	if d.rowAlias {
+		alias = d.QuoteIdentifier("new")
+	}
+	for _, col := range updateCols {
+		col = d.QuoteIdentifier(col)
+		if d.rowAlias {
+			sets = append(sets, fmt.Sprintf("%s = %s.%s", col, alias, col))
+		} else {
+			sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", col, col))
+		}
+	}
+	if autoPk != "" {
+		autoPk = d.QuoteIdentifier(autoPk)
+		sets = append(sets, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", autoPk, autoPk)) //LastInsertId returns the existing id
+	} else if len(sets) == 0 {
+		if len(conflictCols) == 0 {
+			return nil, d.lastInsertIdMethod, ErrorNoConflictTarget
+		}
+		col := d.QuoteIdentifier(conflictCols[0])
+		sets = append(sets, fmt.Sprintf("%s = %s", col, col))
+	}
+	if d.rowAlias {
+		return squirrel.Expr(fmt.Sprintf("AS %s ON DUPLICATE KEY UPDATE %s", alias, strings.Join(sets, ", "))), d.lastInsertIdMethod, nil
+	}
+	return squirrel.Expr(fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(sets, ", "))), d.lastInsertIdMethod, nil
+}
+
+// RowLock mysql 8+
+func (d mysqlDialect) RowLock(lock preformShare.RowLock) (squirrel.Sqlizer, error) {
+	return d.rowLock(lock)
+}
+
+// SetOperation INTERSECT and EXCEPT need mysql 8.0.31+, which is not assumed
+func (d mysqlDialect) SetOperation(op string) (string, error) {
+	switch op {
+	case "INTERSECT", "EXCEPT":
+		return "", ErrorNotSupport
+	}
+	return op, nil
+}
+
+func (d mysqlDialect) Savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	return d.savepoint(name)
+}
+
+// IsRetryable deadlock 1213 and lock wait timeout 1205
+func (d mysqlDialect) IsRetryable(err error) bool {
+	var (
+		mysqlErr *mysql.MySQLError
+	)
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1213, 1205:
+			return true
+		}
+	}
+	return false
+}
+
+// Ddl varchar without length from GetStructure is created as varchar(255)
+func (d mysqlDialect) Ddl(ddl preformShare.Ddl) (string, error) {
+	var (
+		style = ddlStyle{dropForeignKey: "DROP FOREIGN KEY", colType: func(t string) string {
+			switch strings.ToLower(t) {
+			case "varchar", "varbinary":
+				return t + "(255)"
+			}
+			return t
+		}}
+	)
+	style.autoKey = func(col *preformShare.Column) string {
+		return fmt.Sprintf("%s %s NOT NULL AUTO_INCREMENT", d.QuoteIdentifier(col.Name), style.colType(col.Type))
+	}
+	if ddl.Kind == preformShare.DdlAlterColumn {
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", d.tableName(ddl.Table), d.columnDef(ddl.Column, style)), nil
+	}
+	return d.ddl(ddl, style)
+}
+
 func (d mysqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
 	var (
 		schemes               []*preformShare.Scheme
@@ -0,0 +1,15 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+)
+
+// FullTextMatch the column must have a FULLTEXT index
+func (d mysqlDialect) FullTextMatch(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return fmt.Sprintf("MATCH (%s) AGAINST (? IN NATURAL LANGUAGE MODE)", col.GetCode()), []any{text}, nil
+}
+
+func (d mysqlDialect) FullTextRank(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return d.FullTextMatch(col, text)
+}
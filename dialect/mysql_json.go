@@ -0,0 +1,27 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+)
+
+func (d mysqlDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	query = fmt.Sprintf("JSON_EXTRACT(%s, %s)", col.GetCode(), jsonPathLiteral(path))
+	if kind == reflect.String {
+		return fmt.Sprintf("JSON_UNQUOTE(%s)", query), nil
+	}
+	return query, nil
+}
+
+func (d mysqlDialect) JsonContains(col preformShare.ICol, value any) (query string, args []any, err error) {
+	arg, err := jsonArg(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("JSON_CONTAINS(%s, ?)", col.GetCode()), []any{arg}, nil
+}
+
+func (d mysqlDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("JSON_CONTAINS_PATH(%s, 'one', %s)", col.GetCode(), jsonPathLiteral(path)), nil, nil
+}
@@ -0,0 +1,306 @@
+package dialect
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"strings"
+)
+
+type oracleDialect struct {
+	basicSqlDialect
+}
+
+func NewOracleDialect() *oracleDialect {
+	return &oracleDialect{basicSqlDialect: basicSqlDialect{
+		quoteTpl:           `"%s"`,
+		lastInsertIdMethod: LastInsertIdMethodByOutParam,
+		lastInsertIdSuffix: func(col string) squirrel.Sqlizer {
+			return squirrel.Expr(fmt.Sprintf(`RETURNING "%s" INTO ?`, col))
+		},
+	}}
+}
+
+func (d oracleDialect) Aggregate(fn preformShare.Aggregator, body any, params ...any) squirrel.Sqlizer {
+	var (
+		bodyStr string
+		args    []any
+	)
+	switch body.(type) {
+	case string:
+		bodyStr = body.(string)
+	case preformShare.ICol:
+		bodyStr = body.(preformShare.ICol).GetCode()
+	case squirrel.Sqlizer:
+		s := body.(squirrel.Sqlizer)
+		bodyStr, args, _ = s.ToSql()
+		bodyStr, args, _ = preformShare.NestSql(bodyStr, args)
+	}
+	switch fn {
+	case AggGroupConcat:
+		//the delimiter of LISTAGG must be a literal
+		return squirrel.Expr(fmt.Sprintf("LISTAGG(%s, '%s') WITHIN GROUP (ORDER BY NULL)", bodyStr, strings.ReplaceAll(fmt.Sprint(params[0]), "'", "''")), args...)
+	case AggCountDistinct:
+		return squirrel.Expr(fmt.Sprintf("COUNT(DISTINCT %s)", bodyStr), args...)
+	default:
+		if l := len(params); l != 0 {
+			return squirrel.Expr(fmt.Sprintf("%s(%s%s)", fn, bodyStr, strings.Repeat(",?", l)), append(args, params...)...)
+		}
+		return squirrel.Expr(fmt.Sprintf("%s(%s)", fn, bodyStr), args...)
+	}
+}
+
+// Paging row limiting clause of 12c
+func (d oracleDialect) Paging(limit, offset uint64, ordered bool) (squirrel.Sqlizer, error) {
+	var (
+		parts []string
+	)
+	if offset != 0 {
+		parts = append(parts, fmt.Sprintf("OFFSET %d ROWS", offset))
+	}
+	if limit != 0 {
+		parts = append(parts, fmt.Sprintf("FETCH FIRST %d ROWS ONLY", limit))
+	}
+	return squirrel.Expr(strings.Join(parts, " ")), nil
+}
+
+// RowValueCompare row values only support = and IN
+func (d oracleDialect) RowValueCompare() bool {
+	return false
+}
+
+// SetOperation EXCEPT is MINUS before 21c
+func (d oracleDialect) SetOperation(op string) (string, error) {
+	if op == "EXCEPT" {
+		return "MINUS", nil
+	}
+	return op, nil
+}
+
+// Savepoint savepoints are released with the transaction
+func (d oracleDialect) Savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	name = d.QuoteIdentifier(name)
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "", nil
+}
+
+// IsRetryable ORA-08177 can't serialize access and ORA-00060 deadlock, godror errors have Code, go-ora errors have the code in message only
+func (d oracleDialect) IsRetryable(err error) bool {
+	var (
+		oraErr interface{ Code() int }
+	)
+	if err == nil {
+		return false
+	}
+	if errors.As(err, &oraErr) {
+		switch oraErr.Code() {
+		case 8177, 60:
+			return true
+		}
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "ORA-08177") || strings.Contains(msg, "ORA-00060")
+}
+
+// GetStructure schemas are owners, empty for the current schema
+func (d oracleDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
+	var (
+		schemes                          []*preformShare.Scheme
+		scheme                           *preformShare.Scheme
+		ok                               bool
+		schemaByName                     = make(map[string]*preformShare.Scheme)
+		schemaName, tableName, tableType string
+		table                            *preformShare.Table
+		tableByName                      = make(map[string]*preformShare.Table)
+		allSchemas                       = []string{}
+	)
+	schemaQ := squirrel.Select("OWNER", "TABLE_NAME", "TABLE_TYPE").From("ALL_TAB_COMMENTS").Where("TABLE_NAME NOT LIKE 'BIN$%'").PlaceholderFormat(squirrel.Colon)
+	if len(schemasEmptyIsAll) != 0 {
+		schemaQ = schemaQ.Where(squirrel.Eq{"OWNER": schemasEmptyIsAll})
+	} else {
+		schemaQ = schemaQ.Where("OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')")
+	}
+	rows, err := schemaQ.OrderBy("OWNER", "TABLE_NAME").RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &tableType)
+		if err != nil {
+			panic(err)
+		}
+		if scheme, ok = schemaByName[schemaName]; !ok {
+			scheme = &preformShare.Scheme{Name: schemaName, Imports: map[string]struct{}{`"github.com/go-preform/preform/preformBuilder"`: {}}}
+			schemaByName[schemaName] = scheme
+			schemes = append(schemes, scheme)
+			allSchemas = append(allSchemas, schemaName)
+		}
+		table = &preformShare.Table{Name: tableName, Scheme: scheme, ColumnByName: make(map[string]*preformShare.Column), Imports: map[string]struct{}{}, ForeignKeys: map[string]*preformShare.ForeignKey{}, IsView: tableType == "VIEW"}
+		scheme.Tables = append(scheme.Tables, table)
+		tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)] = table
+	}
+	if len(allSchemas) != 0 {
+		d.getTableDetails(db, tableByName, allSchemas)
+	}
+	return schemes
+}
+
+func (d oracleDialect) getTableDetails(db *sql.DB, tableByName map[string]*preformShare.Table, schemaNames []string) {
+	var (
+		schemaName, tableName, colName, dataType, isNullable string
+		fkName, fkSchemaName, fkTableName, fkColName         string
+		colDefault, colComment, isIdentity                   sql.NullString
+		precision, scale                                     sql.NullInt64
+		pkPos                                                int64
+		ok                                                   bool
+		table, fkTable                                       *preformShare.Table
+		col, fkCol                                           *preformShare.Column
+		schemas                                              = map[string]struct{}{}
+		fk                                                   *preformShare.ForeignKey
+	)
+	for _, schemaName = range schemaNames {
+		schemas[schemaName] = struct{}{}
+	}
+	rows, err := squirrel.Select("c.OWNER", "c.TABLE_NAME", "c.COLUMN_NAME", "c.DATA_TYPE", "c.DATA_PRECISION", "c.DATA_SCALE", "c.NULLABLE", "c.DATA_DEFAULT", "c.IDENTITY_COLUMN", "cc.COMMENTS").
+		From("ALL_TAB_COLUMNS c").
+		LeftJoin("ALL_COL_COMMENTS cc ON cc.OWNER = c.OWNER AND cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME").
+		Where(squirrel.Eq{"c.OWNER": schemaNames}).OrderBy("c.OWNER", "c.TABLE_NAME", "c.COLUMN_ID").
+		PlaceholderFormat(squirrel.Colon).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &colName, &dataType, &precision, &scale, &isNullable, &colDefault, &isIdentity, &colComment)
+		if err != nil {
+			panic(err)
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+			continue
+		}
+		if col, ok = table.ColumnByName[colName]; !ok {
+			col = &preformShare.Column{Name: colName, Table: table}
+			table.Columns = append(table.Columns, col)
+			table.ColumnByName[colName] = col
+		}
+		if colDefault.Valid {
+			colDefault.String = strings.TrimSpace(colDefault.String) //DATA_DEFAULT keeps the text as typed
+			colDefault.Valid = colDefault.String != "" && !strings.EqualFold(colDefault.String, "NULL")
+		}
+		col.DefaultValue = colDefault
+		col.Nullable = isNullable == "Y"
+		col.IsAutoKey = isIdentity.String == "YES" || strings.Contains(strings.ToLower(colDefault.String), ".nextval") //identity or sequence default
+		col.Type = dataType
+		col.Comment = colComment.String
+		oracleCalcGoType(col, precision, scale)
+	}
+	_ = rows.Close()
+
+	rows, err = squirrel.Select("c.OWNER", "c.TABLE_NAME", "cc.COLUMN_NAME", "cc.POSITION").
+		From("ALL_CONSTRAINTS c").
+		Join("ALL_CONS_COLUMNS cc ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME").
+		Where(squirrel.Eq{"c.CONSTRAINT_TYPE": "P", "c.OWNER": schemaNames}).
+		PlaceholderFormat(squirrel.Colon).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&schemaName, &tableName, &colName, &pkPos)
+		if err != nil {
+			panic(err)
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; ok {
+			if col, ok = table.ColumnByName[colName]; ok {
+				col.IsPrimaryKey = true
+				col.PkPos = pkPos
+			}
+		}
+	}
+	_ = rows.Close()
+
+	rows, err = squirrel.Select("c.CONSTRAINT_NAME", "c.OWNER", "c.TABLE_NAME", "cc.COLUMN_NAME", "rc.OWNER", "rc.TABLE_NAME", "rc.COLUMN_NAME").
+		From("ALL_CONSTRAINTS c").
+		Join("ALL_CONS_COLUMNS cc ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME").
+		Join("ALL_CONS_COLUMNS rc ON rc.OWNER = c.R_OWNER AND rc.CONSTRAINT_NAME = c.R_CONSTRAINT_NAME AND rc.POSITION = cc.POSITION").
+		Where(squirrel.Eq{"c.CONSTRAINT_TYPE": "R", "c.OWNER": schemaNames}).OrderBy("c.CONSTRAINT_NAME", "cc.POSITION").
+		PlaceholderFormat(squirrel.Colon).RunWith(db).Query()
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		err = rows.Scan(&fkName, &schemaName, &tableName, &colName, &fkSchemaName, &fkTableName, &fkColName)
+		if err != nil {
+			panic(err)
+		}
+		if _, ok = schemas[fkSchemaName]; !ok {
+			continue
+		}
+		if table, ok = tableByName[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+			continue
+		}
+		if fkTable, ok = tableByName[fmt.Sprintf("%s.%s", fkSchemaName, fkTableName)]; !ok {
+			continue
+		}
+		if col, ok = table.ColumnByName[colName]; !ok {
+			continue
+		}
+		if fkCol, ok = fkTable.ColumnByName[fkColName]; !ok {
+			fkCol = &preformShare.Column{Name: fkColName, Table: fkTable}
+			fkTable.Columns = append(fkTable.Columns, fkCol)
+			fkTable.ColumnByName[fkColName] = fkCol
+		}
+		if fk, ok = table.ForeignKeys[fkName]; !ok {
+			fk = &preformShare.ForeignKey{Name: fkName}
+			table.ForeignKeys[fkName] = fk
+			col.ForeignKeys = append(col.ForeignKeys, fk)
+		}
+		fk.LocalKeys = append(fk.LocalKeys, col)
+		fk.ForeignKeys = append(fk.ForeignKeys, fkCol)
+	}
+	_ = rows.Close()
+
+	d.commentForeignKeys(tableByName, schemas)
+}
+
+// oracleCalcGoType NUMBER by precision and scale, NUMBER without scale is decimal unless it's an auto key
+func oracleCalcGoType(col *preformShare.Column, precision, scale sql.NullInt64) {
+	if col.Nullable {
+		defer func() {
+			col.GoType = fmt.Sprintf("preformTypes.Null[%s]", col.GoType)
+			col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.IsScanner = true
+		}()
+	}
+	switch {
+	case col.Type == "NUMBER":
+		switch {
+		case scale.Valid && scale.Int64 == 0 && precision.Valid && precision.Int64 <= 4:
+			col.GoType += "int16"
+		case scale.Valid && scale.Int64 == 0 && precision.Valid && precision.Int64 <= 9:
+			col.GoType += "int32"
+		case scale.Valid && scale.Int64 == 0 && precision.Valid && precision.Int64 <= 18, !precision.Valid && (scale.Valid && scale.Int64 == 0 || col.IsAutoKey):
+			col.GoType += "int64"
+		default:
+			col.GoType += "preformTypes.Rat"
+			col.Table.Scheme.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+			col.Table.Imports[`"github.com/go-preform/preform/types"`] = struct{}{}
+		}
+	case col.Type == "FLOAT", col.Type == "BINARY_DOUBLE":
+		col.GoType += "float64"
+	case col.Type == "BINARY_FLOAT":
+		col.GoType += "float32"
+	case col.Type == "DATE", strings.HasPrefix(col.Type, "TIMESTAMP"):
+		col.GoType += "time.Time"
+		col.Table.Scheme.Imports[`"time"`] = struct{}{}
+		col.Table.Imports[`"time"`] = struct{}{}
+	case col.Type == "CHAR", col.Type == "NCHAR", col.Type == "VARCHAR2", col.Type == "NVARCHAR2", col.Type == "CLOB", col.Type == "NCLOB", col.Type == "LONG":
+		col.GoType += "string"
+	case col.Type == "BLOB", col.Type == "RAW", col.Type == "LONG RAW":
+		col.GoType += "[]byte"
+	default:
+		col.GoType = "any"
+	}
+}
@@ -0,0 +1,21 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+)
+
+func (d oracleDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	switch {
+	case isJsonNumberKind(kind):
+		return fmt.Sprintf("JSON_VALUE(%s, %s RETURNING NUMBER)", col.GetCode(), jsonPathLiteral(path)), nil
+	case kind == reflect.Invalid, kind == reflect.Map, kind == reflect.Struct, kind == reflect.Slice, kind == reflect.Interface:
+		return fmt.Sprintf("JSON_QUERY(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil
+	}
+	return fmt.Sprintf("JSON_VALUE(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil
+}
+
+func (d oracleDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("JSON_EXISTS(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil, nil
+}
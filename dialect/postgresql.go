@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	preformShare "github.com/go-preform/preform/share"
 	preformTypes "github.com/go-preform/preform/types"
@@ -17,6 +18,7 @@ import (
 
 type postgresqlDialect struct {
 	basicSqlDialect
+	compat pgCompat
 }
 
 func NewPostgresqlDialect() *postgresqlDialect {
@@ -57,6 +59,104 @@ func (d postgresqlDialect) Aggregate(fn preformShare.Aggregator, body any, param
 	}
 }
 
+func (d postgresqlDialect) ILike(col preformShare.ICol, v any) squirrel.Sqlizer {
+	return squirrel.ILike{col.GetCode(): v}
+}
+
+func (d postgresqlDialect) Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, err error) {
+	suffix, err = d.onConflictDoUpdate(conflictCols, updateCols, "EXCLUDED")
+	return suffix, d.lastInsertIdMethod, err
+}
+
+func (d postgresqlDialect) Returning(cols []string) (squirrel.Sqlizer, error) {
+	return d.returning(cols)
+}
+
+func (d postgresqlDialect) RowLock(lock preformShare.RowLock) (squirrel.Sqlizer, error) {
+	return d.rowLock(lock)
+}
+
+func (d postgresqlDialect) Savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	return d.savepoint(name)
+}
+
+// IsRetryable serialization_failure 40001 and deadlock_detected 40P01, both pgx and pq errors have SQLState
+func (d postgresqlDialect) IsRetryable(err error) bool {
+	var (
+		pgErr interface{ SQLState() string }
+	)
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+	return false
+}
+
+// ReplicaLag 0 on the primary, note the lag grows if the primary is idle
+func (d postgresqlDialect) ReplicaLag() (query string, err error) {
+	if d.compat != pgCompatNone {
+		return "", ErrorNotSupport
+	}
+	return "SELECT COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)::float8", nil
+}
+
+// Ddl enums and custom types are types of the schema
+func (d postgresqlDialect) Ddl(ddl preformShare.Ddl) (string, error) {
+	var (
+		style = ddlStyle{dropForeignKey: "DROP CONSTRAINT", autoKey: func(col *preformShare.Column) string {
+			return fmt.Sprintf("%s %s NOT NULL GENERATED BY DEFAULT AS IDENTITY", d.QuoteIdentifier(col.Name), col.Type)
+		}}
+	)
+	switch ddl.Kind {
+	case preformShare.DdlAlterColumn:
+		var (
+			col    = d.QuoteIdentifier(ddl.Column.Name)
+			alters []string
+		)
+		if !strings.EqualFold(ddl.FromColumn.Type, ddl.Column.Type) {
+			alters = append(alters, fmt.Sprintf("ALTER COLUMN %s TYPE %s USING %s::%s", col, ddl.Column.Type, col, ddl.Column.Type))
+		}
+		if ddl.FromColumn.Nullable != ddl.Column.Nullable {
+			if ddl.Column.Nullable {
+				alters = append(alters, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", col))
+			} else {
+				alters = append(alters, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", col))
+			}
+		}
+		if ddl.FromColumn.DefaultValue != ddl.Column.DefaultValue {
+			if ddl.Column.DefaultValue.Valid {
+				alters = append(alters, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", col, ddl.Column.DefaultValue.String))
+			} else {
+				alters = append(alters, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", col))
+			}
+		}
+		if len(alters) == 0 {
+			return "", nil
+		}
+		return fmt.Sprintf("ALTER TABLE %s %s", d.tableName(ddl.Table), strings.Join(alters, ", ")), nil
+	case preformShare.DdlCreateEnum:
+		values := make([]string, len(ddl.Values))
+		for i, v := range ddl.Values {
+			values[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+		return fmt.Sprintf("CREATE TYPE %s.%s AS ENUM (%s)", d.QuoteIdentifier(ddl.Scheme), d.QuoteIdentifier(ddl.Name), strings.Join(values, ", ")), nil
+	case preformShare.DdlCreateCustomType:
+		attrs := make([]string, len(ddl.CustomType.Attr))
+		for i, attr := range ddl.CustomType.Attr {
+			if attr.DbType == "" {
+				return "", fmt.Errorf("db type of %s.%s is unknown", ddl.Name, attr.Name)
+			}
+			attrs[i] = d.QuoteIdentifier(attr.Name) + " " + attr.DbType
+		}
+		return fmt.Sprintf("CREATE TYPE %s.%s AS (%s)", d.QuoteIdentifier(ddl.Scheme), d.QuoteIdentifier(ddl.Name), strings.Join(attrs, ", ")), nil
+	case preformShare.DdlDropEnum, preformShare.DdlDropCustomType:
+		return fmt.Sprintf("DROP TYPE %s.%s", d.QuoteIdentifier(ddl.Scheme), d.QuoteIdentifier(ddl.Name)), nil
+	}
+	return d.ddl(ddl, style)
+}
+
 func (d postgresqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
 
 	var (
@@ -80,36 +180,38 @@ func (d postgresqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string)
 		inherited                                                      = map[string]string{}
 	)
 
-	//get partitioned tables
-	rows, err := squirrel.Select("inhparent.relnamespace::regnamespace::text as schema",
-		"inhparent.relname as table_name",
-		"inhrel.relname as part_name",
-		"pg_get_partkeydef(inhparent.oid) as partition_key",
-		"pg_get_expr(inhrel.relpartbound, inhrel.oid) AS bounds").PlaceholderFormat(squirrel.Dollar).
-		From("pg_class inhparent").
-		Join("pg_inherits AS i on i.inhparent  = inhparent.oid").
-		Join("pg_class as inhrel on i.inhrelid = inhrel.oid").
-		Where("inhparent.relkind = 'p'").RunWith(db).Query()
+	//get partitioned tables, cockroachdb has no partition key functions
+	if d.compat != pgCompatCockroachdb {
+		rows, err := squirrel.Select("inhparent.relnamespace::regnamespace::text as schema",
+			"inhparent.relname as table_name",
+			"inhrel.relname as part_name",
+			"pg_get_partkeydef(inhparent.oid) as partition_key",
+			"pg_get_expr(inhrel.relpartbound, inhrel.oid) AS bounds").PlaceholderFormat(squirrel.Dollar).
+			From("pg_class inhparent").
+			Join("pg_inherits AS i on i.inhparent  = inhparent.oid").
+			Join("pg_class as inhrel on i.inhrelid = inhrel.oid").
+			Where("inhparent.relkind = 'p'").RunWith(db).Query()
 
-	if err != nil {
-		panic(err)
-	}
-	for rows.Next() {
-		err = rows.Scan(&schemaName, &tableName, &inheritTable, &inheritKey, &inheritBounds)
 		if err != nil {
 			panic(err)
 		}
-		if parentInheritTable, ok := inherited[fmt.Sprintf("%s.%s", schemaName, tableName)]; ok {
-			tableName = parentInheritTable
-		} else if _, ok = inherits[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
-			inherits[fmt.Sprintf("%s.%s", schemaName, tableName)] = [][3]string{}
+		for rows.Next() {
+			err = rows.Scan(&schemaName, &tableName, &inheritTable, &inheritKey, &inheritBounds)
+			if err != nil {
+				panic(err)
+			}
+			if parentInheritTable, ok := inherited[fmt.Sprintf("%s.%s", schemaName, tableName)]; ok {
+				tableName = parentInheritTable
+			} else if _, ok = inherits[fmt.Sprintf("%s.%s", schemaName, tableName)]; !ok {
+				inherits[fmt.Sprintf("%s.%s", schemaName, tableName)] = [][3]string{}
+			}
+			inherits[fmt.Sprintf("%s.%s", schemaName, tableName)] = append(inherits[fmt.Sprintf("%s.%s", schemaName, tableName)], [3]string{inheritTable, inheritKey, inheritBounds})
+			inherited[fmt.Sprintf("%s.%s", schemaName, inheritTable)] = tableName
 		}
-		inherits[fmt.Sprintf("%s.%s", schemaName, tableName)] = append(inherits[fmt.Sprintf("%s.%s", schemaName, tableName)], [3]string{inheritTable, inheritKey, inheritBounds})
-		inherited[fmt.Sprintf("%s.%s", schemaName, inheritTable)] = tableName
 	}
 
 	//get enums
-	rows, err = squirrel.Select("pn.nspname", "pt.typname", "pe.enumlabel").
+	rows, err := squirrel.Select("pn.nspname", "pt.typname", "pe.enumlabel").
 		PlaceholderFormat(squirrel.Dollar).
 		From("pg_catalog.pg_namespace pn").
 		Join("pg_catalog.pg_type pt on pt.typnamespace = pn.oid").
@@ -133,69 +235,75 @@ func (d postgresqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string)
 		enums[enumName] = append(enums[enumName], enumValue)
 	}
 
-	//get types
-	rows, err = squirrel.Select("pn.nspname", "pt.typname", "pa.attname", "attT.typname", "attTn.nspname").
-		PlaceholderFormat(squirrel.Dollar).
-		From("pg_catalog.pg_type pt").
-		Join("pg_catalog.pg_attribute pa on pt.typrelid = pa.attrelid").
-		Join("pg_catalog.pg_namespace pn ON pn.oid = pt.typnamespace").
-		Join("pg_catalog.pg_type attT on attT.\"oid\" = pa.atttypid").
-		LeftJoin("pg_catalog.pg_namespace attTn on attTn.oid = attT.typnamespace").
-		LeftJoin("information_schema.tables t on t.table_name =pt.typname and t.table_schema =pn.nspname").
-		Where(squirrel.And{
-			squirrel.Eq{"pt.typcategory": "C"},
-			squirrel.NotEq{"pn.nspname": []string{"information_schema", "pg_catalog"}},
-			squirrel.Gt{"pa.attnum": 0},
-			squirrel.Eq{"t.table_name": nil},
-		}).
-		OrderBy("pn.nspname", "pt.typname", "pa.attnum").
-		RunWith(db).Query()
+	//get types, composite types of cockroachdb are not in pg_attribute
+	if d.compat != pgCompatCockroachdb {
+		rows, err = squirrel.Select("pn.nspname", "pt.typname", "pa.attname", "attT.typname", "attTn.nspname").
+			PlaceholderFormat(squirrel.Dollar).
+			From("pg_catalog.pg_type pt").
+			Join("pg_catalog.pg_attribute pa on pt.typrelid = pa.attrelid").
+			Join("pg_catalog.pg_namespace pn ON pn.oid = pt.typnamespace").
+			Join("pg_catalog.pg_type attT on attT.\"oid\" = pa.atttypid").
+			LeftJoin("pg_catalog.pg_namespace attTn on attTn.oid = attT.typnamespace").
+			LeftJoin("information_schema.tables t on t.table_name =pt.typname and t.table_schema =pn.nspname").
+			Where(squirrel.And{
+				squirrel.Eq{"pt.typcategory": "C"},
+				squirrel.NotEq{"pn.nspname": []string{"information_schema", "pg_catalog"}},
+				squirrel.Gt{"pa.attnum": 0},
+				squirrel.Eq{"t.table_name": nil},
+			}).
+			OrderBy("pn.nspname", "pt.typname", "pa.attnum").
+			RunWith(db).Query()
 
-	if err != nil {
-		panic(err)
-	}
-	for rows.Next() {
-		err = rows.Scan(&schemaName, &ctName, &ctAttrName, &ctAttrType, &ctAttrTypeSchema)
 		if err != nil {
 			panic(err)
 		}
-		imports := map[string]struct{}{}
-		dummyCol := &preformShare.Column{Type: ctAttrType, Nullable: false, Table: &preformShare.Table{Imports: imports, Scheme: &preformShare.Scheme{Imports: imports}}}
-		pgCalcGoType(dummyCol, schemaName, map[string]map[string][]string{}, map[string]map[string]*preformShare.CustomType{})
-		if ctAttrType != "json" && ctAttrType != "jsonb" && ctAttrType != "_json" && ctAttrType != "_jsonb" {
-			for {
-				if ctAttrTypeSchema.Valid && ctAttrTypeSchema.String != "pg_catalog" && ctAttrTypeSchema.String != "information_schema" {
-					if enums, ok = enumsBySchema[ctAttrTypeSchema.String]; ok {
-						if _, ok = enums[ctAttrType]; ok {
-							dummyCol.GoType = fmt.Sprintf("%s%s", strcase.ToCamel(ctAttrTypeSchema.String), strcase.ToCamel(ctAttrType))
-							break
+		for rows.Next() {
+			err = rows.Scan(&schemaName, &ctName, &ctAttrName, &ctAttrType, &ctAttrTypeSchema)
+			if err != nil {
+				panic(err)
+			}
+			imports := map[string]struct{}{}
+			dummyCol := &preformShare.Column{Type: ctAttrType, Nullable: false, Table: &preformShare.Table{Imports: imports, Scheme: &preformShare.Scheme{Imports: imports}}}
+			pgCalcGoType(dummyCol, schemaName, map[string]map[string][]string{}, map[string]map[string]*preformShare.CustomType{})
+			if ctAttrType != "json" && ctAttrType != "jsonb" && ctAttrType != "_json" && ctAttrType != "_jsonb" {
+				for {
+					if ctAttrTypeSchema.Valid && ctAttrTypeSchema.String != "pg_catalog" && ctAttrTypeSchema.String != "information_schema" {
+						if enums, ok = enumsBySchema[ctAttrTypeSchema.String]; ok {
+							if _, ok = enums[ctAttrType]; ok {
+								dummyCol.GoType = fmt.Sprintf("%s%s", strcase.ToCamel(ctAttrTypeSchema.String), strcase.ToCamel(ctAttrType))
+								break
+							}
 						}
 					}
+					if strings.Contains(dummyCol.GoType, "[any]") {
+						dummyCol.GoType = strings.Replace(dummyCol.GoType, "[any]", fmt.Sprintf("[%s%s]", strcase.ToCamel(schemaName), strcase.ToCamel(ctAttrType)), 1)
+					} else if dummyCol.GoType == "any" {
+						dummyCol.GoType = fmt.Sprintf("%s%s", strcase.ToCamel(schemaName), strcase.ToCamel(ctAttrType))
+					}
+					break
 				}
-				if strings.Contains(dummyCol.GoType, "[any]") {
-					dummyCol.GoType = strings.Replace(dummyCol.GoType, "[any]", fmt.Sprintf("[%s%s]", strcase.ToCamel(schemaName), strcase.ToCamel(ctAttrType)), 1)
-				} else if dummyCol.GoType == "any" {
-					dummyCol.GoType = fmt.Sprintf("%s%s", strcase.ToCamel(schemaName), strcase.ToCamel(ctAttrType))
-				}
-				break
 			}
-		}
-		if schemaName != lastCtSchema || ctName != lastCtName {
-			lastCtSchema = schemaName
-			lastCtName = ctName
-			if _, ok = ctBySchema[schemaName]; !ok {
-				ctBySchema[schemaName] = map[string]*preformShare.CustomType{}
+			dbType := ctAttrType
+			if ctAttrTypeSchema.Valid && ctAttrTypeSchema.String != "pg_catalog" && ctAttrTypeSchema.String != "information_schema" {
+				dbType = fmt.Sprintf(`"%s"."%s"`, ctAttrTypeSchema.String, ctAttrType)
 			}
-			ctBySchema[schemaName][ctName] = &preformShare.CustomType{Name: ctName, Attr: []*preformShare.CustomTypeAttr{{Name: ctAttrName, Type: dummyCol.GoType, NotNull: true, IsScanner: dummyCol.IsScanner}}, Imports: imports}
-		} else {
-			ctBySchema[schemaName][ctName].Attr = append(ctBySchema[schemaName][ctName].Attr, &preformShare.CustomTypeAttr{Name: ctAttrName, Type: dummyCol.GoType, NotNull: true, IsScanner: dummyCol.IsScanner})
-			for imp := range imports {
-				ctBySchema[schemaName][ctName].Imports[imp] = struct{}{}
+			if schemaName != lastCtSchema || ctName != lastCtName {
+				lastCtSchema = schemaName
+				lastCtName = ctName
+				if _, ok = ctBySchema[schemaName]; !ok {
+					ctBySchema[schemaName] = map[string]*preformShare.CustomType{}
+				}
+				ctBySchema[schemaName][ctName] = &preformShare.CustomType{Name: ctName, Attr: []*preformShare.CustomTypeAttr{{Name: ctAttrName, Type: dummyCol.GoType, DbType: dbType, NotNull: true, IsScanner: dummyCol.IsScanner}}, Imports: imports}
+			} else {
+				ctBySchema[schemaName][ctName].Attr = append(ctBySchema[schemaName][ctName].Attr, &preformShare.CustomTypeAttr{Name: ctAttrName, Type: dummyCol.GoType, DbType: dbType, NotNull: true, IsScanner: dummyCol.IsScanner})
+				for imp := range imports {
+					ctBySchema[schemaName][ctName].Imports[imp] = struct{}{}
+				}
 			}
 		}
 	}
 
-	schemaQ := squirrel.Select("table_schema", "table_name", "obj_description(concat(table_schema, '.', table_name)::regclass)").PlaceholderFormat(squirrel.Dollar).From("information_schema.tables")
+	schemaQ := squirrel.Select("table_schema", "table_name", fmt.Sprintf("obj_description(%s)", d.regclass("table_schema", "table_name"))).PlaceholderFormat(squirrel.Dollar).From("information_schema.tables")
 	if len(schemasEmptyIsAll) != 0 {
 		schemaQ = schemaQ.Where(squirrel.Eq{"table_schema": schemasEmptyIsAll})
 	}
@@ -226,11 +334,11 @@ func (d postgresqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string)
 		return true
 	}
 
-	schemaQ = squirrel.Select("table_schema", "table_name", "obj_description(concat(table_schema, '.', table_name)::regclass)").PlaceholderFormat(squirrel.Dollar).From("information_schema.tables")
+	schemaQ = squirrel.Select("table_schema", "table_name", fmt.Sprintf("obj_description(%s)", d.regclass("table_schema", "table_name"))).PlaceholderFormat(squirrel.Dollar).From("information_schema.tables")
 	if len(schemasEmptyIsAll) != 0 {
 		schemaQ = schemaQ.Where(squirrel.Eq{"table_schema": schemasEmptyIsAll})
 	}
-	schemaQ = schemaQ.Where(squirrel.And{squirrel.Eq{"table_type": "BASE TABLE"}, squirrel.NotEq{"table_schema": []string{"pg_catalog", "information_schema"}}})
+	schemaQ = schemaQ.Where(squirrel.And{squirrel.Eq{"table_type": "BASE TABLE"}, squirrel.NotEq{"table_schema": d.systemSchemas()}})
 	rows, err = schemaQ.RunWith(db).Query()
 	if err != nil {
 		q, args, _ := schemaQ.ToSql()
@@ -250,7 +358,7 @@ func (d postgresqlDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string)
 	if len(schemasEmptyIsAll) != 0 {
 		schemaQ = schemaQ.Where(squirrel.Eq{"table_schema": schemasEmptyIsAll})
 	}
-	schemaQ = schemaQ.Where(squirrel.NotEq{"table_schema": []string{"pg_catalog", "information_schema"}})
+	schemaQ = schemaQ.Where(squirrel.NotEq{"table_schema": d.systemSchemas()})
 	rows, err = schemaQ.RunWith(db).Query()
 	if err != nil {
 		q, args, _ := schemaQ.ToSql()
@@ -294,7 +402,7 @@ func (d postgresqlDialect) getTableDetails(db *sql.DB, tableByName map[string]*p
 		"ccu.table_schema",
 		"ccu.table_name",
 		"ccu.column_name",
-		"pg_catalog.col_description(concat(c.table_schema, '.', c.table_name)::regclass,c.dtd_identifier::int) \"comment\"",
+		d.columnComment(),
 		"k.ordinal_position",
 		"k.position_in_unique_constraint",
 		"tc.constraint_type",
@@ -318,6 +426,9 @@ func (d postgresqlDialect) getTableDetails(db *sql.DB, tableByName map[string]*p
 			"k.ordinal_position",
 			"k.position_in_unique_constraint", "c.ordinal_position", "tc.constraint_type").
 		OrderBy("c.table_schema", "c.table_name", "c.ordinal_position")
+	if d.compat == pgCompatCockroachdb {
+		q = q.Where("c.is_hidden = 'NO'") //rowid of tables without primary key
+	}
 	rows, err := q.RunWith(db).Query()
 	if err != nil {
 		panic(err)
@@ -335,7 +446,7 @@ func (d postgresqlDialect) getTableDetails(db *sql.DB, tableByName map[string]*p
 				table.ColumnByName[colName] = col
 			}
 			if col.Type == "" {
-				if strings.HasPrefix(colDefault.String, "nextval(") || strings.HasPrefix(colDefault.String, "gen_random_uuid()") {
+				if pgIsAutoKeyDefault(colDefault.String) {
 					col.IsAutoKey = true
 				} else {
 					col.DefaultValue = colDefault
@@ -0,0 +1,89 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// pgCompat databases speaking the postgres protocol with a catalog of their own
+type pgCompat uint8
+
+const (
+	pgCompatNone pgCompat = iota
+	pgCompatCockroachdb
+	pgCompatYugabytedb
+)
+
+// NewCockroachdbDialect postgresql without partitions and composite types introspection, transactions retry by SAVEPOINT cockroach_restart
+func NewCockroachdbDialect() *postgresqlDialect {
+	d := NewPostgresqlDialect()
+	d.compat = pgCompatCockroachdb
+	return d
+}
+
+// NewYugabytedbDialect postgresql without replica lag, conflicts abort the whole transaction
+func NewYugabytedbDialect() *postgresqlDialect {
+	d := NewPostgresqlDialect()
+	d.compat = pgCompatYugabytedb
+	return d
+}
+
+// DetectPostgresqlDialect tell cockroachdb and yugabytedb from postgresql by version()
+func DetectPostgresqlDialect(ctx context.Context, db *sql.DB) (*postgresqlDialect, error) {
+	var (
+		version string
+	)
+	if err := db.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.Contains(version, "CockroachDB"):
+		return NewCockroachdbDialect(), nil
+	case strings.Contains(version, "-YB-"):
+		return NewYugabytedbDialect(), nil
+	}
+	return NewPostgresqlDialect(), nil
+}
+
+// RestartSavepoint cockroachdb only, yugabytedb retries by a new transaction
+func (d postgresqlDialect) RestartSavepoint() (name string, err error) {
+	if d.compat == pgCompatCockroachdb {
+		return "cockroach_restart", nil
+	}
+	return "", ErrorNotSupport
+}
+
+// regclass quoted for the compatible ones, they don't take reserved words unquoted
+func (d postgresqlDialect) regclass(schemaCol, tableCol string) string {
+	if d.compat == pgCompatNone {
+		return fmt.Sprintf("concat(%s, '.', %s)::regclass", schemaCol, tableCol)
+	}
+	return fmt.Sprintf("(quote_ident(%s) || '.' || quote_ident(%s))::regclass", schemaCol, tableCol)
+}
+
+// columnComment dtd_identifier is not the attnum of cockroachdb
+func (d postgresqlDialect) columnComment() string {
+	if d.compat == pgCompatNone {
+		return fmt.Sprintf("pg_catalog.col_description(%s,c.dtd_identifier::int) \"comment\"", d.regclass("c.table_schema", "c.table_name"))
+	}
+	return fmt.Sprintf("(SELECT pg_catalog.col_description(a.attrelid, a.attnum) FROM pg_catalog.pg_attribute a WHERE a.attrelid = %s AND a.attname = c.column_name) \"comment\"", d.regclass("c.table_schema", "c.table_name"))
+}
+
+func (d postgresqlDialect) systemSchemas() []string {
+	if d.compat == pgCompatCockroachdb {
+		return []string{"pg_catalog", "information_schema", "crdb_internal", "pg_extension"}
+	}
+	return []string{"pg_catalog", "information_schema"}
+}
+
+// pgIsAutoKeyDefault sequences, unique_rowid() of cockroachdb and generated uuid
+func pgIsAutoKeyDefault(colDefault string) bool {
+	for _, prefix := range []string{"nextval(", "unique_rowid()", "gen_random_uuid()", "uuid_generate_v4()"} {
+		if strings.HasPrefix(colDefault, prefix) {
+			return true
+		}
+	}
+	return false
+}
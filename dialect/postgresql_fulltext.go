@@ -0,0 +1,15 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+)
+
+// FullTextMatch text as plain words, with the default text search config
+func (d postgresqlDialect) FullTextMatch(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return fmt.Sprintf("to_tsvector(%s) @@ plainto_tsquery(?)", col.GetCode()), []any{text}, nil
+}
+
+func (d postgresqlDialect) FullTextRank(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return fmt.Sprintf("ts_rank(to_tsvector(%s), plainto_tsquery(?))", col.GetCode()), []any{text}, nil
+}
@@ -0,0 +1,50 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+	"strings"
+)
+
+func (d postgresqlDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	if len(path) == 0 {
+		return col.GetCode(), nil
+	}
+	var (
+		sb = strings.Builder{}
+	)
+	sb.WriteString(col.GetCode())
+	for i, key := range path {
+		if i == len(path)-1 && kind != reflect.Invalid && kind != reflect.Map && kind != reflect.Struct && kind != reflect.Slice && kind != reflect.Interface {
+			sb.WriteString("->>")
+		} else {
+			sb.WriteString("->")
+		}
+		if jsonPathIdxRx.MatchString(key) {
+			sb.WriteString(key)
+		} else {
+			sb.WriteString(sqlStringLiteral(key))
+		}
+	}
+	switch {
+	case kind == reflect.Bool:
+		return fmt.Sprintf("(%s)::boolean", sb.String()), nil
+	case isJsonNumberKind(kind):
+		return fmt.Sprintf("(%s)::numeric", sb.String()), nil
+	}
+	return sb.String(), nil
+}
+
+func (d postgresqlDialect) JsonContains(col preformShare.ICol, value any) (query string, args []any, err error) {
+	arg, err := jsonArg(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s @> ?::jsonb", col.GetCode()), []any{arg}, nil
+}
+
+// JsonHasKey #> instead of ? operators, which conflict with placeholders
+func (d postgresqlDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("(%s #> %s) IS NOT NULL", col.GetCode(), sqlStringLiteral("{"+strings.Join(path, ",")+"}")), nil, nil
+}
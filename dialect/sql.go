@@ -7,18 +7,23 @@ import (
 	preformShare "github.com/go-preform/preform/share"
 	preformSqlizer "github.com/go-preform/preform/sqlizer"
 	"github.com/go-preform/squirrel"
+	"github.com/iancoleman/strcase"
 	"reflect"
 	"strings"
 )
 
 var (
-	ErrorNotSupport = errors.New("driver not support")
+	ErrorNotSupport       = errors.New("driver not support")
+	ErrorNoConflictTarget = errors.New("conflict columns required")
+	ErrorLockWaitOption   = errors.New("skip locked and nowait cannot be used together")
 )
 
 const (
 	LastInsertIdMethodByRes preformShare.SqlDialectLastInsertIdMethod = iota
 	LastInsertIdMethodBySuffix
 	LastInsertIdMethodNone
+	LastInsertIdMethodByOutput   //suffix goes between columns and VALUES
+	LastInsertIdMethodByOutParam //suffix binds the id to a sql.Out appended to args
 )
 
 type basicSqlDialect struct {
@@ -147,6 +152,198 @@ func (d basicSqlDialect) Between(col preformShare.ICol, v1, v2 any) squirrel.Sql
 	return preformSqlizer.Between{col.GetCode(): [2]any{v1, v2}}
 }
 
+func (d basicSqlDialect) NotBetween(col preformShare.ICol, v1, v2 any) squirrel.Sqlizer {
+	return preformSqlizer.NotBetween{col.GetCode(): [2]any{v1, v2}}
+}
+
+func (d basicSqlDialect) In(col preformShare.ICol, v any) squirrel.Sqlizer {
+	return preformSqlizer.In{Col: col.GetCode(), Value: v}
+}
+
+func (d basicSqlDialect) NotIn(col preformShare.ICol, v any) squirrel.Sqlizer {
+	return preformSqlizer.In{Col: col.GetCode(), Value: v, Not: true}
+}
+
+func (d basicSqlDialect) IsNull(col preformShare.ICol) squirrel.Sqlizer {
+	return squirrel.Eq{col.GetCode(): nil}
+}
+
+func (d basicSqlDialect) IsNotNull(col preformShare.ICol) squirrel.Sqlizer {
+	return squirrel.NotEq{col.GetCode(): nil}
+}
+
+func (d basicSqlDialect) NotLike(col preformShare.ICol, v any) squirrel.Sqlizer {
+	return squirrel.NotLike{col.GetCode(): v}
+}
+
+// ILike LOWER() on both sides for mysql sqlite clickhouse etc.
+func (d basicSqlDialect) ILike(col preformShare.ICol, v any) squirrel.Sqlizer {
+	return squirrel.Expr(fmt.Sprintf("LOWER(%s) LIKE LOWER(?)", col.GetCode()), v)
+}
+
 func (d basicSqlDialect) CaseStmtToSql(builder squirrel.CaseBuilder, col preformShare.ICol) (string, []any, error) {
 	return builder.ToSql()
 }
+
+func (d basicSqlDialect) Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, err error) {
+	return nil, d.lastInsertIdMethod, ErrorNotSupport
+}
+
+func (d basicSqlDialect) Returning(cols []string) (squirrel.Sqlizer, error) {
+	return nil, ErrorNotSupport
+}
+
+func (d basicSqlDialect) RowLock(lock preformShare.RowLock) (squirrel.Sqlizer, error) {
+	return nil, ErrorNotSupport
+}
+
+func (d basicSqlDialect) SetOperation(op string) (string, error) {
+	return op, nil
+}
+
+func (d basicSqlDialect) Savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	return "", "", "", ErrorNotSupport
+}
+
+func (d basicSqlDialect) IsRetryable(err error) bool {
+	return false
+}
+
+func (d basicSqlDialect) RestartSavepoint() (name string, err error) {
+	return "", ErrorNotSupport
+}
+
+func (d basicSqlDialect) ReplicaLag() (query string, err error) {
+	return "", ErrorNotSupport
+}
+
+func (d basicSqlDialect) Paging(limit, offset uint64, ordered bool) (squirrel.Sqlizer, error) {
+	return nil, nil
+}
+
+func (d basicSqlDialect) RowValueCompare() bool {
+	return true
+}
+
+// savepoint for postgresql mysql and sqlite
+func (d basicSqlDialect) savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	name = d.QuoteIdentifier(name)
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "RELEASE SAVEPOINT " + name, nil
+}
+
+// rowLock for postgresql and mysql 8
+func (d basicSqlDialect) rowLock(lock preformShare.RowLock) (squirrel.Sqlizer, error) {
+	var (
+		sql = "FOR UPDATE"
+	)
+	if lock.SkipLocked && lock.NoWait {
+		return nil, ErrorLockWaitOption
+	}
+	if lock.ForShare {
+		sql = "FOR SHARE"
+	}
+	if len(lock.Of) != 0 {
+		quoted := make([]string, len(lock.Of))
+		for i, alias := range lock.Of {
+			quoted[i] = d.QuoteIdentifier(alias)
+		}
+		sql += " OF " + strings.Join(quoted, ", ")
+	}
+	if lock.SkipLocked {
+		sql += " SKIP LOCKED"
+	} else if lock.NoWait {
+		sql += " NOWAIT"
+	}
+	return squirrel.Expr(sql), nil
+}
+
+// returning for postgresql sqlite and duckdb
+func (d basicSqlDialect) returning(cols []string) (squirrel.Sqlizer, error) {
+	var (
+		quoted = make([]string, len(cols))
+	)
+	for i, col := range cols {
+		quoted[i] = d.QuoteIdentifier(col)
+	}
+	return squirrel.Expr(fmt.Sprintf("RETURNING %s", strings.Join(quoted, ", "))), nil
+}
+
+// onConflictDoUpdate for postgresql sqlite and duckdb
+func (d basicSqlDialect) onConflictDoUpdate(conflictCols, updateCols []string, excluded string) (squirrel.Sqlizer, error) {
+	if len(conflictCols) == 0 {
+		return nil, ErrorNoConflictTarget
+	}
+	var (
+		targets = make([]string, len(conflictCols))
+		sets    = make([]string, 0, len(updateCols))
+	)
+	for i, col := range conflictCols {
+		targets[i] = d.QuoteIdentifier(col)
+	}
+	if len(updateCols) == 0 {
+		updateCols = conflictCols[:1] //no-op update, still hits the existing row
+	}
+	for _, col := range updateCols {
+		col = d.QuoteIdentifier(col)
+		sets = append(sets, fmt.Sprintf("%s = %s.%s", col, excluded, col))
+	}
+	return squirrel.Expr(fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(targets, ", "), strings.Join(sets, ", "))), nil
+}
+
+// commentForeignKeys fk:[schema.]table.col[:relation[:reverse]] in column comments, for mssql oracle and duckdb reading comments with the columns
+func (d basicSqlDialect) commentForeignKeys(tableByName map[string]*preformShare.Table, schemas map[string]struct{}) {
+	var (
+		ok            bool
+		fkTable       *preformShare.Table
+		fkCol         *preformShare.Column
+		fk            *preformShare.ForeignKey
+		settingParts  []string
+		parts         []string
+		commentFkName string
+	)
+	for _, table := range tableByName {
+		for _, col := range table.Columns {
+			if col.Comment == "" {
+				continue
+			}
+			for i, fkPart := range strings.Split(col.Comment, ";") {
+				if !strings.HasPrefix(fkPart, "fk:") {
+					continue
+				}
+				settingParts = strings.Split(fkPart, ":")
+				parts = strings.Split(settingParts[1], ".")
+				if len(parts) == 2 {
+					parts = append([]string{table.Scheme.Name}, parts...)
+				} else if len(parts) != 3 {
+					fmt.Println("ignore illegal fk comment:", col.Comment)
+					continue
+				}
+				if _, ok = schemas[parts[0]]; !ok {
+					continue
+				}
+				if fkTable, ok = tableByName[fmt.Sprintf("%s.%s", parts[0], parts[1])]; !ok {
+					continue
+				}
+				if fkCol, ok = fkTable.ColumnByName[parts[2]]; !ok {
+					fkCol = &preformShare.Column{Name: parts[2], Table: fkTable}
+					fkTable.Columns = append(fkTable.Columns, fkCol)
+					fkTable.ColumnByName[parts[2]] = fkCol
+				}
+				commentFkName = fmt.Sprintf("comment_%s_%d", col.Name, i)
+				if _, ok = table.ForeignKeys[commentFkName]; ok {
+					continue
+				}
+				fk = &preformShare.ForeignKey{Name: commentFkName}
+				table.ForeignKeys[fk.Name] = fk
+				col.ForeignKeys = append(col.ForeignKeys, fk)
+				fk.LocalKeys = append(fk.LocalKeys, col)
+				fk.ForeignKeys = append(fk.ForeignKeys, fkCol)
+				if len(settingParts) == 4 {
+					fk.RelationName, fk.ReverseName = strcase.ToCamel(settingParts[2]), strcase.ToCamel(settingParts[3])
+				} else if len(settingParts) == 3 {
+					fk.RelationName = strcase.ToCamel(settingParts[2])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"sort"
+	"strings"
+)
+
+// ddlStyle differences of postgresql mysql and sqlite in ddl
+type ddlStyle struct {
+	autoKey         func(col *preformShare.Column) string //column definition of an auto increment pk
+	colType         func(t string) string                 //complete the type from GetStructure if needed
+	dropForeignKey  string                                //DROP CONSTRAINT / DROP FOREIGN KEY
+	fkWithoutSchema bool                                  //sqlite references tables in the same database only
+	noAlterTable    bool                                  //sqlite can't add or drop constraints
+	addColumn       string                                //ADD COLUMN / ADD
+}
+
+func (d basicSqlDialect) Ddl(ddl preformShare.Ddl) (string, error) {
+	return "", ErrorNotSupport
+}
+
+func (d basicSqlDialect) tableName(table *preformShare.Table) string {
+	if table.Scheme == nil || table.Scheme.Name == "" {
+		return d.QuoteIdentifier(table.Name)
+	}
+	return d.QuoteIdentifier(table.Scheme.Name) + "." + d.QuoteIdentifier(table.Name)
+}
+
+func (d basicSqlDialect) columnDef(col *preformShare.Column, style ddlStyle) string {
+	if col.IsAutoKey && style.autoKey != nil {
+		return style.autoKey(col)
+	}
+	var (
+		t = col.Type
+	)
+	if style.colType != nil {
+		t = style.colType(t)
+	}
+	def := d.QuoteIdentifier(col.Name) + " " + t
+	if !col.Nullable {
+		def += " NOT NULL"
+	}
+	if col.DefaultValue.Valid {
+		def += " DEFAULT " + col.DefaultValue.String
+	}
+	return def
+}
+
+func (d basicSqlDialect) foreignKeyDef(fk *preformShare.ForeignKey, style ddlStyle) string {
+	var (
+		localKeys   = make([]string, len(fk.LocalKeys))
+		foreignKeys = make([]string, len(fk.ForeignKeys))
+		target      = d.tableName(fk.ForeignKeys[0].Table)
+	)
+	for i, col := range fk.LocalKeys {
+		localKeys[i] = d.QuoteIdentifier(col.Name)
+	}
+	for i, col := range fk.ForeignKeys {
+		foreignKeys[i] = d.QuoteIdentifier(col.Name)
+	}
+	if style.fkWithoutSchema {
+		target = d.QuoteIdentifier(fk.ForeignKeys[0].Table.Name)
+	}
+	return fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", d.QuoteIdentifier(fk.Name), strings.Join(localKeys, ", "), target, strings.Join(foreignKeys, ", "))
+}
+
+// ddl for postgresql mysql sqlite and mssql, alter column is left to the dialect
+func (d basicSqlDialect) ddl(ddl preformShare.Ddl, style ddlStyle) (string, error) {
+	switch ddl.Kind {
+	case preformShare.DdlCreateTable:
+		var (
+			defs    = make([]string, 0, len(ddl.Table.Columns)+1)
+			pks     []string
+			fkNames = make([]string, 0, len(ddl.Table.ForeignKeys))
+		)
+		for _, col := range ddl.Table.Columns {
+			def := d.columnDef(col, style)
+			defs = append(defs, def)
+			if col.IsPrimaryKey && !strings.Contains(def, "PRIMARY KEY") {
+				pks = append(pks, d.QuoteIdentifier(col.Name))
+			}
+		}
+		if len(pks) != 0 {
+			defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pks, ", ")))
+		}
+		for name := range ddl.Table.ForeignKeys {
+			fkNames = append(fkNames, name)
+		}
+		sort.Strings(fkNames)
+		for _, name := range fkNames {
+			defs = append(defs, d.foreignKeyDef(ddl.Table.ForeignKeys[name], style))
+		}
+		return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n)", d.tableName(ddl.Table), strings.Join(defs, ",\n\t")), nil
+	case preformShare.DdlDropTable:
+		return "DROP TABLE " + d.tableName(ddl.Table), nil
+	case preformShare.DdlAddColumn:
+		if style.addColumn == "" {
+			style.addColumn = "ADD COLUMN"
+		}
+		return fmt.Sprintf("ALTER TABLE %s %s %s", d.tableName(ddl.Table), style.addColumn, d.columnDef(ddl.Column, style)), nil
+	case preformShare.DdlDropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.tableName(ddl.Table), d.QuoteIdentifier(ddl.Column.Name)), nil
+	case preformShare.DdlAddForeignKey:
+		if style.noAlterTable {
+			return "", ErrorNotSupport
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", d.tableName(ddl.Table), d.foreignKeyDef(ddl.ForeignKey, style)), nil
+	case preformShare.DdlDropForeignKey:
+		if style.noAlterTable {
+			return "", ErrorNotSupport
+		}
+		return fmt.Sprintf("ALTER TABLE %s %s %s", d.tableName(ddl.Table), style.dropForeignKey, d.QuoteIdentifier(ddl.ForeignKey.Name)), nil
+	}
+	return "", ErrorNotSupport
+}
@@ -0,0 +1,13 @@
+package dialect
+
+import (
+	preformShare "github.com/go-preform/preform/share"
+)
+
+func (d basicSqlDialect) FullTextMatch(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return "", nil, ErrorNotSupport
+}
+
+func (d basicSqlDialect) FullTextRank(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return "", nil, ErrorNotSupport
+}
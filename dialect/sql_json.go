@@ -0,0 +1,69 @@
+package dialect
+
+import (
+	"encoding/json"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	jsonPathKeyRx = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	jsonPathIdxRx = regexp.MustCompile(`^[0-9]+$`)
+)
+
+func (d basicSqlDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	return "", ErrorNotSupport
+}
+
+func (d basicSqlDialect) JsonContains(col preformShare.ICol, value any) (query string, args []any, err error) {
+	return "", nil, ErrorNotSupport
+}
+
+func (d basicSqlDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return "", nil, ErrorNotSupport
+}
+
+// jsonPathLiteral $.a[0]."b c" as a quoted sql string
+func jsonPathLiteral(path []string) string {
+	var (
+		sb = strings.Builder{}
+	)
+	sb.WriteString("$")
+	for _, key := range path {
+		switch {
+		case jsonPathIdxRx.MatchString(key):
+			sb.WriteString(fmt.Sprintf("[%s]", key))
+		case jsonPathKeyRx.MatchString(key):
+			sb.WriteString("." + key)
+		default:
+			sb.WriteString(fmt.Sprintf(`."%s"`, strings.ReplaceAll(key, `"`, `\"`)))
+		}
+	}
+	return sqlStringLiteral(sb.String())
+}
+
+func sqlStringLiteral(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
+
+// jsonArg go values to json string, json.RawMessage is validated and compacted
+func jsonArg(value any) (any, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func isJsonNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -27,6 +27,48 @@ func (d sqliteDialect) DefaultValueExpr() squirrel.Sqlizer {
 	return nil
 }
 
+// Upsert last_insert_rowid() is not updated by DO UPDATE, let the caller look the id up
+func (d sqliteDialect) Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, err error) {
+	suffix, err = d.onConflictDoUpdate(conflictCols, updateCols, "excluded")
+	return suffix, LastInsertIdMethodNone, err
+}
+
+// Returning sqlite 3.35+
+func (d sqliteDialect) Returning(cols []string) (squirrel.Sqlizer, error) {
+	return d.returning(cols)
+}
+
+// RowLock sqlite locks the whole database on write, row lock is ignored
+func (d sqliteDialect) RowLock(lock preformShare.RowLock) (squirrel.Sqlizer, error) {
+	if lock.SkipLocked && lock.NoWait {
+		return nil, ErrorLockWaitOption
+	}
+	return nil, nil
+}
+
+func (d sqliteDialect) Savepoint(name string) (savepoint, rollbackTo, release string, err error) {
+	return d.savepoint(name)
+}
+
+// IsRetryable SQLITE_BUSY and SQLITE_LOCKED, matched by message to keep cgo out of the dialect
+func (d sqliteDialect) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
+
+// Ddl sqlite can't alter columns or constraints of existing tables, foreign keys are created with the table
+func (d sqliteDialect) Ddl(ddl preformShare.Ddl) (string, error) {
+	if ddl.Kind == preformShare.DdlAlterColumn {
+		return "", ErrorNotSupport
+	}
+	return d.ddl(ddl, ddlStyle{fkWithoutSchema: true, noAlterTable: true, autoKey: func(col *preformShare.Column) string {
+		return d.QuoteIdentifier(col.Name) + " INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL"
+	}})
+}
+
 func (d sqliteDialect) GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*preformShare.Scheme {
 
 	var (
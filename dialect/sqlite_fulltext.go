@@ -0,0 +1,17 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"strings"
+)
+
+// FullTextMatch the column must be of a FTS5 virtual table, text in FTS5 query syntax
+func (d sqliteDialect) FullTextMatch(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return fmt.Sprintf("%s MATCH ?", col.GetCode()), []any{text}, nil
+}
+
+// FullTextRank bm25 of the row matched in where, negated as bm25 is lower for more relevant
+func (d sqliteDialect) FullTextRank(col preformShare.ICol, text string) (query string, args []any, err error) {
+	return fmt.Sprintf("-bm25(%s)", strings.TrimSuffix(col.GetCode(), "."+d.QuoteIdentifier(col.DbName()))), nil, nil
+}
@@ -0,0 +1,16 @@
+package dialect
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+)
+
+// JsonExtract sqlite returns sql values for scalars already
+func (d sqliteDialect) JsonExtract(col preformShare.ICol, path []string, kind reflect.Kind) (query string, err error) {
+	return fmt.Sprintf("JSON_EXTRACT(%s, %s)", col.GetCode(), jsonPathLiteral(path)), nil
+}
+
+func (d sqliteDialect) JsonHasKey(col preformShare.ICol, path []string) (query string, args []any, err error) {
+	return fmt.Sprintf("JSON_TYPE(%s, %s) IS NOT NULL", col.GetCode(), jsonPathLiteral(path)), nil, nil
+}
@@ -86,6 +86,9 @@ type factory[FPtr IFactory, B any] struct {
 	setter              any //func(s ISchema)
 	fieldsByName        map[string]preformShare.IField
 	modelScanner        IModelScanner[B]
+	softDelete          ICol
+	softDeleteValue     func() any
+	version             ICol
 }
 
 func (f factory[FPtr, B]) NewBody() any {
@@ -330,6 +333,12 @@ func SelectByFactory[B any](f IQuery, modelScanner IModelScanner[B], cols ...any
 		}
 	)
 	_, q.forceBodyScan = any(body).(iForceBodyScan)
+	if sd, ok := f.(iSoftDelete); ok {
+		if col := sd.SoftDeleteColumn(); col != nil {
+			q.trashed = &trashedCond{col: col}
+			q.selectBuilder = q.selectBuilder.Where(q.trashed)
+		}
+	}
 	if len(cols) > 0 {
 		q = q.Columns(cols...)
 	}
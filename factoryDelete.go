@@ -18,15 +18,21 @@ func (f *Factory[FPtr, B]) DeleteByPk(body any, cfgs ...EditConfig) (Deleted int
 	if len(cfgs) != 0 {
 		cfg = cfgs[0]
 	}
-	if cfg.Tx != nil {
-		exec = cfg.Tx
-	}
 	if cfg.Ctx != nil {
 		ctx = cfg.Ctx
 	}
+	if cfg.Tx == nil {
+		cfg.Tx = txOfDb(ctx, db)
+	}
+	if cfg.Tx != nil {
+		exec = cfg.Tx
+	}
 	if f.fixCond != nil {
 		query = query.Where(noTableCodeWhere(f.fixCond))
 	}
+	if err = runHook(hookBeforeDelete, body, ctx, cfg.Tx); err != nil {
+		return 0, err
+	}
 	if cfg.Cascading {
 		if len(f.relations) != 0 {
 			var (
@@ -48,8 +54,19 @@ func (f *Factory[FPtr, B]) DeleteByPk(body any, cfgs ...EditConfig) (Deleted int
 			}
 		}
 	}
-	query = query.Where(f.PkCondByBody(mBody))
-	q, args, err := db.dialect.DeleteSqlizer(query)
+	var (
+		q    string
+		args []any
+	)
+	if f.factory.softDelete != nil && !cfg.ForceDelete {
+		softQuery := f.softDeleteQuery()
+		if f.fixCond != nil {
+			softQuery = softQuery.Where(noTableCodeWhere(f.fixCond))
+		}
+		q, args, err = db.dialect.UpdateSqlizer(softQuery.Where(f.PkCondByBody(mBody)))
+	} else {
+		q, args, err = db.dialect.DeleteSqlizer(query.Where(f.PkCondByBody(mBody)))
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -61,44 +78,81 @@ func (f *Factory[FPtr, B]) DeleteByPk(body any, cfgs ...EditConfig) (Deleted int
 	if err != nil {
 		return 0, err
 	}
+	if err = runHook(hookAfterDelete, body, ctx, cfg.Tx); err != nil {
+		return 0, err
+	}
 	return
 }
 
 type DeleteBuilder[B any] struct {
-	Builder  preformShare.DeleteBuilder
-	factory  IFactory
-	execer   DB
-	hasWhere bool
-	bodyCols []ICol
-	bodies   []*B
-	ctx      context.Context
+	Builder     preformShare.DeleteBuilder
+	factory     IFactory
+	execer      DB
+	hasWhere    bool
+	bodyCols    []ICol
+	bodies      []*B
+	ctx         context.Context
+	softDelete  bool
+	softBuilder preformShare.UpdateBuilder
+	err         error
 }
 
 func (f *Factory[FPtr, B]) Delete() DeleteBuilder[B] {
-	b := f.Db().sqStmtBuilder.DeleteFast(strings.Split(f.fromClause(), " AS ")[0])
+	var (
+		b  = f.Db().sqStmtBuilder.DeleteFast(strings.Split(f.fromClause(), " AS ")[0])
+		db = DeleteBuilder[B]{factory: f.Definition, execer: f.Db()}
+	)
+	if f.factory.softDelete != nil {
+		db.softDelete = true
+		db.softBuilder = f.softDeleteQuery()
+		if f.fixCond != nil {
+			db.softBuilder = db.softBuilder.Where(f.fixCond)
+		}
+	}
 	if f.fixCond != nil {
 		b = b.Where(f.fixCond)
 	}
-	return DeleteBuilder[B]{Builder: b, factory: f.Definition, execer: f.Db()}
+	db.Builder = b
+	return db
 }
 
+// Ctx run with the transaction in ctx if any, see WithTx
 func (b DeleteBuilder[B]) Ctx(ctx context.Context) DeleteBuilder[B] {
 	b.ctx = ctx
+	if tx := txOfDb(ctx, b.factory.Db()); tx != nil {
+		b.execer = tx
+	}
 	return b
 }
 
 func (b DeleteBuilder[B]) Where(cond ICond) DeleteBuilder[B] {
-	b.Builder = b.Builder.Where(noTableCodeWhere(cond))
+	cond = noTableCodeWhere(cond)
+	b.Builder = b.Builder.Where(cond)
+	if b.softDelete {
+		b.softBuilder = b.softBuilder.Where(cond)
+	}
 	b.hasWhere = true
 	return b
 }
 
 func (b DeleteBuilder[B]) LimitOffset(limit, offset uint64) DeleteBuilder[B] {
+	if b.err = limitOffsetErr(b.factory.Db().dialect, limit, offset); b.err != nil {
+		return b
+	}
 	b.Builder = b.Builder.Limit(limit).Offset(offset)
+	if b.softDelete {
+		b.softBuilder = b.softBuilder.Limit(limit).Offset(offset)
+	}
 	return b
 }
 
 func (b DeleteBuilder[B]) ToSql() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if b.softDelete {
+		return b.factory.Db().dialect.UpdateSqlizer(b.softBuilder)
+	}
 	return b.factory.Db().dialect.DeleteSqlizer(b.Builder)
 }
 
@@ -119,3 +173,46 @@ func (b DeleteBuilder[B]) Exec(tx ...preformShare.QueryRunner) (int64, error) {
 	}
 	return res.RowsAffected()
 }
+
+// ExecReturning delete and scan the returning cols into bodies, all columns if cols is empty
+// AfterDelete hooks run on the returned bodies, BeforeDelete hooks only run with DeleteByPk as there are no bodies before the delete
+func (b DeleteBuilder[B]) ExecReturning(cols []ICol, tx ...preformShare.QueryRunner) ([]B, error) {
+	return b.execReturning(cols, false, tx)
+}
+
+// ExecReturningFast scan with ScanBodiesFast
+func (b DeleteBuilder[B]) ExecReturningFast(cols []ICol, tx ...preformShare.QueryRunner) ([]B, error) {
+	return b.execReturning(cols, true, tx)
+}
+
+func (b DeleteBuilder[B]) execReturning(cols []ICol, fast bool, tx []preformShare.QueryRunner) ([]B, error) {
+	if len(cols) == 0 {
+		cols = b.factory.Columns()
+	}
+	suffix, err := returningSuffix(b.factory, cols)
+	if err != nil {
+		return nil, err
+	}
+	b.Builder = b.Builder.Suffix(suffix)
+	if b.softDelete {
+		b.softBuilder = b.softBuilder.Suffix(suffix)
+	}
+	q, args, err := b.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if b.ctx == nil {
+		b.ctx = b.factory.Db().ctx
+	}
+	if len(tx) == 0 {
+		tx = []preformShare.QueryRunner{b.execer}
+	}
+	res, err := queryReturning[B](b.ctx, tx[0], b.factory, cols, fast, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err = runHooks[B](hookAfterDelete, res, b.ctx, txOfRunner(tx[0])); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
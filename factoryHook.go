@@ -0,0 +1,106 @@
+package preform
+
+import (
+	"context"
+)
+
+// lifecycle hooks, implement on the body pointer in the model package, e.g.
+//
+//	func (b *UserBody) BeforeInsert(ctx context.Context, tx *preform.Tx) error {
+//		b.CreatedAt = time.Now()
+//		return nil
+//	}
+//
+// tx is EditConfig.Tx, nil if not in a transaction, return an error to abort the operation
+// called by InsertOne UpdateByPk DeleteByPk, InsertBatch and UpdateBuilder.SetBodies, and for each related body while cascading
+// builders run them only on the bodies they have, Exec of Update and Delete without SetBodies runs none, DeleteBuilder.ExecReturning runs AfterDelete on the returned bodies
+type IBeforeInsert interface {
+	BeforeInsert(ctx context.Context, tx *Tx) error
+}
+
+type IAfterInsert interface {
+	AfterInsert(ctx context.Context, tx *Tx) error
+}
+
+type IBeforeUpdate interface {
+	BeforeUpdate(ctx context.Context, tx *Tx) error
+}
+
+type IAfterUpdate interface {
+	AfterUpdate(ctx context.Context, tx *Tx) error
+}
+
+type IBeforeDelete interface {
+	BeforeDelete(ctx context.Context, tx *Tx) error
+}
+
+type IAfterDelete interface {
+	AfterDelete(ctx context.Context, tx *Tx) error
+}
+
+type hookPoint uint8
+
+const (
+	hookBeforeInsert hookPoint = iota
+	hookAfterInsert
+	hookBeforeUpdate
+	hookAfterUpdate
+	hookBeforeDelete
+	hookAfterDelete
+)
+
+func runHook(point hookPoint, body any, ctx context.Context, tx *Tx) error {
+	switch point {
+	case hookBeforeInsert:
+		if h, ok := body.(IBeforeInsert); ok {
+			return h.BeforeInsert(ctx, tx)
+		}
+	case hookAfterInsert:
+		if h, ok := body.(IAfterInsert); ok {
+			return h.AfterInsert(ctx, tx)
+		}
+	case hookBeforeUpdate:
+		if h, ok := body.(IBeforeUpdate); ok {
+			return h.BeforeUpdate(ctx, tx)
+		}
+	case hookAfterUpdate:
+		if h, ok := body.(IAfterUpdate); ok {
+			return h.AfterUpdate(ctx, tx)
+		}
+	case hookBeforeDelete:
+		if h, ok := body.(IBeforeDelete); ok {
+			return h.BeforeDelete(ctx, tx)
+		}
+	case hookAfterDelete:
+		if h, ok := body.(IAfterDelete); ok {
+			return h.AfterDelete(ctx, tx)
+		}
+	}
+	return nil
+}
+
+// runHooks for []B []*B, elements of []B are passed by pointer so hooks can modify them
+func runHooks[B any](point hookPoint, bodies any, ctx context.Context, tx *Tx) error {
+	switch bs := bodies.(type) {
+	case []B:
+		for i := range bs {
+			if err := runHook(point, &bs[i], ctx, tx); err != nil {
+				return err
+			}
+		}
+	case []*B:
+		for _, b := range bs {
+			if err := runHook(point, b, ctx, tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func txOfRunner(runner any) *Tx {
+	if tx, ok := runner.(*Tx); ok {
+		return tx
+	}
+	return nil
+}
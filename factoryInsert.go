@@ -2,10 +2,14 @@ package preform
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"github.com/go-preform/preform/dialect"
 	preformShare "github.com/go-preform/preform/share"
 	"github.com/go-preform/squirrel"
+	"reflect"
+	"strings"
 )
 
 var (
@@ -17,6 +21,15 @@ type EditConfig struct {
 	Cascading        bool
 	Ctx              context.Context
 	NoAutoPrimaryKey bool
+	OnConflict       *UpsertConfig //update on conflict, not applied to cascading bodies
+	ForceDelete      bool          //remove rows of soft deleting factories
+}
+
+type UpsertConfig struct {
+	Tx           *Tx
+	Ctx          context.Context
+	ConflictCols []ICol //empty as primary keys
+	UpdateCols   []ICol //empty as all columns except conflict columns and primary keys
 }
 
 // Insert body bodies map maps with cascading and insert id
@@ -80,18 +93,34 @@ func (f Factory[FPtr, B]) InsertBatch(bodies any, cfgs ...EditConfig) error {
 	if len(cfgs) != 0 {
 		cfg = cfgs[0]
 	}
-	if cfg.Tx != nil {
-		exec = cfg.Tx
-	}
 	if cfg.Ctx != nil {
 		ctx = cfg.Ctx
 	}
+	if cfg.Tx == nil {
+		cfg.Tx = txOfDb(ctx, db)
+	}
+	if cfg.Tx != nil {
+		exec = cfg.Tx
+	}
 	if cfg.Cascading {
 		return errors.New("cascading is not supported for batch insert")
 	}
 	if cfg.NoAutoPrimaryKey {
 		autoPk = nil
 	}
+	if err := runHooks[B](hookBeforeInsert, bodies, ctx, cfg.Tx); err != nil {
+		return err
+	}
+	if cfg.OnConflict != nil {
+		conflictSuffix, _, err := f.onConflictSuffix(*cfg.OnConflict, autoPk)
+		if err != nil {
+			return err
+		}
+		if conflictSuffix != nil {
+			suffix, args, _ := conflictSuffix.ToSql()
+			query = query.Suffix(suffix, args...)
+		}
+	}
 	for _, col := range f.columns {
 		if autoPk == col {
 			continue
@@ -183,7 +212,7 @@ func (f Factory[FPtr, B]) InsertBatch(bodies any, cfgs ...EditConfig) error {
 	if returnErr != nil {
 		return returnErr
 	}
-	return returnErr
+	return runHooks[B](hookAfterInsert, bodies, ctx, cfg.Tx)
 }
 
 func (f Factory[FPtr, B]) InsertOne(body *B, cfgs ...EditConfig) error {
@@ -198,31 +227,61 @@ func (f Factory[FPtr, B]) InsertOne(body *B, cfgs ...EditConfig) error {
 		cfg          EditConfig
 		ctx          = f.Db().ctx
 		autoPk       = f.autoPk
+		autoPkOmit   bool
 		lastIdMethod preformShare.SqlDialectLastInsertIdMethod
 		lastIdSuffix func(col string) squirrel.Sqlizer
+		lastIdOutput string
 	)
 	lastIdMethod, lastIdSuffix = db.dialect.LastInsertIdMethod()
 	if len(cfgs) != 0 {
 		cfg = cfgs[0]
 	}
-	if cfg.Tx != nil {
-		exec = cfg.Tx
-	}
 	if cfg.Ctx != nil {
 		ctx = cfg.Ctx
 	}
+	if cfg.Tx == nil {
+		cfg.Tx = txOfDb(ctx, db)
+	}
+	if cfg.Tx != nil {
+		exec = cfg.Tx
+	}
 	if cfg.NoAutoPrimaryKey {
 		autoPk = nil
 	}
+	if err := runHook(hookBeforeInsert, body, ctx, cfg.Tx); err != nil {
+		return err
+	}
+	if cfg.OnConflict != nil {
+		var (
+			conflictSuffix squirrel.Sqlizer
+			err            error
+		)
+		conflictSuffix, lastIdMethod, err = f.onConflictSuffix(*cfg.OnConflict, autoPk)
+		if err != nil {
+			return err
+		}
+		if conflictSuffix != nil {
+			suffix, args, _ := conflictSuffix.ToSql()
+			query = query.Suffix(suffix, args...)
+		}
+		if lastIdMethod != dialect.LastInsertIdMethodBySuffix && lastIdMethod != dialect.LastInsertIdMethodByOutput && lastIdMethod != dialect.LastInsertIdMethodByOutParam {
+			lastIdSuffix = nil
+		}
+	}
 	var i int
 	for _, col := range f.columns {
 		bodyValues[i] = col.unwrapPtrForInsert(bodyValues[i])
 		if bodyValues[i] == preformShare.DEFAULT_VALUE {
 			if autoPk == col {
+				autoPkOmit = true
 				bodyValues = append(bodyValues[:autoPk.GetPos()], bodyValues[autoPk.GetPos()+1:]...)
 				if lastIdSuffix != nil {
 					suffix, args, _ := lastIdSuffix(autoPk.DbName()).ToSql()
-					query = query.Suffix(suffix, args...)
+					if lastIdMethod == dialect.LastInsertIdMethodByOutput {
+						lastIdOutput = suffix
+					} else {
+						query = query.Suffix(suffix, args...)
+					}
 				}
 				continue
 			} else {
@@ -238,11 +297,31 @@ func (f Factory[FPtr, B]) InsertOne(body *B, cfgs ...EditConfig) error {
 	if err != nil {
 		return err
 	}
+	if lastIdOutput != "" {
+		q = strings.Replace(q, ") VALUES (", ") "+lastIdOutput+" VALUES (", 1)
+	}
 	if autoPk == nil {
 		_, err = exec.RelatedFactory([]preformShare.IQueryFactory{f}).ExecContext(ctx, q, args...)
 		if err != nil {
 			return err
 		}
+	} else if cfg.OnConflict != nil && (!autoPkOmit || lastIdMethod == dialect.LastInsertIdMethodNone) {
+		var res sql.Result
+		res, err = exec.RelatedFactory([]preformShare.IQueryFactory{f}).ExecContext(ctx, q, args...)
+		if err != nil {
+			return err
+		}
+		if autoPkOmit {
+			if err = f.loadAutoIdByConflict(ctx, exec, res, body, *cfg.OnConflict, autoPk); err != nil {
+				return err
+			}
+		}
+	} else if lastIdMethod == dialect.LastInsertIdMethodBySuffix && !isNumericId(autoPk.NewValue()) {
+		//uuid keys by gen_random_uuid() are returned as they are
+		err = exec.RelatedFactory([]preformShare.IQueryFactory{f}).QueryRowContext(ctx, q, args...).Scan(any(body).(iModelBody).FieldValuePtr(autoPk.GetPos()))
+		if err != nil {
+			return err
+		}
 	} else {
 		var (
 			lastId int64
@@ -260,6 +339,10 @@ func (f Factory[FPtr, B]) InsertOne(body *B, cfgs ...EditConfig) error {
 				relatedBodies           []any
 				relatedBody             any
 			)
+			if cfg.OnConflict != nil {
+				cfg.OnConflict = nil
+				cfgs = []EditConfig{cfg}
+			}
 			for _, rel := range f.relations {
 				relatedBodies = rel.unwrapPtrBodyToTargetBodies(bodyAsiModelRelatedBody.RelatedValuePtrs()[rel.Index()])
 				if relatedBodies != nil {
@@ -284,8 +367,89 @@ func (f Factory[FPtr, B]) InsertOne(body *B, cfgs ...EditConfig) error {
 			}
 		}
 	}
-	return nil
+	return runHook(hookAfterInsert, body, ctx, cfg.Tx)
+
+}
+
+// Upsert body bodies with insert id, update UpdateCols when ConflictCols conflict
+func (f Factory[FPtr, B]) Upsert(body any, cfg ...UpsertConfig) error {
+	var (
+		upsertCfg UpsertConfig
+	)
+	if len(cfg) != 0 {
+		upsertCfg = cfg[0]
+	}
+	return f.Insert(body, EditConfig{Tx: upsertCfg.Tx, Ctx: upsertCfg.Ctx, OnConflict: &upsertCfg})
+}
+
+func (f Factory[FPtr, B]) onConflictSuffix(cfg UpsertConfig, autoPk ICol) (squirrel.Sqlizer, preformShare.SqlDialectLastInsertIdMethod, error) {
+	var (
+		conflictCols  = cfg.ConflictCols
+		updateCols    = cfg.UpdateCols
+		conflictNames = make([]string, 0, len(conflictCols))
+		updateNames   = make([]string, 0, len(updateCols))
+		conflictSet   = map[string]struct{}{}
+		autoPkName    string
+	)
+	if len(conflictCols) == 0 {
+		conflictCols = f.primaryKeys
+	}
+	for _, col := range conflictCols {
+		conflictNames = append(conflictNames, col.DbName())
+		conflictSet[col.DbName()] = struct{}{}
+	}
+	if len(updateCols) == 0 {
+		for _, col := range f.primaryKeys {
+			conflictSet[col.DbName()] = struct{}{}
+		}
+		for _, col := range f.columns {
+			if _, ok := conflictSet[col.DbName()]; !ok {
+				updateNames = append(updateNames, col.DbName())
+			}
+		}
+	} else {
+		for _, col := range updateCols {
+			updateNames = append(updateNames, col.DbName())
+		}
+	}
+	if autoPk != nil {
+		autoPkName = autoPk.DbName()
+	}
+	return f.Db().dialect.Upsert(conflictNames, updateNames, autoPkName)
+}
 
+// loadAutoIdByConflict for drivers can't return the id of the updated row
+func (f Factory[FPtr, B]) loadAutoIdByConflict(ctx context.Context, exec DB, res sql.Result, body *B, cfg UpsertConfig, autoPk ICol) error {
+	var (
+		db           = f.Db()
+		conflictCols = cfg.ConflictCols
+		cond         = squirrel.Eq{}
+		bodyValues   = any(body).(iModelBody)
+		lastId       int64
+		err          error
+	)
+	if len(conflictCols) == 0 {
+		conflictCols = f.primaryKeys
+	}
+	for _, col := range conflictCols {
+		if col.DbName() == autoPk.DbName() { //omitted auto key never conflicts
+			if lastId, err = res.LastInsertId(); err != nil {
+				return err
+			}
+			setInsertId(body, autoPk, lastId)
+			return nil
+		}
+		cond[db.dialect.QuoteIdentifier(col.DbName())] = f.columns[col.GetPos()].unwrapPtrForInsert(bodyValues.FieldValuePtr(col.GetPos()))
+	}
+	q, args, err := db.sqStmtBuilder.SelectFast(db.dialect.QuoteIdentifier(autoPk.DbName())).From(f.tableNameWithParent()).Where(cond).Limit(1).ToSql()
+	if err != nil {
+		return err
+	}
+	if err = exec.RelatedFactory([]preformShare.IQueryFactory{f}).QueryRowContext(ctx, q, args...).Scan(&lastId); err != nil {
+		return err
+	}
+	setInsertId(body, autoPk, lastId)
+	return nil
 }
 
 func (f Factory[FPtr, B]) InsertMap(body map[string]any, cfgs ...EditConfig) error {
@@ -308,28 +472,23 @@ func (f Factory[FPtr, B]) InsertMap(body map[string]any, cfgs ...EditConfig) err
 	return err
 }
 
+// isNumericId by kind, named types and every int size included
+func isNumericId(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
 func setInsertId[B any](body *B, autoPk ICol, lastId int64) {
 	var (
 		va = autoPk.NewValue()
-		id any
 	)
-	switch va.(type) {
-	case int64:
-		id = lastId
-	case int:
-		id = int(lastId)
-	case int32:
-		id = int32(lastId)
-	case uint64:
-		id = uint64(lastId)
-	case uint32:
-		id = uint32(lastId)
-	case uint:
-		id = uint(lastId)
-	case float64:
-		id = float64(lastId)
-	case float32:
-		id = float32(lastId)
-	}
-	autoPk.SetValue(body, id)
+	if !isNumericId(va) {
+		return
+	}
+	autoPk.SetValue(body, reflect.ValueOf(lastId).Convert(reflect.TypeOf(va)).Interface())
 }
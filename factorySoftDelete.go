@@ -0,0 +1,100 @@
+package preform
+
+import (
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"strings"
+)
+
+const (
+	trashedExclude uint8 = iota
+	trashedWith
+	trashedOnly
+)
+
+// SetSoftDelete delete by setting the nullable col instead of removing rows, selects skip rows with col set
+// deletedValue default CURRENT_TIMESTAMP, nil col turn it off
+func (f *Factory[FPtr, B]) SetSoftDelete(col ICol, deletedValue ...func() any) *Factory[FPtr, B] {
+	f.factory.softDelete = col
+	f.factory.softDeleteValue = nil
+	if len(deletedValue) != 0 {
+		f.factory.softDeleteValue = deletedValue[0]
+	}
+	return f
+}
+
+// SoftDeleteColumn nil if not soft deleting
+func (f Factory[FPtr, B]) SoftDeleteColumn() ICol {
+	if f.factory.softDelete == nil {
+		return nil
+	}
+	return f.columns[f.factory.softDelete.GetPos()]
+}
+
+func (f Factory[FPtr, B]) softDeleteQuery() preformShare.UpdateBuilder {
+	var (
+		db      = f.Db()
+		colCode = db.dialect.QuoteIdentifier(f.factory.softDelete.DbName())
+		value   any
+	)
+	if f.factory.softDeleteValue != nil {
+		value = f.factory.softDeleteValue()
+	} else {
+		value = squirrel.Expr("CURRENT_TIMESTAMP")
+	}
+	return db.sqStmtBuilder.UpdateFast(strings.Split(f.fromClause(), " AS ")[0]).
+		Set(colCode, value).
+		Where(colCode + " IS NULL")
+}
+
+type iSoftDelete interface {
+	SoftDeleteColumn() ICol
+}
+
+// notTrashedCond skip soft deleted rows of joined or related factories, nil if f is not soft deleting
+func notTrashedCond(f IQuery) ICond {
+	if sd, ok := f.(iSoftDelete); ok {
+		if col := sd.SoftDeleteColumn(); col != nil {
+			return &trashedCond{col: col}
+		}
+	}
+	return nil
+}
+
+// trashedCond kept as pointer in where so WithTrashed OnlyTrashed can switch it later
+type trashedCond struct {
+	col  ICol
+	mode uint8
+}
+
+func (c *trashedCond) ToSql() (string, []any, error) {
+	switch c.mode {
+	case trashedWith:
+		return squirrel.And{}.ToSql()
+	case trashedOnly:
+		return c.col.GetCode() + " IS NOT NULL", nil, nil
+	}
+	return c.col.GetCode() + " IS NULL", nil, nil
+}
+
+// WithTrashed include soft deleted rows
+func (b *SelectQuery[B]) WithTrashed() *SelectQuery[B] {
+	if b.trashed != nil {
+		b.trashed.mode = trashedWith
+	}
+	return b
+}
+
+// OnlyTrashed select soft deleted rows only
+func (b *SelectQuery[B]) OnlyTrashed() *SelectQuery[B] {
+	if b.trashed != nil {
+		b.trashed.mode = trashedOnly
+	}
+	return b
+}
+
+// ForceDelete remove rows even if the factory is soft deleting
+func (b DeleteBuilder[B]) ForceDelete() DeleteBuilder[B] {
+	b.softDelete = false
+	return b
+}
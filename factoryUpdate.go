@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"errors"
+	"github.com/go-preform/preform/dialect"
 	preformShare "github.com/go-preform/preform/share"
 	"github.com/go-preform/squirrel"
 	"strings"
@@ -28,22 +29,31 @@ func (f *Factory[FPtr, B]) UpdateByPk(body any, cfgs ...UpdateConfig) (updated i
 		cols       = f.columns
 		mBody      = body.(iModelBody)
 		bodyValues = mBody.FieldValuePtrs()
+		version    = f.VersionColumn()
+		current    any
+		next       any
 	)
 	if len(cfgs) != 0 {
 		cfg = cfgs[0]
 	}
-	if cfg.Tx != nil {
-		exec = cfg.Tx
-	}
 	if cfg.Ctx != nil {
 		ctx = cfg.Ctx
 	}
+	if cfg.Tx == nil {
+		cfg.Tx = txOfDb(ctx, db)
+	}
+	if cfg.Tx != nil {
+		exec = cfg.Tx
+	}
 	if cfg.Cols != nil {
 		cols = cfg.Cols
 	}
+	if err = runHook(hookBeforeUpdate, body, ctx, cfg.Tx); err != nil {
+		return 0, err
+	}
 	for _, col := range cols {
 		_, _, isPk, _ = col.properties()
-		if !isPk {
+		if !isPk && (version == nil || col.GetPos() != version.GetPos()) {
 			query = query.Set(db.dialect.QuoteIdentifier(col.DbName()), col.unwrapPtrForUpdate(bodyValues[col.GetPos()]))
 		}
 	}
@@ -51,6 +61,18 @@ func (f *Factory[FPtr, B]) UpdateByPk(body any, cfgs ...UpdateConfig) (updated i
 		query = query.Where(f.fixCond)
 	}
 	query = query.Where(f.PkCondByValues(bodyValues))
+	if version != nil {
+		var (
+			nextPtr     any
+			versionCode = db.dialect.QuoteIdentifier(version.DbName())
+		)
+		current = version.getValueFromBody(mBody)
+		if next, nextPtr, err = nextVersion(current); err != nil {
+			return 0, err
+		}
+		query = query.Set(versionCode, version.unwrapPtrForUpdate(nextPtr)).
+			Where(squirrel.Eq{versionCode: version.unwrapPtrForUpdate(bodyValues[version.GetPos()])})
+	}
 	q, args, err := db.dialect.UpdateSqlizer(query)
 	if err != nil {
 		return 0, err
@@ -63,6 +85,22 @@ func (f *Factory[FPtr, B]) UpdateByPk(body any, cfgs ...UpdateConfig) (updated i
 	if err != nil {
 		return 0, err
 	}
+	if version != nil {
+		if updated == 0 {
+			var matched bool
+			if matched, err = f.versionMatched(ctx, exec, version, mBody, bodyValues); err != nil {
+				return 0, err
+			}
+			if !matched {
+				return 0, ErrStaleObject{Table: f.tableName, Version: current}
+			}
+			updated = 1
+		}
+		version.setValueToBody(mBody, next)
+		if err = f.reloadVersion(ctx, exec, version, mBody, bodyValues); err != nil {
+			return 0, err
+		}
+	}
 	if cfg.Cascading {
 		if len(f.relations) != 0 {
 			var (
@@ -84,6 +122,9 @@ func (f *Factory[FPtr, B]) UpdateByPk(body any, cfgs ...UpdateConfig) (updated i
 			}
 		}
 	}
+	if err = runHook(hookAfterUpdate, body, ctx, cfg.Tx); err != nil {
+		return 0, err
+	}
 	return
 }
 
@@ -95,6 +136,7 @@ type UpdateBuilder[B any] struct {
 	bodyCols []ICol
 	bodies   []*B
 	ctx      context.Context
+	err      error
 }
 
 func (f *Factory[FPtr, B]) Update() UpdateBuilder[B] {
@@ -105,8 +147,12 @@ func (f *Factory[FPtr, B]) Update() UpdateBuilder[B] {
 	return UpdateBuilder[B]{Builder: b, factory: f.Definition, execer: f.Db()}
 }
 
+// Ctx run with the transaction in ctx if any, see WithTx
 func (b UpdateBuilder[B]) Ctx(ctx context.Context) UpdateBuilder[B] {
 	b.ctx = ctx
+	if tx := txOfDb(ctx, b.factory.Db()); tx != nil {
+		b.execer = tx
+	}
 	return b
 }
 
@@ -118,20 +164,28 @@ func (b UpdateBuilder[B]) Where(cond ICond) UpdateBuilder[B] {
 
 func noTableCodeWhere(cond ICond) ICond {
 	switch cond.(type) {
+	case colConditioner:
+		return cond.(colConditioner).NoParentCode().mapNested(noTableCodeWhere)
 	case IColConditioner:
 		return cond.(IColConditioner).NoParentCode()
 	case squirrel.And:
 		condAnd := cond.(squirrel.And)
+		res := make(squirrel.And, len(condAnd))
 		for i := range condAnd {
-			condAnd[i] = noTableCodeWhere(condAnd[i])
+			res[i] = noTableCodeWhere(condAnd[i])
 		}
-		return condAnd
+		return res
 	case squirrel.Or:
 		condOr := cond.(squirrel.Or)
+		res := make(squirrel.Or, len(condOr))
 		for i := range condOr {
-			condOr[i] = noTableCodeWhere(condOr[i])
+			res[i] = noTableCodeWhere(condOr[i])
 		}
-		return condOr
+		return res
+	case relationExists:
+		condExists := cond.(relationExists)
+		condExists.noParentCode = true
+		return condExists
 	}
 	return cond
 }
@@ -145,6 +199,7 @@ func (b UpdateBuilder[B]) Columns(cols ...ICol) UpdateBuilder[B] {
 	return b
 }
 
+// SetBodies update the bodies by pk, the version column of SetVersion is set as is, not bumped or checked
 func (b UpdateBuilder[B]) SetBodies(body ...*B) UpdateBuilder[B] {
 	b.bodies = body
 	return b
@@ -166,11 +221,25 @@ func (b UpdateBuilder[B]) SetMap(clause map[string]any) UpdateBuilder[B] {
 }
 
 func (b UpdateBuilder[B]) LimitOffset(limit, offset uint64) UpdateBuilder[B] {
-	b.Builder = b.Builder.Limit(limit).Offset(offset)
+	if b.err = limitOffsetErr(b.factory.Db().dialect, limit, offset); b.err == nil {
+		b.Builder = b.Builder.Limit(limit).Offset(offset)
+	}
 	return b
 }
 
+// limitOffsetErr ErrorNotSupport if the dialect pages without LIMIT OFFSET, for clauses its paging can't be put in
+func limitOffsetErr(d preformShare.IDialect, limit, offset uint64) error {
+	paging, err := d.Paging(limit, offset, false)
+	if err == nil && paging != nil {
+		err = dialect.ErrorNotSupport
+	}
+	return err
+}
+
 func (b UpdateBuilder[B]) ToSql() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
 	var db = b.factory.Db()
 	if l := len(b.bodies); l != 0 {
 		var (
@@ -236,19 +305,97 @@ func (b UpdateBuilder[B]) ToSql() (string, []any, error) {
 }
 
 func (b UpdateBuilder[B]) Exec(tx ...preformShare.QueryRunner) (int64, error) {
+	if b.ctx == nil {
+		b.ctx = b.factory.Db().ctx
+	}
+	if len(tx) == 0 {
+		tx = []preformShare.QueryRunner{b.execer}
+	}
+	if err := runHooks[B](hookBeforeUpdate, b.bodies, b.ctx, txOfRunner(tx[0])); err != nil {
+		return 0, err
+	}
 	q, args, err := b.ToSql()
 	if err != nil {
 		return 0, err
 	}
+	res, err := tx[0].RelatedFactory([]preformShare.IQueryFactory{b.factory}).ExecContext(b.ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err = runHooks[B](hookAfterUpdate, b.bodies, b.ctx, txOfRunner(tx[0])); err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// ExecReturning update and scan the returning cols into bodies, all columns if cols is empty
+func (b UpdateBuilder[B]) ExecReturning(cols []ICol, tx ...preformShare.QueryRunner) ([]B, error) {
+	return b.execReturning(cols, false, tx)
+}
+
+// ExecReturningFast scan with ScanBodiesFast
+func (b UpdateBuilder[B]) ExecReturningFast(cols []ICol, tx ...preformShare.QueryRunner) ([]B, error) {
+	return b.execReturning(cols, true, tx)
+}
+
+func (b UpdateBuilder[B]) execReturning(cols []ICol, fast bool, tx []preformShare.QueryRunner) ([]B, error) {
+	if len(cols) == 0 {
+		cols = b.factory.Columns()
+	}
+	suffix, err := returningSuffix(b.factory, cols)
+	if err != nil {
+		return nil, err
+	}
 	if b.ctx == nil {
 		b.ctx = b.factory.Db().ctx
 	}
 	if len(tx) == 0 {
 		tx = []preformShare.QueryRunner{b.execer}
 	}
-	res, err := tx[0].RelatedFactory([]preformShare.IQueryFactory{b.factory}).ExecContext(b.ctx, q, args...)
+	if err = runHooks[B](hookBeforeUpdate, b.bodies, b.ctx, txOfRunner(tx[0])); err != nil {
+		return nil, err
+	}
+	b.Builder = b.Builder.Suffix(suffix)
+	q, args, err := b.ToSql()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	res, err := queryReturning[B](b.ctx, tx[0], b.factory, cols, fast, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err = runHooks[B](hookAfterUpdate, b.bodies, b.ctx, txOfRunner(tx[0])); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func returningSuffix(factory IFactory, cols []ICol) (string, error) {
+	var (
+		names = make([]string, len(cols))
+	)
+	for i, col := range cols {
+		names[i] = col.DbName()
+	}
+	returning, err := factory.Db().dialect.Returning(names)
+	if err != nil {
+		return "", err
+	}
+	suffix, _, err := returning.ToSql()
+	return suffix, err
+}
+
+func queryReturning[B any](ctx context.Context, execer preformShare.QueryRunner, factory IFactory, cols []ICol, fast bool, q string, args ...any) ([]B, error) {
+	rows, err := execer.RelatedFactory([]preformShare.IQueryFactory{factory}).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if fast {
+		return factory.IModelScanner().(IModelScanner[B]).ScanBodiesFast(rows, cols, 0)
 	}
-	return res.RowsAffected()
+	return factory.IModelScanner().(IModelScanner[B]).ScanBodies(rows, cols, 0)
 }
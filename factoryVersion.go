@@ -0,0 +1,127 @@
+package preform
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// ErrStaleObject returned by UpdateByPk when the version of body no longer matches the row
+type ErrStaleObject struct {
+	Table   string
+	Version any
+}
+
+func (e ErrStaleObject) Error() string {
+	return fmt.Sprintf("stale object of %s, version %v is outdated", e.Table, e.Version)
+}
+
+// SetVersion optimistic locking by col, integer columns are increased and time columns set to now by UpdateByPk, nil col turn it off
+// panics on other types, nullable and pointer columns included, UpdateBuilder.SetBodies does not bump or check the version
+// time versions are as precise as the column stores them, e.g. DATETIME of mysql keeps seconds, so updates within the same second are not told apart
+func (f *Factory[FPtr, B]) SetVersion(col ICol) *Factory[FPtr, B] {
+	if col != nil {
+		if err := checkVersionType(reflect.TypeOf(col.NewValue())); err != nil {
+			panic(err)
+		}
+	}
+	f.factory.version = col
+	return f
+}
+
+func checkVersionType(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	}
+	if t.Kind() == reflect.Struct && t.ConvertibleTo(timeType) {
+		return nil
+	}
+	return fmt.Errorf("version column of type %s is not supported", t)
+}
+
+// VersionColumn nil if no optimistic locking
+func (f Factory[FPtr, B]) VersionColumn() ICol {
+	if f.factory.version == nil {
+		return nil
+	}
+	return f.columns[f.factory.version.GetPos()]
+}
+
+// nextVersion same typed value as current, pointer for unwrapPtrForUpdate
+func nextVersion(current any) (next any, nextPtr any, err error) {
+	var (
+		v = reflect.ValueOf(current)
+		n = reflect.New(v.Type())
+	)
+	if err = checkVersionType(v.Type()); err != nil {
+		return nil, nil, err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.Elem().SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n.Elem().SetUint(v.Uint() + 1)
+	default:
+		n.Elem().Set(reflect.ValueOf(time.Now().Truncate(time.Microsecond)).Convert(v.Type())) //stored precision differs by db, reloaded by reloadVersion
+	}
+	return n.Elem().Interface(), n.Interface(), nil
+}
+
+// versionMatched mysql counts changed rows only, so a time version set within the stored precision updates 0 rows of a matched row
+func (f *Factory[FPtr, B]) versionMatched(ctx context.Context, exec DB, version ICol, mBody iModelBody, bodyValues []any) (bool, error) {
+	if !reflect.TypeOf(version.getValueFromBody(mBody)).ConvertibleTo(timeType) {
+		return false, nil
+	}
+	var (
+		db    = f.Db()
+		found int
+	)
+	q, args, err := db.sqStmtBuilder.SelectFast("1").From(strings.Split(f.fromClause(), " AS ")[0]).Where(f.PkCondByValues(bodyValues)).
+		Where(squirrel.Eq{db.dialect.QuoteIdentifier(version.DbName()): version.unwrapPtrForUpdate(bodyValues[version.GetPos()])}).ToSql()
+	if err != nil {
+		return false, err
+	}
+	err = exec.RelatedFactory([]preformShare.IQueryFactory{f}).QueryRowContext(ctx, q, args...).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// reloadVersion read back a time version as stored, e.g. DATETIME keeps seconds only and sqlite keeps text
+func (f *Factory[FPtr, B]) reloadVersion(ctx context.Context, exec DB, version ICol, mBody iModelBody, bodyValues []any) error {
+	if !reflect.TypeOf(version.getValueFromBody(mBody)).ConvertibleTo(timeType) {
+		return nil
+	}
+	var (
+		db                     = f.Db()
+		raw, toScanner, direct = version.rawPtrScanner()
+		scanner                = toScanner(&raw)
+	)
+	if direct {
+		scanner = bodyValues[version.GetPos()]
+	}
+	q, args, err := db.sqStmtBuilder.SelectFast(db.dialect.QuoteIdentifier(version.DbName())).From(strings.Split(f.fromClause(), " AS ")[0]).Where(f.PkCondByValues(bodyValues)).ToSql()
+	if err != nil {
+		return err
+	}
+	if err = exec.RelatedFactory([]preformShare.IQueryFactory{f}).QueryRowContext(ctx, q, args...).Scan(scanner); err != nil {
+		return err
+	}
+	if !direct {
+		version.setValueToBody(mBody, raw)
+	}
+	return nil
+}
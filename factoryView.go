@@ -71,6 +71,10 @@ func (f *ViewFactory[FPtr, B]) Insert(body any, cfg ...EditConfig) error {
 	return errorViewNotWritable
 }
 
+func (f *ViewFactory[FPtr, B]) Upsert(body any, cfg ...UpsertConfig) error {
+	return errorViewNotWritable
+}
+
 func (f *ViewFactory[FPtr, B]) UpdateByPk(body any, cfg ...UpdateConfig) (int64, error) {
 	return 0, errorViewNotWritable
 }
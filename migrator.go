@@ -0,0 +1,216 @@
+package preform
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-preform/squirrel"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// MigrationTable default table of NewMigrator, skipped by preformBuilder.DiffMigration unless MigrationSchema.MigrationTable is set
+const MigrationTable = "preform_migration"
+
+// Migrator apply <version>_<name>.up.sql and revert by .down.sql in fsys, as written by preformBuilder.BuildMigration
+// every migration runs in a transaction with its bookkeeping row, mysql commits ddl implicitly so a failed migration may be applied partly
+type Migrator struct {
+	db    *db
+	fsys  fs.FS
+	table string
+}
+
+type migration struct {
+	version, name, up, down string
+}
+
+// NewMigrator migrations in the root of fsys, applied versions are kept in table, default preform_migration, schema.table to keep it in a schema
+func (d *db) NewMigrator(fsys fs.FS, table ...string) *Migrator {
+	m := &Migrator{db: d, fsys: fsys, table: MigrationTable}
+	if len(table) != 0 {
+		m.table = table[0]
+	}
+	return m
+}
+
+func (m *Migrator) init(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version VARCHAR(32) NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)", m.tableCode()))
+	return err
+}
+
+func (m *Migrator) tableCode() string {
+	var (
+		parts = strings.Split(m.table, ".")
+	)
+	for i, part := range parts {
+		parts[i] = m.db.dialect.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (m *Migrator) migrations() ([]*migration, error) {
+	var (
+		byVersion = map[string]*migration{}
+		list      []*migration
+	)
+	entries, err := fs.ReadDir(m.fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		var (
+			name   = entry.Name()
+			isUp   = strings.HasSuffix(name, ".up.sql")
+			isDown = strings.HasSuffix(name, ".down.sql")
+		)
+		if entry.IsDir() || !isUp && !isDown {
+			continue
+		}
+		version, rest, _ := strings.Cut(strings.TrimSuffix(strings.TrimSuffix(name, ".up.sql"), ".down.sql"), "_")
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &migration{version: version, name: rest}
+			byVersion[version] = mg
+			list = append(list, mg)
+		}
+		if isUp {
+			mg.up = name
+		} else {
+			mg.down = name
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].version < list[j].version
+	})
+	return list, nil
+}
+
+// Applied versions in ascending order
+func (m *Migrator) Applied(ctx context.Context) ([]string, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, fmt.Sprintf("SELECT version FROM %s ORDER BY version", m.tableCode()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var (
+		versions []string
+		version  string
+	)
+	for rows.Next() {
+		if err = rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// Up apply all pending migrations in version order, return the applied versions
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		done    = map[string]bool{}
+		applies []string
+	)
+	for _, version := range applied {
+		done[version] = true
+	}
+	for _, mg := range list {
+		if done[mg.version] {
+			continue
+		}
+		if mg.up == "" {
+			return applies, fmt.Errorf("migration %s has no up file", mg.version)
+		}
+		err = m.run(ctx, mg.up, func(tx *Tx) error {
+			q, args, err := m.db.sqStmtBuilder.InsertFast(m.tableCode()).Columns("version", "name").Values(mg.version, mg.name).ToSql()
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, q, args...)
+			return err
+		})
+		if err != nil {
+			return applies, fmt.Errorf("migration %s: %w", mg.up, err)
+		}
+		applies = append(applies, mg.version)
+	}
+	return applies, nil
+}
+
+// Down revert the last steps applied migrations, return the reverted versions
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		byVersion = map[string]*migration{}
+		reverts   []string
+	)
+	for _, mg := range list {
+		byVersion[mg.version] = mg
+	}
+	for i := len(applied) - 1; i >= 0 && len(reverts) < steps; i-- {
+		mg, ok := byVersion[applied[i]]
+		if !ok || mg.down == "" {
+			return reverts, fmt.Errorf("migration %s has no down file", applied[i])
+		}
+		err = m.run(ctx, mg.down, func(tx *Tx) error {
+			q, args, err := m.db.sqStmtBuilder.DeleteFast(m.tableCode()).Where(squirrel.Eq{"version": mg.version}).ToSql()
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, q, args...)
+			return err
+		})
+		if err != nil {
+			return reverts, fmt.Errorf("migration %s: %w", mg.down, err)
+		}
+		reverts = append(reverts, mg.version)
+	}
+	return reverts, nil
+}
+
+// run statements of file split by ";\n" and then book in the same transaction, never retried as ddl may not be undone
+func (m *Migrator) run(ctx context.Context, file string, book func(tx *Tx) error) error {
+	body, err := fs.ReadFile(m.fsys, file)
+	if err != nil {
+		return err
+	}
+	return m.db.withTx(ctx, func(ctx context.Context) error {
+		tx := TxFromCtx(ctx)
+		for _, stmt := range strings.Split(string(body), ";\n") {
+			if stmt = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt), ";")); stmt == "" || isSqlComment(stmt) {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return book(tx)
+	})
+}
+
+func isSqlComment(stmt string) bool {
+	for _, line := range strings.Split(stmt, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
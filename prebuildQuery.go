@@ -29,6 +29,7 @@ type PrebuildQueryFactory[FPtr iPrebuildQueryFactory, B any] struct {
 	schemas                 []ISchema
 	db                      *db
 	modelScanner            IModelScanner[B]
+	ctes                    *cteClause
 }
 
 func (f PrebuildQueryFactory[FPtr, B]) TableName() string {
@@ -213,6 +214,7 @@ func (f *PrebuildQueryFactory[FPtr, B]) Prepare(schemas ...ISchema) {
 	}
 
 	f.Query = squirrel.SelectFast()
+	f.ctes = nil
 	f.setter(f.def)
 	for _, col := range f.allCols {
 		f.columnsByName[col.DbName()] = col
@@ -220,12 +222,84 @@ func (f *PrebuildQueryFactory[FPtr, B]) Prepare(schemas ...ISchema) {
 }
 
 func (f *PrebuildQueryFactory[FPtr, B]) SetSrc(factory IFactory) *PrebuildQueryFactory[FPtr, B] {
-	f.Query = f.Query.From(factory.fromClause())
+	f.Query = f.Query.From(f.srcClause(factory))
+	return f
+}
+
+// srcClause select from the cte if one is defined with the alias of factory
+func (f *PrebuildQueryFactory[FPtr, B]) srcClause(factory IFactory) string {
+	if f.ctes.has(factory.Alias()) {
+		return f.Db().dialect.QuoteIdentifier(factory.Alias())
+	}
+	return factory.fromClause()
+}
+
+// With define a cte named by the alias of src with its rows matching cond, SetSrc / Join of src then select from the cte
+func (f *PrebuildQueryFactory[FPtr, B]) With(src IFactory, cond ...ICond) *PrebuildQueryFactory[FPtr, B] {
+	q := f.cteSelect(src)
+	for _, c := range cond {
+		q = q.Where(c)
+	}
+	return f.with(src, q, nil, false)
+}
+
+// WithRecursive define a recursive cte named by the alias of src, starting from rows matching anchor then walking down parentKey (a self fk of src)
+func (f *PrebuildQueryFactory[FPtr, B]) WithRecursive(src IFactory, anchor ICond, parentKey ICol) *PrebuildQueryFactory[FPtr, B] {
+	return f.withRecursive(src, anchor, parentKey, false)
+}
+
+// WithRecursiveDistinct as WithRecursive but duplicated rows are dropped, for parentKey forming cycles
+func (f *PrebuildQueryFactory[FPtr, B]) WithRecursiveDistinct(src IFactory, anchor ICond, parentKey ICol) *PrebuildQueryFactory[FPtr, B] {
+	return f.withRecursive(src, anchor, parentKey, true)
+}
+
+func (f *PrebuildQueryFactory[FPtr, B]) withRecursive(src IFactory, anchor ICond, parentKey ICol, distinct bool) *PrebuildQueryFactory[FPtr, B] {
+	var (
+		dialect = f.Db().dialect
+		child   = src.SetAlias(src.Alias() + "Child").(IFactory)
+		target  string
+	)
+	if fk, ok := parentKey.(IForeignKey); ok && len(fk.AssociatedKeys()) != 0 {
+		target = fk.AssociatedKeys()[0].DbName()
+	} else {
+		target = src.Pks()[0].DbName()
+	}
+	return f.with(src, f.cteSelect(src).Where(anchor), f.cteSelect(child).Join(fmt.Sprintf("%s ON %s.%s = %s.%s",
+		dialect.QuoteIdentifier(src.Alias()),
+		dialect.QuoteIdentifier(child.Alias()), dialect.QuoteIdentifier(parentKey.DbName()),
+		dialect.QuoteIdentifier(src.Alias()), dialect.QuoteIdentifier(target),
+	)), distinct)
+}
+
+func (f *PrebuildQueryFactory[FPtr, B]) cteSelect(src IFactory) preformShare.SelectBuilder {
+	var (
+		cols = src.Columns()
+		strs = make([]string, len(cols))
+	)
+	for i, col := range cols {
+		strs[i] = col.GetCode()
+	}
+	return src.selectQuery().Columns(strs...).PlaceholderFormat(squirrel.Question)
+}
+
+func (f *PrebuildQueryFactory[FPtr, B]) with(src IFactory, anchor, recursive squirrel.Sqlizer, distinct bool) *PrebuildQueryFactory[FPtr, B] {
+	var (
+		cols = src.Columns()
+		strs = make([]string, len(cols))
+	)
+	for i, col := range cols {
+		strs[i] = col.DbName()
+	}
+	if f.ctes == nil {
+		f.ctes = &cteClause{}
+		f.Query = f.Query.PrefixExpr(f.ctes)
+	}
+	f.ctes.add(src.Alias(), f.Db().dialect, strs, anchor, recursive, distinct)
 	return f
 }
 
 func (f *PrebuildQueryFactory[FPtr, B]) Join(join string, factory IFactory, cond ...ICond) *PrebuildQueryFactory[FPtr, B] {
-	joinSql := factory.fromClause()
+	joinSql := f.srcClause(factory)
 	var (
 		condSql  string
 		tmpArgs  []any
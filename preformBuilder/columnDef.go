@@ -1,6 +1,7 @@
 package preformBuilder
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/go-preform/preform/dialect"
 	preformShare "github.com/go-preform/preform/share"
@@ -46,6 +47,21 @@ func (c columnDef[T]) RelatedFks() []preformShare.IColDef {
 	return c.relatedFks
 }
 
+// dbDef for migrations, by the tags of BuildModel
+func (c columnDef[T]) dbDef() (dataType string, defaultValue sql.NullString, isAuto bool) {
+	if c.fieldRef != nil {
+		dataType = c.fieldRef.Tag.Get("dataType")
+		defaultValue.String, defaultValue.Valid = c.fieldRef.Tag.Lookup("defaultValue")
+		isAuto = c.fieldRef.Tag.Get("autoKey") == "true"
+	}
+	for _, setting := range c.settings {
+		if setting[0] == "AutoIncrement" {
+			isAuto = true
+		}
+	}
+	return
+}
+
 func (c *columnDef[T]) Factory() preformShare.IFactoryBuilder {
 	return c.factoryBuilder
 }
@@ -327,6 +343,7 @@ type aggregatedCol[T any] struct {
 	*columnDef[T]
 	aggregateSetting []string
 	aggregatedType   reflect.Type
+	over             []WindowClause
 }
 
 func (c aggregatedCol[T]) ColDef() preformShare.IColDef {
@@ -341,12 +358,14 @@ func (c aggregatedCol[T]) GenerateCode(schemaName string, fromQuery bool) (impor
 		name            = c.CodeName()
 		defCode         []string
 		bodyCode        []string
+		typeName        string
 	)
 	if c.aggregatedType != nil {
 		tType = c.aggregatedType
 	}
-	defCode = []string{fmt.Sprintf("%s *%s.PrebuildQueryCol[%s, %s.NoAggregation]", name, pkgName, tType.String(), pkgName)}
-	bodyCode = []string{fmt.Sprintf("%s %s `db:\"%s\"%s`", name, tType.String(), c.alias, c.tags)}
+	typeName, importPath = parseColType(tType, name, schemaName)
+	defCode = []string{fmt.Sprintf("%s *%s.PrebuildQueryCol[%s, %s.NoAggregation]", name, pkgName, typeName, pkgName)}
+	bodyCode = []string{fmt.Sprintf("%s %s `db:\"%s\"`", name, typeName, c.alias)}
 	for _, setting := range c.settings {
 		if fromQuery && setting[0] == "setAlias" {
 			tmpSettingCodes = append(tmpSettingCodes, fmt.Sprintf("SetAlias(%s)", strings.Join(setting[1:], ",")))
@@ -355,10 +374,13 @@ func (c aggregatedCol[T]) GenerateCode(schemaName string, fromQuery bool) (impor
 		}
 	}
 	settingCodes = []string{fmt.Sprintf("%s.SetColumn(s.%s.%s).%s(%s)", pkgName, c.Factory().CodeName(), name, c.aggregateSetting[0], strings.Join(c.aggregateSetting[1:], ","))}
+	if c.over != nil {
+		settingCodes[0] += fmt.Sprintf(".Over(%s)", windowClausesToCode(c.over))
+	}
 	if len(tmpSettingCodes) != 0 {
 		settingCodes[0] += fmt.Sprintf(".%s", strings.Join(tmpSettingCodes, "."))
 	}
-	return []string{tType.PkgPath()},
+	return importPath,
 		defCode,
 		bodyCode,
 		settingCodes, ""
@@ -370,7 +392,7 @@ func (a columnDef[T]) Aggregate(fn preformShare.Aggregator, aggregatedType refle
 		aa         = a.setAlias(strcase.ToCamel(a.CodeName() + " " + string(fn)))
 	)
 	if len(params) == 0 {
-		return &aggregatedCol[T]{aa, []string{"Aggregate", fmt.Sprintf(`"%s"`, fn)}, aggregatedType}
+		return &aggregatedCol[T]{aa, []string{"Aggregate", fmt.Sprintf(`"%s"`, fn)}, aggregatedType, nil}
 	}
 	for i := range params {
 		switch params[i].(type) {
@@ -380,7 +402,7 @@ func (a columnDef[T]) Aggregate(fn preformShare.Aggregator, aggregatedType refle
 			paramsCode[i] = fmt.Sprintf(`%v`, params[i])
 		}
 	}
-	return &aggregatedCol[T]{aa, []string{"Aggregate", fmt.Sprintf(`"%s", %s`, fn, strings.Join(paramsCode, `,`))}, aggregatedType}
+	return &aggregatedCol[T]{aa, []string{"Aggregate", fmt.Sprintf(`"%s", %s`, fn, strings.Join(paramsCode, `,`))}, aggregatedType, nil}
 
 }
 
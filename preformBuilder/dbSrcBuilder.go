@@ -1,6 +1,7 @@
 package preformBuilder
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 
 func BuildModel(conn *sql.DB, modelPkgName, outputFiles string, schemasEmptyIsAll ...string) {
 	db := preform.DbFromNative(conn)
+	_ = db.DetectDialect(context.Background()) //logged, GetStructure fails the same way
 
 	schemas := db.Dialect().GetStructure(db.DB.DB, schemasEmptyIsAll...)
 
@@ -237,7 +239,8 @@ func BuildModel(conn *sql.DB, modelPkgName, outputFiles string, schemasEmptyIsAl
 					strings.Replace(
 						strings.Replace(
 							strings.Replace(
-								strings.Replace(string(customTypeJson), `"Imports":`, "Imports: map[string]struct{}", -1), `"IsScanner":`, "IsScanner:", -1), `"NotNull":`, "NotNull:", -1), `"Type":`, "Type:", -1), `"Name":`, "Name:", -1), `}],`, "}},", -1), `"Attr":[`, "Attr: []*preformShare.CustomTypeAttr{", -1)
+								strings.Replace(
+									strings.Replace(string(customTypeJson), `"Imports":`, "Imports: map[string]struct{}", -1), `"IsScanner":`, "IsScanner:", -1), `"NotNull":`, "NotNull:", -1), `"Type":`, "Type:", -1), `"DbType":`, "DbType:", -1), `"Name":`, "Name:", -1), `}],`, "}},", -1), `"Attr":[`, "Attr: []*preformShare.CustomTypeAttr{", -1)
 		if customTypeObj == "null" {
 			customTypeObj = "{}"
 		}
@@ -192,6 +192,10 @@ func (f *FactoryBuilder[D]) addToColSet(col preformShare.IColDef) {
 	f.colSet[col.CodeName()] = col
 }
 
+func (f FactoryBuilder[D]) view() bool {
+	return f.isView
+}
+
 func (f *FactoryBuilder[D]) IsView() {
 	f.isView = true
 	if !strings.Contains(f.codeName, "View") {
@@ -306,6 +310,18 @@ func (f *FactoryBuilder[D]) SetTableName(name string) *FactoryBuilder[D] {
 	return f
 }
 
+// SetSoftDelete delete by setting the nullable col to CURRENT_TIMESTAMP, selects skip deleted rows unless WithTrashed
+func (f *FactoryBuilder[D]) SetSoftDelete(col preformShare.IColDef) *FactoryBuilder[D] {
+	f.settingCodes = append(f.settingCodes, fmt.Sprintf(`s.%s.SetSoftDelete(s.%s.%s)`, f.codeName, f.codeName, col.CodeName()))
+	return f
+}
+
+// SetVersion optimistic locking by the integer or timestamp col, UpdateByPk returns preform.ErrStaleObject if the row was changed
+func (f *FactoryBuilder[D]) SetVersion(col preformShare.IColDef) *FactoryBuilder[D] {
+	f.settingCodes = append(f.settingCodes, fmt.Sprintf(`s.%s.SetVersion(s.%s.%s)`, f.codeName, f.codeName, col.CodeName()))
+	return f
+}
+
 func (f *FactoryBuilder[D]) FullCodeName() string {
 	if f.needSchemaPrefix {
 		return fmt.Sprintf("%s_%s", f.schema, f.codeName)
@@ -190,6 +190,13 @@ func (c *ForeignKeyDef[T]) SetAssociatedKey(col preformShare.IColDef, setters ..
 	return c
 }
 
+func (c ForeignKeyDef[T]) foreignKeyCnfs() (cnfs []*foreignKeyCnf) {
+	for _, cc := range c.associated {
+		cnfs = append(cnfs, cc...)
+	}
+	return
+}
+
 func (c *ForeignKeyDef[T]) PK() *ForeignKeyDef[T] {
 	c.settings = append(c.settings, []string{"PK"})
 	c.factoryBuilder.setPk(c)
@@ -0,0 +1,436 @@
+package preformBuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/dialect"
+	preformShare "github.com/go-preform/preform/share"
+	preformTypes "github.com/go-preform/preform/types"
+	"github.com/iancoleman/strcase"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ddlKindNames = [...]string{"create table", "drop table", "add column", "drop column", "alter column", "add foreign key", "drop foreign key", "create enum", "drop enum", "create custom type", "drop custom type"}
+	nullPkgPath  = reflect.TypeOf(preformTypes.Null[any]{}).PkgPath()
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// MigrationSchema factories enums and custom types of a schema as passed to BuildSchema, Name is the schema name in db
+// MigrationTable the table of preform.Migrator in the schema, never dropped, default preform.MigrationTable
+type MigrationSchema struct {
+	Name           string
+	Factories      []preformShare.IFactoryBuilder
+	Enums          map[string][]string
+	CustomTypes    map[string]*preformShare.CustomType
+	MigrationTable string
+}
+
+type iMigrationCol interface {
+	dbDef() (dataType string, defaultValue sql.NullString, isAuto bool)
+}
+
+type iMigrationFk interface {
+	foreignKeyCnfs() []*foreignKeyCnf
+}
+
+type iMigrationView interface {
+	view() bool
+}
+
+type migrationStep struct {
+	up, down preformShare.Ddl
+}
+
+// BuildMigration diff the factories against the database by IDialect.GetStructure, write <version>_<name>.up.sql and .down.sql to path for preform.Migrator
+// call after PrepareSchema, empty version if nothing changed, keep a path per dialect as the sql is rendered by the dialect of conn
+func BuildMigration(conn *sql.DB, path, name string, schemas ...MigrationSchema) (version string, err error) {
+	var (
+		db          = preform.DbFromNative(conn)
+		schemaNames = make([]string, len(schemas))
+	)
+	if err = db.DetectDialect(context.Background()); err != nil {
+		return "", err
+	}
+	for i, schema := range schemas {
+		schemaNames[i] = schema.Name
+	}
+	up, down, err := DiffMigration(db.Dialect(), db.Dialect().GetStructure(conn, schemaNames...), schemas...)
+	if err != nil || len(up) == 0 {
+		return "", err
+	}
+	version = time.Now().UTC().Format("20060102150405")
+	if err = os.MkdirAll(path, 0755); err != nil {
+		return "", err
+	}
+	fileName := fmt.Sprintf("%s/%s_%s", path, version, strcase.ToSnake(name))
+	if err = os.WriteFile(fileName+".up.sql", []byte(strings.Join(up, "\n")+"\n"), 0644); err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(fileName+".down.sql", []byte(strings.Join(down, "\n")+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// DiffMigration statements turning current into the factories and the ones reverting them
+// columns are compared by dataType tag and nullability, not by defaults as databases report them rewritten, e.g. '0'::integer or CURRENT_TIMESTAMP
+// foreign keys by name, statements not supported by the dialect are left as comments, the MigrationTable of the schema is never dropped
+func DiffMigration(iDialect preformShare.IDialect, current []*preformShare.Scheme, schemas ...MigrationSchema) (up, down []string, err error) {
+	desired, untyped, err := migrationSchemes(schemas)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		currentByName = map[string]*preformShare.Scheme{}
+		schemaByName  = map[string]MigrationSchema{}
+		steps         [9][]migrationStep //create types, create tables, add columns, alter columns, drop fks, add fks, drop columns, drop tables, drop types
+		created       []*preformShare.Table
+		dropped       []*preformShare.Table
+	)
+	for _, scheme := range current {
+		currentByName[scheme.Name] = scheme
+	}
+	for _, schema := range schemas {
+		schemaByName[schema.Name] = schema
+	}
+	for _, scheme := range desired {
+		var (
+			cur         = currentByName[scheme.Name]
+			curTables   = map[string]*preformShare.Table{}
+			curEnums    map[string][]string
+			curTypes    map[string]*preformShare.CustomType
+			tableByName = map[string]*preformShare.Table{}
+		)
+		if cur != nil {
+			curEnums, curTypes = cur.Enums, cur.CustomTypes
+			for _, table := range cur.Tables {
+				if !table.IsView {
+					curTables[table.Name] = table
+				}
+			}
+		}
+		for _, name := range sortedKeys(scheme.Enums) {
+			if _, ok := curEnums[name]; !ok {
+				steps[0] = append(steps[0], migrationStep{
+					up:   preformShare.Ddl{Kind: preformShare.DdlCreateEnum, Scheme: scheme.Name, Name: name, Values: scheme.Enums[name]},
+					down: preformShare.Ddl{Kind: preformShare.DdlDropEnum, Scheme: scheme.Name, Name: name},
+				})
+			}
+		}
+		for _, name := range sortedKeys(curEnums) {
+			if _, ok := scheme.Enums[name]; !ok {
+				steps[8] = append(steps[8], migrationStep{
+					up:   preformShare.Ddl{Kind: preformShare.DdlDropEnum, Scheme: scheme.Name, Name: name},
+					down: preformShare.Ddl{Kind: preformShare.DdlCreateEnum, Scheme: scheme.Name, Name: name, Values: curEnums[name]},
+				})
+			}
+		}
+		for _, name := range sortedKeys(scheme.CustomTypes) {
+			if _, ok := curTypes[name]; !ok {
+				steps[0] = append(steps[0], migrationStep{
+					up:   preformShare.Ddl{Kind: preformShare.DdlCreateCustomType, Scheme: scheme.Name, Name: name, CustomType: scheme.CustomTypes[name]},
+					down: preformShare.Ddl{Kind: preformShare.DdlDropCustomType, Scheme: scheme.Name, Name: name},
+				})
+			}
+		}
+		for _, name := range sortedKeys(curTypes) {
+			if _, ok := scheme.CustomTypes[name]; !ok {
+				steps[8] = append(steps[8], migrationStep{
+					up:   preformShare.Ddl{Kind: preformShare.DdlDropCustomType, Scheme: scheme.Name, Name: name},
+					down: preformShare.Ddl{Kind: preformShare.DdlCreateCustomType, Scheme: scheme.Name, Name: name, CustomType: curTypes[name]},
+				})
+			}
+		}
+		for _, table := range scheme.Tables {
+			tableByName[table.Name] = table
+			curTable, ok := curTables[table.Name]
+			if !ok {
+				created = append(created, table)
+				continue
+			}
+			for _, col := range table.Columns {
+				curCol, ok := curTable.ColumnByName[col.Name]
+				if !ok {
+					steps[2] = append(steps[2], migrationStep{
+						up:   preformShare.Ddl{Kind: preformShare.DdlAddColumn, Table: table, Column: col},
+						down: preformShare.Ddl{Kind: preformShare.DdlDropColumn, Table: table, Column: col},
+					})
+					continue
+				}
+				if _, ok = untyped[col]; ok || col.IsAutoKey {
+					if col.Nullable == curCol.Nullable {
+						continue
+					}
+				} else if strings.EqualFold(col.Type, curCol.Type) && col.Nullable == curCol.Nullable {
+					continue
+				}
+				steps[3] = append(steps[3], migrationStep{
+					up:   preformShare.Ddl{Kind: preformShare.DdlAlterColumn, Table: table, Column: col, FromColumn: curCol},
+					down: preformShare.Ddl{Kind: preformShare.DdlAlterColumn, Table: curTable, Column: curCol, FromColumn: col},
+				})
+			}
+			for _, curCol := range curTable.Columns {
+				if _, ok = table.ColumnByName[curCol.Name]; !ok {
+					steps[6] = append(steps[6], migrationStep{
+						up:   preformShare.Ddl{Kind: preformShare.DdlDropColumn, Table: curTable, Column: curCol},
+						down: preformShare.Ddl{Kind: preformShare.DdlAddColumn, Table: curTable, Column: curCol},
+					})
+				}
+			}
+			for _, name := range sortedKeys(curTable.ForeignKeys) {
+				if _, ok = table.ForeignKeys[name]; !ok && !strings.HasPrefix(name, "comment_") {
+					steps[4] = append(steps[4], migrationStep{
+						up:   preformShare.Ddl{Kind: preformShare.DdlDropForeignKey, Table: curTable, ForeignKey: curTable.ForeignKeys[name]},
+						down: preformShare.Ddl{Kind: preformShare.DdlAddForeignKey, Table: curTable, ForeignKey: curTable.ForeignKeys[name]},
+					})
+				}
+			}
+			for _, name := range sortedKeys(table.ForeignKeys) {
+				if _, ok = curTable.ForeignKeys[name]; !ok {
+					steps[5] = append(steps[5], migrationStep{
+						up:   preformShare.Ddl{Kind: preformShare.DdlAddForeignKey, Table: table, ForeignKey: table.ForeignKeys[name]},
+						down: preformShare.Ddl{Kind: preformShare.DdlDropForeignKey, Table: table, ForeignKey: table.ForeignKeys[name]},
+					})
+				}
+			}
+		}
+		if cur != nil {
+			migrationTable := schemaByName[scheme.Name].MigrationTable
+			if migrationTable == "" {
+				migrationTable = preform.MigrationTable
+			}
+			migrationTable = strings.TrimPrefix(migrationTable, scheme.Name+".")
+			for _, table := range cur.Tables {
+				if _, ok := tableByName[table.Name]; !ok && !table.IsView && table.Name != migrationTable {
+					dropped = append(dropped, table)
+				}
+			}
+		}
+	}
+	for _, table := range sortTablesByDependency(created) {
+		steps[1] = append(steps[1], migrationStep{
+			up:   preformShare.Ddl{Kind: preformShare.DdlCreateTable, Table: table},
+			down: preformShare.Ddl{Kind: preformShare.DdlDropTable, Table: table},
+		})
+	}
+	dropped = sortTablesByDependency(dropped)
+	for i := len(dropped) - 1; i >= 0; i-- {
+		steps[7] = append(steps[7], migrationStep{
+			up:   preformShare.Ddl{Kind: preformShare.DdlDropTable, Table: dropped[i]},
+			down: preformShare.Ddl{Kind: preformShare.DdlCreateTable, Table: dropped[i]},
+		})
+	}
+	var (
+		all []migrationStep
+	)
+	for _, phase := range steps {
+		all = append(all, phase...)
+	}
+	for _, step := range all {
+		q, err := renderDdl(iDialect, step.up)
+		if err != nil {
+			return nil, nil, err
+		}
+		if q != "" {
+			up = append(up, q)
+		}
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		q, err := renderDdl(iDialect, all[i].down)
+		if err != nil {
+			return nil, nil, err
+		}
+		if q != "" {
+			down = append(down, q)
+		}
+	}
+	return up, down, nil
+}
+
+func renderDdl(iDialect preformShare.IDialect, ddl preformShare.Ddl) (string, error) {
+	q, err := iDialect.Ddl(ddl)
+	if errors.Is(err, dialect.ErrorNotSupport) {
+		return fmt.Sprintf("-- %s is not supported by the dialect, %s", ddlKindNames[ddl.Kind], ddlTarget(ddl)), nil
+	}
+	if err != nil || q == "" {
+		return "", err
+	}
+	return q + ";", nil
+}
+
+func ddlTarget(ddl preformShare.Ddl) string {
+	switch {
+	case ddl.Column != nil:
+		return fmt.Sprintf("%s.%s.%s", ddl.Table.Scheme.Name, ddl.Table.Name, ddl.Column.Name)
+	case ddl.ForeignKey != nil:
+		return fmt.Sprintf("%s.%s.%s", ddl.Table.Scheme.Name, ddl.Table.Name, ddl.ForeignKey.Name)
+	case ddl.Table != nil:
+		return fmt.Sprintf("%s.%s", ddl.Table.Scheme.Name, ddl.Table.Name)
+	}
+	return fmt.Sprintf("%s.%s", ddl.Scheme, ddl.Name)
+}
+
+// migrationSchemes structure of the factories like GetStructure, untyped columns have no dataType tag and the type is guessed
+func migrationSchemes(schemas []MigrationSchema) (schemes []*preformShare.Scheme, untyped map[*preformShare.Column]struct{}, err error) {
+	var (
+		tables   = map[preformShare.IFactoryBuilder]*preformShare.Table{}
+		builders []preformShare.IFactoryBuilder
+	)
+	untyped = map[*preformShare.Column]struct{}{}
+	for _, schema := range schemas {
+		scheme := &preformShare.Scheme{Name: schema.Name, Enums: schema.Enums, CustomTypes: schema.CustomTypes}
+		for _, f := range schema.Factories {
+			if v, ok := f.(iMigrationView); ok && v.view() {
+				continue
+			}
+			var (
+				table = &preformShare.Table{Name: f.TableName(), Scheme: scheme, ColumnByName: map[string]*preformShare.Column{}, ForeignKeys: map[string]*preformShare.ForeignKey{}}
+				pks   = map[string]int64{}
+			)
+			for i, pk := range f.PK() {
+				pks[pk.CodeName()] = int64(i + 1)
+			}
+			for _, c := range f.Cols() {
+				col := &preformShare.Column{Name: c.Alias(), Table: table}
+				if m, ok := c.(iMigrationCol); ok {
+					col.Type, col.DefaultValue, col.IsAutoKey = m.dbDef()
+				}
+				t, nullable := unwrapNullType(c.GetType())
+				col.Nullable = nullable
+				if col.Type == "" {
+					if col.Type = migrationDataType(t); col.Type == "" {
+						return nil, nil, fmt.Errorf("add dataType tag to %s.%s.%s", schema.Name, table.Name, col.Name)
+					}
+					untyped[col] = struct{}{}
+				}
+				col.PkPos, col.IsPrimaryKey = pks[c.CodeName()], pks[c.CodeName()] != 0
+				table.Columns = append(table.Columns, col)
+				table.ColumnByName[col.Name] = col
+			}
+			tables[f] = table
+			builders = append(builders, f)
+			scheme.Tables = append(scheme.Tables, table)
+		}
+		schemes = append(schemes, scheme)
+	}
+	for _, f := range builders {
+		table := tables[f]
+		for _, c := range f.Cols() {
+			fkDef, ok := c.(iMigrationFk)
+			if !ok {
+				continue
+			}
+			for _, cnf := range fkDef.foreignKeyCnfs() {
+				if cnf.middleTable != nil {
+					continue
+				}
+				target, ok := tables[cnf.col.Factory()]
+				if !ok {
+					return nil, nil, fmt.Errorf("foreign key of %s.%s references %s which is not in the migration", table.Name, c.Alias(), cnf.col.Factory().TableName())
+				}
+				fk := &preformShare.ForeignKey{
+					Name:        cnf.name,
+					LocalKeys:   []*preformShare.Column{table.ColumnByName[c.Alias()]},
+					ForeignKeys: []*preformShare.Column{target.ColumnByName[cnf.col.Alias()]},
+				}
+				for _, keys := range cnf.compositeKeys {
+					fk.LocalKeys = append(fk.LocalKeys, table.ColumnByName[keys[0].Alias()])
+					fk.ForeignKeys = append(fk.ForeignKeys, target.ColumnByName[keys[1].Alias()])
+				}
+				if fk.Name == "" {
+					fk.Name = fmt.Sprintf("fk_%s_%s_%s", table.Name, fk.LocalKeys[0].Name, fk.ForeignKeys[0].Name)
+				}
+				if !strings.HasPrefix(fk.Name, "comment_") {
+					table.ForeignKeys[fk.Name] = fk
+				}
+			}
+		}
+	}
+	return schemes, untyped, nil
+}
+
+func unwrapNullType(t reflect.Type) (reflect.Type, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if t.Kind() == reflect.Ptr {
+		return t.Elem(), true
+	}
+	if t.Kind() == reflect.Struct && (t.PkgPath() == nullPkgPath && strings.HasPrefix(t.Name(), "Null[") || t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null")) {
+		if f, ok := t.FieldByName("V"); ok {
+			return f.Type, true
+		}
+		return t.Field(0).Type, true
+	}
+	return t, false
+}
+
+// migrationDataType guess the type of a column without dataType tag
+func migrationDataType(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	if t.ConvertibleTo(timeType) {
+		return "TIMESTAMP"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "INTEGER"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "BIGINT"
+	case reflect.Float32:
+		return "REAL"
+	case reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.String:
+		return "TEXT"
+	}
+	return ""
+}
+
+// sortTablesByDependency referenced tables first
+func sortTablesByDependency(tables []*preformShare.Table) []*preformShare.Table {
+	var (
+		sorted  = make([]*preformShare.Table, 0, len(tables))
+		pending = map[*preformShare.Table]bool{}
+		visit   func(table *preformShare.Table)
+	)
+	for _, table := range tables {
+		pending[table] = true
+	}
+	visit = func(table *preformShare.Table) {
+		if !pending[table] {
+			return
+		}
+		delete(pending, table)
+		for _, name := range sortedKeys(table.ForeignKeys) {
+			visit(table.ForeignKeys[name].ForeignKeys[0].Table)
+		}
+		sorted = append(sorted, table)
+	}
+	for _, table := range tables {
+		visit(table)
+	}
+	return sorted
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var (
+		keys = make([]string, 0, len(m))
+	)
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -19,6 +19,14 @@ type QueryBuilder struct {
 	cols       []preformShare.IColDef
 	schemas    []string
 	setter     any
+	ctes       []*queryBuilderCte
+}
+
+type queryBuilderCte struct {
+	src       preformShare.IFactoryBuilder
+	conds     []preformShare.ICondForBuilder
+	parentKey preformShare.IColDef
+	distinct  bool
 }
 
 func BuildQuery(name string, funcAcceptBuilderPlusModels any) *QueryBuilder {
@@ -128,6 +136,24 @@ func (builder *QueryBuilder) InnerJoinByForeignKey(fk foreignKeyForJoin, cond ..
 	return builder.joinByForeignKey("Inner", fk, cond...)
 }
 
+// With define a cte of src filtered by cond, From / joins of src then select from the cte
+func (builder *QueryBuilder) With(src preformShare.IFactoryBuilder, cond ...preformShare.ICondForBuilder) *QueryBuilder {
+	builder.ctes = append(builder.ctes, &queryBuilderCte{src: src, conds: cond})
+	return builder
+}
+
+// WithRecursive define a recursive cte of src, starting from rows matching anchor then walking down parentKey (a self fk of src, e.g. CreatedBy)
+func (builder *QueryBuilder) WithRecursive(src preformShare.IFactoryBuilder, anchor preformShare.ICondForBuilder, parentKey preformShare.IColDef) *QueryBuilder {
+	builder.ctes = append(builder.ctes, &queryBuilderCte{src: src, conds: []preformShare.ICondForBuilder{anchor}, parentKey: parentKey})
+	return builder
+}
+
+// WithRecursiveDistinct as WithRecursive but duplicated rows are dropped, for parentKey forming cycles
+func (builder *QueryBuilder) WithRecursiveDistinct(src preformShare.IFactoryBuilder, anchor preformShare.ICondForBuilder, parentKey preformShare.IColDef) *QueryBuilder {
+	builder.ctes = append(builder.ctes, &queryBuilderCte{src: src, conds: []preformShare.ICondForBuilder{anchor}, parentKey: parentKey, distinct: true})
+	return builder
+}
+
 func (builder *QueryBuilder) Cols(cols ...preformShare.IColDef) *QueryBuilder {
 	builder.cols = append(builder.cols, cols...)
 	return builder
@@ -175,11 +201,38 @@ func (builder *QueryBuilder) GenerateCode(schemaName string) (name, schemaField,
 	for _, s := range builder.schemas {
 		schemaInputs = append(schemaInputs, fmt.Sprintf(`%sSchema *%sSchema`, s, s))
 	}
+	for _, cte := range builder.ctes {
+		var (
+			found     bool
+			condCodes []string
+		)
+		for _, src := range builder.src {
+			if src.src == cte.src {
+				found = true
+				break
+			}
+		}
+		if !found {
+			panic(fmt.Errorf("query %s: cte src %s must be used in From or join", builder.name, cte.src.CodeName()))
+		}
+		for _, cond := range cte.conds {
+			condCodes = append(condCodes, cond.ToCode())
+		}
+		if cte.parentKey != nil {
+			withFunc := "WithRecursive"
+			if cte.distinct {
+				withFunc = "WithRecursiveDistinct"
+			}
+			subSettingCodes = append(subSettingCodes, fmt.Sprintf(`%s(d.%s, %s, d.%sSchema.%s.%s)`, withFunc, cte.src.Alias(), condCodes[0], cte.parentKey.Factory().SchemaName(), cte.parentKey.Factory().Alias(), cte.parentKey.SrcName()))
+		} else {
+			subSettingCodes = append(subSettingCodes, fmt.Sprintf(`With(%s)`, strings.Join(append([]string{"d." + cte.src.Alias()}, condCodes...), ", ")))
+		}
+	}
 	for i, src := range builder.src {
 		defColCodes = append(defColCodes, fmt.Sprintf(`%s *Factory%s`, src.src.Alias(), src.src.CodeName()))
 		settingCodes = append(settingCodes, fmt.Sprintf("d.%s = d.%sSchema.%s.SetAlias(\"%s\").(*Factory%s)", src.src.Alias(), src.src.SchemaName(), src.src.CodeName(), src.src.Alias(), src.src.CodeName()))
 		if i == 0 {
-			subSettingCodes = append(subSettingCodes, fmt.Sprintf(`d.SetSrc(d.%s)`, src.src.Alias()))
+			subSettingCodes = append(subSettingCodes, fmt.Sprintf(`SetSrc(d.%s)`, src.src.Alias()))
 		} else {
 			subSettingCodes = append(subSettingCodes, fmt.Sprintf(
 				`Join("%s", d.%s, %s)`,
@@ -189,7 +242,7 @@ func (builder *QueryBuilder) GenerateCode(schemaName string) (name, schemaField,
 			))
 		}
 	}
-	settingCodes = append(settingCodes, strings.Join(subSettingCodes, ".\n\t\t")+".DefineCols(")
+	settingCodes = append(settingCodes, "d."+strings.Join(subSettingCodes, ".\n\t\t")+".DefineCols(")
 	if len(builder.cols) == 0 {
 		for _, src := range builder.src {
 			for _, col := range src.src.Cols() {
@@ -5,6 +5,7 @@ import (
 	preformShare "github.com/go-preform/preform/share"
 	"github.com/iancoleman/strcase"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -50,8 +51,6 @@ func (c ConditionForBuilder[T]) ToCondCode() string {
 			} else {
 				t := reflect.TypeOf(a)
 				switch t.Kind() {
-				case reflect.String:
-					argsCodes = append(argsCodes, fmt.Sprintf("\"%s\"", a))
 				case reflect.Array, reflect.Slice:
 					if cc[0] == "And" || cc[0] == "Or" {
 
@@ -62,17 +61,10 @@ func (c ConditionForBuilder[T]) ToCondCode() string {
 							argsCodes = append(argsCodes, cond.ToCondCode())
 						}
 					} else {
-						var (
-							v  = reflect.ValueOf(a)
-							vs = make([]any, v.Len())
-						)
-						for i := 0; i < v.Len(); i++ {
-							vs[i] = v.Index(i).Interface()
-						}
-						argsCodes = append(argsCodes, fmt.Sprintf("[]any{"+strings.Repeat(",%s", v.Len())[1:]+"}", vs...))
+						argsCodes = append(argsCodes, valueCode(a))
 					}
 				default:
-					argsCodes = append(argsCodes, fmt.Sprintf("%v", a))
+					argsCodes = append(argsCodes, valueCode(a))
 				}
 			}
 		}
@@ -121,8 +113,6 @@ func (c ConditionForBuilder[T]) ToCode() string {
 			} else {
 				t := reflect.TypeOf(a)
 				switch t.Kind() {
-				case reflect.String:
-					argsCodes = append(argsCodes, fmt.Sprintf("\"%s\"", a))
 				case reflect.Array, reflect.Slice:
 					if cc[0] == "And" || cc[0] == "Or" {
 
@@ -133,17 +123,10 @@ func (c ConditionForBuilder[T]) ToCode() string {
 							argsCodes = append(argsCodes, cond.ToCode())
 						}
 					} else {
-						var (
-							v  = reflect.ValueOf(a)
-							vs = make([]any, v.Len())
-						)
-						for i := 0; i < v.Len(); i++ {
-							vs[i] = v.Index(i).Interface()
-						}
-						argsCodes = append(argsCodes, fmt.Sprintf("[]any{"+strings.Repeat(",%s", v.Len())[1:]+"}", vs...))
+						argsCodes = append(argsCodes, valueCode(a))
 					}
 				default:
-					argsCodes = append(argsCodes, fmt.Sprintf("%v", a))
+					argsCodes = append(argsCodes, valueCode(a))
 				}
 			}
 		}
@@ -207,6 +190,50 @@ func (c ConditionForBuilder[T]) Between(value1, value2 any) ConditionForBuilder[
 	return c
 }
 
+// NotBetween
+func (c ConditionForBuilder[T]) NotBetween(value1, value2 any) ConditionForBuilder[T] {
+	c.conditions = append(c.conditions, []any{"NotBetween", value1, value2})
+	return c
+}
+
+// In values, columns or a slice, sub queries can't be generated
+func (c ConditionForBuilder[T]) In(values ...any) ConditionForBuilder[T] {
+	checkInValues("In", values)
+	c.conditions = append(c.conditions, append([]any{"In"}, values...))
+	return c
+}
+
+// NotIn see In
+func (c ConditionForBuilder[T]) NotIn(values ...any) ConditionForBuilder[T] {
+	checkInValues("NotIn", values)
+	c.conditions = append(c.conditions, append([]any{"NotIn"}, values...))
+	return c
+}
+
+// IsNull
+func (c ConditionForBuilder[T]) IsNull() ConditionForBuilder[T] {
+	c.conditions = append(c.conditions, []any{"IsNull"})
+	return c
+}
+
+// IsNotNull
+func (c ConditionForBuilder[T]) IsNotNull() ConditionForBuilder[T] {
+	c.conditions = append(c.conditions, []any{"IsNotNull"})
+	return c
+}
+
+// NotLike
+func (c ConditionForBuilder[T]) NotLike(value any) ConditionForBuilder[T] {
+	c.conditions = append(c.conditions, []any{"NotLike", value})
+	return c
+}
+
+// ILike
+func (c ConditionForBuilder[T]) ILike(value any) ConditionForBuilder[T] {
+	c.conditions = append(c.conditions, []any{"ILike", value})
+	return c
+}
+
 func (c ConditionForBuilder[T]) Any(value any) ConditionForBuilder[T] {
 	c.conditions = append(c.conditions, []any{"Any", value})
 	return c
@@ -232,3 +259,48 @@ func (c ConditionForBuilder[T]) Or(cond ...preformShare.ICondForBuilder) Conditi
 	c.conditions = append(c.conditions, []any{"Or", cond})
 	return c
 }
+
+// valueCode go literal of a value or a slice of values
+func valueCode(a any) string {
+	v := reflect.ValueOf(a)
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Array, reflect.Slice:
+		codes := make([]string, v.Len())
+		for i := range codes {
+			codes[i] = valueCode(v.Index(i).Interface())
+		}
+		return "[]any{" + strings.Join(codes, ", ") + "}"
+	}
+	return fmt.Sprintf("%v", a)
+}
+
+func isLiteral(a any) bool {
+	switch reflect.ValueOf(a).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// checkInValues panic on values the query code can't be generated from, e.g. sub query builders
+func checkInValues(op string, values []any) {
+	for _, a := range values {
+		if _, ok := a.(preformShare.IColDef); ok || isLiteral(a) {
+			continue
+		}
+		if v := reflect.ValueOf(a); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for i := 0; i < v.Len(); i++ {
+				if !isLiteral(v.Index(i).Interface()) {
+					panic(fmt.Sprintf("%s of prebuild query takes values, columns or slices of values, not %T in %T", op, v.Index(i).Interface(), a))
+				}
+			}
+			continue
+		}
+		panic(fmt.Sprintf("%s of prebuild query takes values, columns or slices of values, not %T", op, a))
+	}
+}
@@ -0,0 +1,99 @@
+package preformBuilder
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	preformTypes "github.com/go-preform/preform/types"
+	"github.com/iancoleman/strcase"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WindowClause PARTITION BY, ORDER BY or frame of aggregatedCol.Over, rendered as preform.PartitionBy etc.
+type WindowClause struct {
+	fn   string
+	cols []preformShare.IColDef
+	desc bool
+	args []string
+}
+
+func PartitionBy(cols ...preformShare.IColDef) WindowClause {
+	return WindowClause{fn: "PartitionBy", cols: cols}
+}
+
+func OrderBy(cols ...preformShare.IColDef) WindowClause {
+	return WindowClause{fn: "OrderBy", cols: cols}
+}
+
+func OrderByDesc(cols ...preformShare.IColDef) WindowClause {
+	return WindowClause{fn: "OrderBy", cols: cols, desc: true}
+}
+
+// RowsBetween bounds as preform.UnboundedPreceding, preform.CurrentRow, preform.Preceding(n) etc.
+func RowsBetween(from, to string) WindowClause {
+	return WindowClause{fn: "RowsBetween", args: []string{from, to}}
+}
+
+func RangeBetween(from, to string) WindowClause {
+	return WindowClause{fn: "RangeBetween", args: []string{from, to}}
+}
+
+func (w WindowClause) toCode() string {
+	var (
+		argCodes = make([]string, 0, len(w.cols)+len(w.args))
+		code     string
+	)
+	for _, col := range w.cols {
+		code = fmt.Sprintf("d.%s.%s", col.Factory().Alias(), col.SrcName())
+		if w.desc {
+			code = fmt.Sprintf("%s.OrderDesc(%s)", pkgName, code)
+		}
+		argCodes = append(argCodes, code)
+	}
+	for _, arg := range w.args {
+		argCodes = append(argCodes, strconv.Quote(arg))
+	}
+	return fmt.Sprintf("%s.%s(%s)", pkgName, w.fn, strings.Join(argCodes, ", "))
+}
+
+func windowClausesToCode(clauses []WindowClause) string {
+	var (
+		codes = make([]string, len(clauses))
+	)
+	for i, clause := range clauses {
+		codes[i] = clause.toCode()
+	}
+	return strings.Join(codes, ", ")
+}
+
+// Over run the aggregation or window function over the window
+func (a *aggregatedCol[T]) Over(clauses ...WindowClause) *aggregatedCol[T] {
+	a.over = append([]WindowClause{}, clauses...)
+	return a
+}
+
+func (a columnDef[T]) window(fn string, aggregatedType reflect.Type, params ...string) *aggregatedCol[T] {
+	return &aggregatedCol[T]{a.setAlias(strcase.ToCamel(a.CodeName() + " " + fn)), append([]string{fn}, params...), aggregatedType, nil}
+}
+
+func (a columnDef[T]) RowNumber() *aggregatedCol[T] {
+	return a.window("RowNumber", reflect.TypeOf(int64(0)))
+}
+
+func (a columnDef[T]) Rank() *aggregatedCol[T] {
+	return a.window("Rank", reflect.TypeOf(int64(0)))
+}
+
+func (a columnDef[T]) DenseRank() *aggregatedCol[T] {
+	return a.window("DenseRank", reflect.TypeOf(int64(0)))
+}
+
+// Lag typed as preformTypes.Null[T] since the first rows have no previous value
+func (a columnDef[T]) Lag(offset int) *aggregatedCol[T] {
+	return a.window("Lag", reflect.TypeOf(preformTypes.Null[T]{}), strconv.Itoa(offset))
+}
+
+func (a columnDef[T]) Lead(offset int) *aggregatedCol[T] {
+	return a.window("Lead", reflect.TypeOf(preformTypes.Null[T]{}), strconv.Itoa(offset))
+}
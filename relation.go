@@ -28,6 +28,10 @@ type IRelation interface {
 	prepare(name string, pos uint32)
 	JoinClause() ForeignKeyJoin
 	IsMiddleTable() bool
+	IsMany() bool
+	Exists(conds ...ICond) ICond
+	NotExists(conds ...ICond) ICond
+	existsQuery(localKey func(ICol) ICol, conds []ICond) (string, []any, error)
 }
 
 type iRelation[TargetBody any] interface {
@@ -92,6 +96,10 @@ func (r relation[SrcBody, TargetFactory, TargetBody]) IsMiddleTable() bool {
 
 }
 
+func (r relation[SrcBody, TargetFactory, TargetBody]) IsMany() bool {
+	return r.isMany
+}
+
 func (r relation[SrcBody, TargetFactory, TargetBody]) unwrapPtrBodyToTargetBodies(ptr any) []any {
 	var (
 		res  []any
@@ -0,0 +1,137 @@
+package preform
+
+import (
+	"fmt"
+	"github.com/go-preform/squirrel"
+	"strings"
+)
+
+// relationExists EXISTS / NOT EXISTS of the related rows, by a sub-query correlated with the local keys
+type relationExists struct {
+	relation     IRelation
+	conds        []ICond
+	not          bool
+	noParentCode bool
+}
+
+func (e relationExists) ToSql() (string, []any, error) {
+	var localKey = func(col ICol) ICol { return col }
+	if e.noParentCode {
+		localKey = tableCodeCol
+	}
+	query, args, err := e.relation.existsQuery(localKey, e.conds)
+	if err != nil {
+		return "", nil, err
+	}
+	if e.not {
+		return fmt.Sprintf("NOT EXISTS (%s)", query), args, nil
+	}
+	return fmt.Sprintf("EXISTS (%s)", query), args, nil
+}
+
+// tableCodeCol update and delete have no table alias, local keys in the sub-query must be qualified by the table name
+func tableCodeCol(col ICol) ICol {
+	f := col.QueryFactory().(IFactory)
+	cc := newColWrap(col, f, "")
+	cc.code = f.tableNameWithParent() + "." + f.Db().GetDialect().QuoteIdentifier(col.DbName())
+	return cc
+}
+
+// Exists related rows matching conds exist, conditions on the target factory are rendered with the relation alias
+func (r relation[SrcBody, TargetFactory, TargetBody]) Exists(conds ...ICond) ICond {
+	return relationExists{relation: r.relationPtr, conds: conds}
+}
+
+// NotExists no related rows matching conds
+func (r relation[SrcBody, TargetFactory, TargetBody]) NotExists(conds ...ICond) ICond {
+	return relationExists{relation: r.relationPtr, conds: conds, not: true}
+}
+
+func (r relation[SrcBody, TargetFactory, TargetBody]) existsQuery(localKey func(ICol) ICol, conds []ICond) (string, []any, error) {
+	var (
+		cond = make(squirrel.And, len(r.foreignKeys))
+	)
+	for i, c := range r.foreignKeys {
+		cond[i] = keyPairCond(localKey(r.localKeys[i]), c)
+	}
+	query, args, err := append(cond, r.existsConds(localKey, conds)...).ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("SELECT 1 FROM %s WHERE %s", r.targetFactory.fromClause(), query), args, nil
+}
+
+// existsConds extra conditions of the relation, conds, the fixed condition of the target and its soft delete
+func (r relation[SrcBody, TargetFactory, TargetBody]) existsConds(localKey func(ICol) ICol, conds []ICond) []ICond {
+	var (
+		res         = make([]ICond, 0, len(r.cond)+len(conds)+1)
+		localTable  = r.localKeys[0].Factory().tableNameWithParent()
+		targetTable = r.targetFactory.tableNameWithParent()
+	)
+	res = append(res, r.cond...)
+	res = append(res, conds...)
+	if fixedCond := r.targetFactory.FixedCondition(); fixedCond != nil {
+		res = append(res, fixedCond)
+	}
+	if trashed := notTrashedCond(r.targetFactory); trashed != nil {
+		res = append(res, trashed)
+	}
+	var reAlias func(cond ICond) ICond
+	reAlias = func(cond ICond) ICond {
+		switch c := cond.(type) {
+		case colConditioner:
+			switch c.col.QueryFactory().tableNameWithParent() {
+			case targetTable:
+				c.col = r.targetFactory.Columns()[c.col.GetPos()].(IColFromFactory)
+			case localTable:
+				c.col = localKey(c.col)
+			}
+			return c.mapNested(reAlias)
+		case squirrel.And:
+			res := make(squirrel.And, len(c))
+			for i := range c {
+				res[i] = reAlias(c[i])
+			}
+			return res
+		case squirrel.Or:
+			res := make(squirrel.Or, len(c))
+			for i := range c {
+				res[i] = reAlias(c[i])
+			}
+			return res
+		}
+		return cond
+	}
+	for i, cond := range res {
+		res[i] = reAlias(cond)
+	}
+	return res
+}
+
+// existsQuery the target table is joined only if there are conditions on it
+func (r MiddleTable[SrcBody, TargetFactory, TargetBody, MiddleBody]) existsQuery(localKey func(ICol) ICol, conds []ICond) (string, []any, error) {
+	var (
+		from  = r.middleTable.fromClause()
+		cond  = make(squirrel.And, len(r.localKeyRefs))
+		extra = r.existsConds(localKey, conds)
+	)
+	for i, c := range r.localKeyRefs {
+		cond[i] = keyPairCond(localKey(r.localKeys[i]), c)
+	}
+	if len(extra) != 0 {
+		var (
+			d        = r.targetFactory.Db().GetDialect()
+			onClause = make([]string, len(r.foreignKeys))
+		)
+		for i, c := range r.foreignKeys {
+			onClause[i] = fmt.Sprintf("%s.%s = %s.%s", d.QuoteIdentifier(r.middleTable.Alias()), d.QuoteIdentifier(r.foreignKeyRefs[i].DbName()), d.QuoteIdentifier(r.targetFactory.Alias()), d.QuoteIdentifier(c.DbName()))
+		}
+		from = fmt.Sprintf("%s JOIN %s ON %s", from, r.targetFactory.fromClause(), strings.Join(onClause, " AND "))
+		cond = append(cond, extra...)
+	}
+	query, args, err := cond.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("SELECT 1 FROM %s WHERE %s", from, query), args, nil
+}
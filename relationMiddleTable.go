@@ -370,6 +370,38 @@ func (b modelScannerWithRelated[B, RELATED]) ScanBodiesFast(rows IRows, cols []I
 	return res, err
 
 }
+func (b modelScannerWithRelated[B, RELATED]) ScanEach(rows IRows, cols []ICol, fn func(*modelWithRelation[B, RELATED]) error) error {
+	var (
+		model       modelWithRelation[B, RELATED]
+		scanPtrs    []any
+		sortedPtrs  []any
+		mtbScanPtrs []any
+		err         error
+		l           = len(cols)
+		ll          = l - len(b.refKeyPos)
+	)
+	scanPtrs = any(&model.Body).(hasFieldValuePtrs).FieldValuePtrs()
+	mtbScanPtrs = any(&model.Related).(hasFieldValuePtrs).FieldValuePtrs()
+	sortedPtrs = make([]any, 0, l)
+	for _, col := range cols[:ll] {
+		sortedPtrs = append(sortedPtrs, col.wrapScanner(scanPtrs[col.GetPos()]))
+	}
+	for i, pos := range b.refKeyPos {
+		sortedPtrs = append(sortedPtrs, cols[ll+i].wrapScanner(mtbScanPtrs[pos]))
+	}
+	for rows.Next() {
+		err = rows.Scan(sortedPtrs...)
+		if err != nil {
+			return err
+		}
+		row := model
+		err = fn(&row)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
 func (b modelScannerWithRelated[B, RELATED]) ScanBody(row IRow, cols []ICol) (*modelWithRelation[B, RELATED], error) {
 	return nil, nil
 }
@@ -69,6 +69,21 @@ func (s *Array[T]) Scan(src any) (err error) {
 			scanner = &Json{}
 		}
 	}
+	if list, ok := src.([]any); ok { //duckdb LIST
+		tmp := make([]T, len(list))
+		for i, e := range list {
+			scanner.Ptr(&tmp[i])
+			err = scanner.Scan(e)
+			if err != nil {
+				return err
+			}
+		}
+		*s.Value = tmp
+		if s.ValueAny != nil {
+			*s.ValueAny = *s.Value
+		}
+		return nil
+	}
 	dims, elems, err := parseArray(src.([]byte), []byte(","))
 	if err != nil {
 		return err
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	preformShare "github.com/go-preform/preform/share"
+	"io/fs"
 	"reflect"
+	"time"
 )
 
 var (
@@ -129,7 +131,37 @@ func (s Schema[TPtr, T]) BeginTx(ctx context.Context) (*Tx, error) {
 	return s.db.BeginTx(ctx)
 }
 
-// deprecated, slow
+// DetectDialect see db.DetectDialect
+func (s *Schema[TPtr, T]) DetectDialect(ctx context.Context) error {
+	return s.db.DetectDialect(ctx)
+}
+
+// SetReplicas see db.SetReplicas
+func (s *Schema[TPtr, T]) SetReplicas(conns ...*sql.DB) {
+	s.db.SetReplicas(conns...)
+}
+
+// SetReplicaHealthCheck see db.SetReplicaHealthCheck
+func (s *Schema[TPtr, T]) SetReplicaHealthCheck(interval, maxLag time.Duration) {
+	s.db.SetReplicaHealthCheck(interval, maxLag)
+}
+
+// WithTx run fn in a transaction carried by ctx, see db.WithTx
+func (s Schema[TPtr, T]) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.db.WithTx(ctx, fn)
+}
+
+// WithTxRetry run fn in a transaction by policy, see db.WithTxRetry
+func (s Schema[TPtr, T]) WithTxRetry(ctx context.Context, policy *RetryPolicy, fn func(ctx context.Context) error) error {
+	return s.db.WithTxRetry(ctx, policy, fn)
+}
+
+// NewMigrator see db.NewMigrator
+func (s Schema[TPtr, T]) NewMigrator(fsys fs.FS, table ...string) *Migrator {
+	return s.db.NewMigrator(fsys, table...)
+}
+
+// Deprecated: slow, clones the schema per call, use WithTx
 func (s Schema[TPtr, T]) RunTx(fn func(t TPtr) error) error {
 	var (
 		tPtr = s.instance.Clone(s.SchemaName).(TPtr)
@@ -0,0 +1,239 @@
+package preform
+
+import (
+	"context"
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"reflect"
+	"strings"
+)
+
+type DriftKind uint8
+
+const (
+	DriftMissingTable      DriftKind = iota //table of the factory not in db
+	DriftMissingColumn                      //column of the factory not in db
+	DriftExtraColumn                        //column in db not in the factory
+	DriftType                               //dataType tag differs from db
+	DriftNullable                           //Null / pointer type differs from db nullability
+	DriftPrimaryKey                         //primary keys differ
+	DriftMissingForeignKey                  //to one relation without foreign key in db
+)
+
+var (
+	driftKindNames = [...]string{"missing table", "missing column", "extra column", "type mismatch", "nullable mismatch", "primary key mismatch", "missing foreign key"}
+)
+
+func (k DriftKind) String() string {
+	return driftKindNames[k]
+}
+
+// Drift a difference between a factory and the live table, Expected by the factory and Actual in db
+type Drift struct {
+	Kind     DriftKind
+	Table    string
+	Column   string
+	Expected string
+	Actual   string
+}
+
+func (d Drift) String() string {
+	if d.Column == "" {
+		return fmt.Sprintf("%s %s", d.Kind, d.Table)
+	}
+	if d.Expected == "" && d.Actual == "" {
+		return fmt.Sprintf("%s %s.%s", d.Kind, d.Table, d.Column)
+	}
+	return fmt.Sprintf("%s %s.%s, expected %s, actual %s", d.Kind, d.Table, d.Column, d.Expected, d.Actual)
+}
+
+// SchemaDrift report of Schema.Verify
+type SchemaDrift struct {
+	Schema string
+	Drifts []Drift
+}
+
+// Ok the factories match the db
+func (r SchemaDrift) Ok() bool {
+	return len(r.Drifts) == 0
+}
+
+// Error one drift per line, so the report can be returned as error to fail fast
+func (r SchemaDrift) Error() string {
+	lines := make([]string, len(r.Drifts))
+	for i, d := range r.Drifts {
+		lines[i] = d.String()
+	}
+	return fmt.Sprintf("schema %s drifted from the models:\n%s", r.Schema, strings.Join(lines, "\n"))
+}
+
+type iColDbDef interface {
+	dbDef() (dbType string, nullable bool)
+}
+
+func (c column[T]) dbDef() (dbType string, nullable bool) {
+	var (
+		t = reflect.TypeOf((*T)(nil)).Elem()
+	)
+	nullable = c.isPtr || t.Kind() == reflect.Struct && (strings.HasSuffix(t.PkgPath(), "/preform/types") && strings.HasPrefix(t.Name(), "Null[") || t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null"))
+	return c.dbType, nullable
+}
+
+func (c *ColumnWrap[C]) dbDef() (dbType string, nullable bool) {
+	if def, ok := any(c.col).(iColDbDef); ok {
+		return def.dbDef()
+	}
+	return "", false
+}
+
+// Verify compare the factories with the live db by IDialect.GetStructure, columns by db name, dataType tag, nullability and pk, to one relations by foreign keys
+// err only if the structure can't be read, check report.Ok() to fail fast or log the drifts on boot
+func (s *Schema[TPtr, T]) Verify(ctx context.Context) (report SchemaDrift, err error) {
+	if ctx == nil {
+		ctx = s.db.ctx
+	}
+	report.Schema = s.DbName()
+	if err = ctx.Err(); err != nil {
+		return report, err
+	}
+	var (
+		schemaNames = []string{s.DbName()}
+		seen        = map[string]struct{}{s.DbName(): {}}
+		res         = make(chan []*preformShare.Scheme, 1) //buffered so GetStructure ends without a receiver once ctx is done
+		pan         = make(chan any, 1)
+	)
+	for _, f := range s.instance.Factories() {
+		for _, r := range f.Relations() {
+			if name := r.TargetFactory().Schema().DbName(); name != "" {
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					schemaNames = append(schemaNames, name) //foreign keys to other schemas
+				}
+			}
+		}
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				pan <- r
+			}
+		}()
+		res <- s.db.dialect.GetStructure(s.db.DB.DB, schemaNames...)
+	}()
+	var (
+		schemes []*preformShare.Scheme
+	)
+	select {
+	case schemes = <-res:
+	case r := <-pan:
+		return report, fmt.Errorf("get structure of %s: %v", s.DbName(), r)
+	case <-ctx.Done():
+		return report, ctx.Err()
+	}
+	var (
+		tables = map[string]*preformShare.Table{}
+	)
+	for _, scheme := range schemes {
+		for _, table := range scheme.Tables {
+			tables[verifyTableKey(scheme.Name, table.Name)] = table
+		}
+	}
+	for _, f := range s.instance.Factories() {
+		table, ok := tables[verifyTableKey(s.DbName(), f.TableName())]
+		if !ok {
+			report.Drifts = append(report.Drifts, Drift{Kind: DriftMissingTable, Table: f.TableName()})
+			continue
+		}
+		report.Drifts = append(report.Drifts, verifyColumns(f, table)...)
+		if !table.IsView {
+			report.Drifts = append(report.Drifts, verifyForeignKeys(f, table, tables)...)
+		}
+	}
+	return report, nil
+}
+
+// verifyTableKey identifiers are case insensitive unless quoted, models are generated from unquoted names mostly
+func verifyTableKey(schema, table string) string {
+	return strings.ToLower(schema + "." + table)
+}
+
+func verifyColumns(f IFactory, table *preformShare.Table) (drifts []Drift) {
+	var (
+		colNames = map[string]struct{}{}
+	)
+	for _, col := range f.Columns() {
+		colNames[col.DbName()] = struct{}{}
+		dbCol, ok := table.ColumnByName[col.DbName()]
+		if !ok {
+			drifts = append(drifts, Drift{Kind: DriftMissingColumn, Table: table.Name, Column: col.DbName()})
+			continue
+		}
+		if def, ok := col.(iColDbDef); ok {
+			dbType, nullable := def.dbDef()
+			if dbType != "" && !strings.EqualFold(dbType, dbCol.Type) {
+				drifts = append(drifts, Drift{Kind: DriftType, Table: table.Name, Column: col.DbName(), Expected: dbType, Actual: dbCol.Type})
+			}
+			if nullable != dbCol.Nullable && !table.IsView {
+				drifts = append(drifts, Drift{Kind: DriftNullable, Table: table.Name, Column: col.DbName(), Expected: fmt.Sprint(nullable), Actual: fmt.Sprint(dbCol.Nullable)})
+			}
+		}
+		if _, _, isPk, _ := col.properties(); isPk != dbCol.IsPrimaryKey && !table.IsView {
+			drifts = append(drifts, Drift{Kind: DriftPrimaryKey, Table: table.Name, Column: col.DbName(), Expected: fmt.Sprint(isPk), Actual: fmt.Sprint(dbCol.IsPrimaryKey)})
+		}
+	}
+	for _, dbCol := range table.Columns {
+		if _, ok := colNames[dbCol.Name]; !ok && dbCol.Type != "" {
+			drifts = append(drifts, Drift{Kind: DriftExtraColumn, Table: table.Name, Column: dbCol.Name, Actual: dbCol.Type})
+		}
+	}
+	return drifts
+}
+
+// verifyForeignKeys to one relations need a foreign key in db, either way for one to one
+func verifyForeignKeys(f IFactory, table *preformShare.Table, tables map[string]*preformShare.Table) (drifts []Drift) {
+	var (
+		checked = map[string]struct{}{}
+	)
+	for _, r := range f.Relations() {
+		if r.IsMany() || r.IsMiddleTable() {
+			continue
+		}
+		var (
+			target     = verifyTableKey(r.TargetFactory().Schema().DbName(), r.TargetFactory().TableName())
+			localKeys  = make([]string, len(r.LocalKeys()))
+			targetKeys = make([]string, len(r.ForeignKeys()))
+		)
+		for i, col := range r.LocalKeys() {
+			localKeys[i] = col.DbName()
+		}
+		for i, col := range r.ForeignKeys() {
+			targetKeys[i] = col.DbName()
+		}
+		key := fmt.Sprintf("%s->%s.%s", strings.Join(localKeys, ","), target, strings.Join(targetKeys, ","))
+		if _, ok := checked[key]; ok {
+			continue
+		}
+		checked[key] = struct{}{}
+		if hasForeignKey(table, localKeys, target, targetKeys) || tables[target] != nil && hasForeignKey(tables[target], targetKeys, verifyTableKey(table.Scheme.Name, table.Name), localKeys) {
+			continue
+		}
+		drifts = append(drifts, Drift{Kind: DriftMissingForeignKey, Table: table.Name, Column: strings.Join(localKeys, ","), Expected: fmt.Sprintf("%s.%s(%s)", r.TargetFactory().Schema().DbName(), r.TargetFactory().TableName(), strings.Join(targetKeys, ","))})
+	}
+	return drifts
+}
+
+func hasForeignKey(table *preformShare.Table, localKeys []string, target string, targetKeys []string) bool {
+loopFk:
+	for _, fk := range table.ForeignKeys {
+		if len(fk.LocalKeys) != len(localKeys) || len(fk.ForeignKeys) != len(targetKeys) || verifyTableKey(fk.ForeignKeys[0].Table.Scheme.Name, fk.ForeignKeys[0].Table.Name) != target {
+			continue
+		}
+		for i, col := range fk.LocalKeys {
+			if col.Name != localKeys[i] || fk.ForeignKeys[i].Name != targetKeys[i] {
+				continue loopFk
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -12,6 +12,8 @@ type SelectQuery[B any] struct {
 	scanner                  IModelScanner[B]
 	selectBuilder            preformShare.SelectBuilder
 	limit                    uint64
+	offset                   uint64
+	ordered                  bool
 	db                       DB
 	queryFactory             IQuery
 	relatedFactoriesForCache []preformShare.IQueryFactory
@@ -22,6 +24,12 @@ type SelectQuery[B any] struct {
 	prepared                 *sqlx.Stmt
 	eagerLoaders             []IEagerLoader
 	forceBodyScan            bool
+	ctes                     *cteClause
+	lock                     *preformShare.RowLock
+	eachBatch                int
+	trashed                  *trashedCond
+	primary                  bool
+	err                      error
 }
 
 func (b *SelectQuery[B]) PlaceholderFormat(f squirrel.PlaceholderFormat) *SelectQuery[B] {
@@ -30,7 +38,30 @@ func (b *SelectQuery[B]) PlaceholderFormat(f squirrel.PlaceholderFormat) *Select
 }
 
 func (b SelectQuery[B]) ToSql() (string, []interface{}, error) {
-	return b.selectBuilder.ToSql()
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	b.fillFactoryColumns()
+	if b.lock != nil {
+		return b.lockedToSql()
+	}
+	builder, err := b.pagedBuilder()
+	if err != nil {
+		return "", nil, err
+	}
+	return builder.ToSql()
+}
+
+// pagedBuilder LIMIT OFFSET replaced by the paging of dialect if any
+func (b SelectQuery[B]) pagedBuilder() (preformShare.SelectBuilder, error) {
+	if b.limit == 0 && b.offset == 0 || b.db == nil {
+		return b.selectBuilder, nil
+	}
+	paging, err := b.db.GetDialect().Paging(b.limit, b.offset, b.ordered)
+	if err != nil || paging == nil {
+		return b.selectBuilder, err
+	}
+	return b.selectBuilder.RemoveLimit().RemoveOffset().SuffixExpr(paging), nil
 }
 
 func (b SelectQuery[B]) MustSql() (string, []interface{}) {
@@ -235,11 +266,13 @@ func (b *SelectQuery[B]) Having(pred interface{}, rest ...interface{}) *SelectQu
 }
 
 func (b *SelectQuery[B]) OrderByClause(pred interface{}, args ...interface{}) *SelectQuery[B] {
+	b.ordered = true
 	b.selectBuilder = b.selectBuilder.OrderByClause(pred, args...)
 	return b
 }
 
 func (b *SelectQuery[B]) OrderBy(orderBys ...string) *SelectQuery[B] {
+	b.ordered = b.ordered || len(orderBys) != 0
 	b.selectBuilder = b.selectBuilder.OrderBy(orderBys...)
 	return b
 }
@@ -251,16 +284,19 @@ func (b *SelectQuery[B]) Limit(limit uint64) *SelectQuery[B] {
 }
 
 func (b *SelectQuery[B]) RemoveLimit() *SelectQuery[B] {
+	b.limit = 0
 	b.selectBuilder = b.selectBuilder.RemoveLimit()
 	return b
 }
 
 func (b *SelectQuery[B]) Offset(offset uint64) *SelectQuery[B] {
+	b.offset = offset
 	b.selectBuilder = b.selectBuilder.Offset(offset)
 	return b
 }
 
 func (b *SelectQuery[B]) RemoveOffset() *SelectQuery[B] {
+	b.offset = 0
 	b.selectBuilder = b.selectBuilder.RemoveOffset()
 	return b
 }
@@ -276,16 +312,21 @@ func (b *SelectQuery[B]) SuffixExpr(expr squirrel.Sqlizer) *SelectQuery[B] {
 }
 
 func (b *SelectQuery[B]) AsSubQuery(alias string) squirrel.Sqlizer {
-	return &subQueryCol{query: b.selectBuilder, alias: alias, relatedFactories: b.relatedFactoriesForCache}
+	query, err := b.pagedBuilder()
+	return &subQueryCol{query: query, alias: alias, relatedFactories: b.relatedFactoriesForCache, err: err}
 }
 
 type subQueryCol struct {
 	query            preformShare.SelectBuilder
 	alias            string
 	relatedFactories []preformShare.IQueryFactory
+	err              error
 }
 
 func (s *subQueryCol) ToSql() (string, []interface{}, error) {
+	if s.err != nil {
+		return "", nil, s.err
+	}
 	q, args, err := s.query.ToSql()
 	return fmt.Sprintf("(%s) AS %s", q, s.alias), args, err
 }
@@ -0,0 +1,141 @@
+package preform
+
+import (
+	"fmt"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+	"strings"
+)
+
+type cte struct {
+	alias, code       string
+	cols              []string
+	anchor, recursive squirrel.Sqlizer
+	distinct          bool
+}
+
+// cteClause render the WITH prefix, kept as pointer in the prefix so later ctes join the same clause
+type cteClause struct {
+	ctes []cte
+}
+
+func (c *cteClause) add(alias string, dialect preformShare.IDialect, cols []string, anchor, recursive squirrel.Sqlizer, distinct bool) {
+	for i, col := range cols {
+		cols[i] = dialect.QuoteIdentifier(col)
+	}
+	c.ctes = append(c.ctes, cte{alias: alias, code: dialect.QuoteIdentifier(alias), cols: cols, anchor: anchor, recursive: recursive, distinct: distinct})
+}
+
+func (c *cteClause) has(alias string) bool {
+	if c == nil {
+		return false
+	}
+	for _, t := range c.ctes {
+		if t.alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// ToSql WITH [RECURSIVE] "a"("col", ...) AS (anchor [UNION [ALL] recursive]), ...
+func (c *cteClause) ToSql() (string, []any, error) {
+	var (
+		sqls      = make([]string, len(c.ctes))
+		args      []any
+		recursive bool
+	)
+	for i, t := range c.ctes {
+		q, a, err := t.anchor.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, a...)
+		if t.recursive != nil {
+			recursive = true
+			rq, ra, err := t.recursive.ToSql()
+			if err != nil {
+				return "", nil, err
+			}
+			if t.distinct {
+				q += " UNION " + rq
+			} else {
+				q += " UNION ALL " + rq
+			}
+			args = append(args, ra...)
+		}
+		if len(t.cols) != 0 {
+			sqls[i] = fmt.Sprintf("%s(%s) AS (%s)", t.code, strings.Join(t.cols, ", "), q)
+		} else {
+			sqls[i] = fmt.Sprintf("%s AS (%s)", t.code, q)
+		}
+	}
+	if recursive {
+		return "WITH RECURSIVE " + strings.Join(sqls, ", "), args, nil
+	}
+	return "WITH " + strings.Join(sqls, ", "), args, nil
+}
+
+type iCteSrc interface {
+	cteSrc() (q squirrel.Sqlizer, relatedFactories []preformShare.IQueryFactory, noCache bool, err error)
+}
+
+func (b *SelectQuery[B]) cteSrc() (squirrel.Sqlizer, []preformShare.IQueryFactory, bool, error) {
+	b.fillFactoryColumns()
+	query, err := b.pagedBuilder()
+	return query.PlaceholderFormat(squirrel.Question), b.relatedFactoriesForCache, b.noCache, err
+}
+
+// With add a common table expression, q can be another SelectQuery or any sqlizer
+// if alias is the alias of the query factory, the query selects from the cte instead of the table with the same typed columns
+func (b *SelectQuery[B]) With(alias string, q squirrel.Sqlizer) *SelectQuery[B] {
+	return b.with(alias, q, nil, false)
+}
+
+// WithRecursive add a recursive cte as anchor UNION ALL recursive, recursive refers the cte by alias, e.g. walking a self fk:
+//
+//	child := f.SetAlias("Child").(*FactoryUser)
+//	f.Select().WithRecursive(f.Alias(), f.Select().Where(f.Id.Eq(1)), child.Select().CrossJoin(`"User"`).Where(child.CreatedBy.Eq(f.Id)))
+func (b *SelectQuery[B]) WithRecursive(alias string, anchor, recursive squirrel.Sqlizer) *SelectQuery[B] {
+	return b.with(alias, anchor, recursive, false)
+}
+
+// WithRecursiveDistinct as WithRecursive but anchor UNION recursive, duplicated rows are dropped so walking a cyclic graph ends
+func (b *SelectQuery[B]) WithRecursiveDistinct(alias string, anchor, recursive squirrel.Sqlizer) *SelectQuery[B] {
+	return b.with(alias, anchor, recursive, true)
+}
+
+func (b *SelectQuery[B]) with(alias string, anchor, recursive squirrel.Sqlizer, distinct bool) *SelectQuery[B] {
+	var (
+		cols []string
+	)
+	if b.ctes == nil {
+		b.ctes = &cteClause{}
+		b.selectBuilder = b.selectBuilder.PrefixExpr(b.ctes)
+	}
+	if b.queryFactory != nil && b.queryFactory.Alias() == alias {
+		for _, col := range b.queryFactory.Columns() {
+			cols = append(cols, col.DbName())
+		}
+		b.selectBuilder = b.selectBuilder.From(b.QuoteIdentifier(alias))
+	}
+	b.ctes.add(alias, b.db.GetDialect(), cols, b.withSrc(anchor), b.withSrc(recursive), distinct)
+	return b
+}
+
+func (b *SelectQuery[B]) withSrc(q squirrel.Sqlizer) squirrel.Sqlizer {
+	if q == nil {
+		return nil
+	}
+	if s, ok := q.(iCteSrc); ok {
+		sq, related, noCache, err := s.cteSrc()
+		if err != nil {
+			b.err = err
+		}
+		b.relatedFactoriesForCache = append(b.relatedFactoriesForCache, related...)
+		b.noCache = b.noCache || noCache
+		return sq
+	}
+	b.noCache = true
+	return q
+}
@@ -0,0 +1,76 @@
+package preform
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultEachBatch = 100
+)
+
+var (
+	ErrorStopEach    = errors.New("stop each")
+	ErrorEachEagerTx = errors.New("each with eager loaders can not run in a transaction, the connection is busy with the streaming rows")
+)
+
+// EachBatch rows per eager loading batch of Each / Iterate, default 100
+func (b *SelectQuery[B]) EachBatch(size int) *SelectQuery[B] {
+	b.eachBatch = size
+	return b
+}
+
+// Each stream rows to fn without holding the whole result, return ErrorStopEach from fn to stop early without error
+// with eager loaders, rows are buffered and eager loaded per EachBatch rows before passing to fn, not supported in a transaction
+func (b SelectQuery[B]) Each(fn func(*B) error) error {
+	if b.queryFactory == nil {
+		return fmt.Errorf("not support body scan, check your select columns")
+	}
+	if _, ok := b.db.(*Tx); (ok || txOfDb(b.ctx, b.db.Db()) != nil) && len(b.eagerLoaders) != 0 {
+		return ErrorEachEagerTx
+	}
+	b.fillFactoryColumns()
+	rows, err := b.Query()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if len(b.eagerLoaders) == 0 {
+		err = b.scanner.ScanEach(rows, b.Cols, fn)
+	} else {
+		var (
+			size  = b.eachBatch
+			batch []*B
+		)
+		if size <= 0 {
+			size = defaultEachBatch
+		}
+		batch = make([]*B, 0, size)
+		flush := func() error {
+			if err := b.eagerLoad(batch); err != nil {
+				return err
+			}
+			for _, body := range batch {
+				if err := fn(body); err != nil {
+					return err
+				}
+			}
+			batch = batch[:0]
+			return nil
+		}
+		err = b.scanner.ScanEach(rows, b.Cols, func(body *B) error {
+			batch = append(batch, body)
+			if len(batch) == size {
+				return flush()
+			}
+			return nil
+		})
+		if err == nil && len(batch) != 0 {
+			err = flush()
+		}
+	}
+	if errors.Is(err, ErrorStopEach) {
+		return nil
+	}
+	return err
+}
@@ -18,9 +18,13 @@ func (b *SelectQuery[B]) eagerLoad(ptrs any, fast ...bool) error {
 	var (
 		err       error
 		modelPtrs = ptrs.([]*B)
+		ctx       = b.ctx
 	)
+	if b.primary {
+		ctx = ContextWithPrimary(ctx)
+	}
 	for _, eagerLoader := range b.eagerLoaders {
-		err = eagerLoader.loadEagers(b.ctx, modelPtrs, len(fast) != 0 && fast[0])
+		err = eagerLoader.loadEagers(ctx, modelPtrs, len(fast) != 0 && fast[0])
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package preform
+
+import (
+	"iter"
+)
+
+// Iterate stream rows as iter.Seq2, break the range loop to stop early, a query error is yielded with nil body
+// only built by go 1.23+ while go.mod asks for 1.21, see Each for older toolchains
+func (b SelectQuery[B]) Iterate() iter.Seq2[*B, error] {
+	return func(yield func(*B, error) bool) {
+		err := b.Each(func(body *B) error {
+			if !yield(body, nil) {
+				return ErrorStopEach
+			}
+			return nil
+		})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
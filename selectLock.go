@@ -0,0 +1,74 @@
+package preform
+
+import (
+	"errors"
+	preformShare "github.com/go-preform/preform/share"
+)
+
+var (
+	ErrorLockWithoutTx = errors.New("row lock requires a transaction")
+)
+
+// Tx run the query in the transaction
+func (b *SelectQuery[B]) Tx(tx *Tx) *SelectQuery[B] {
+	b.db = tx
+	return b
+}
+
+// ForUpdate lock selected rows until the transaction ends, rendered by dialect, ignored on sqlite
+func (b *SelectQuery[B]) ForUpdate() *SelectQuery[B] {
+	b.rowLock().ForShare = false
+	return b
+}
+
+// ForShare
+func (b *SelectQuery[B]) ForShare() *SelectQuery[B] {
+	b.rowLock().ForShare = true
+	return b
+}
+
+// SkipLocked skip rows locked by others, implies ForUpdate if no lock is set
+func (b *SelectQuery[B]) SkipLocked() *SelectQuery[B] {
+	b.rowLock().SkipLocked = true
+	return b
+}
+
+// NoWait fail instead of waiting for rows locked by others, implies ForUpdate if no lock is set
+func (b *SelectQuery[B]) NoWait() *SelectQuery[B] {
+	b.rowLock().NoWait = true
+	return b
+}
+
+// Of lock rows of the given factories only, implies ForUpdate if no lock is set
+func (b *SelectQuery[B]) Of(factories ...IQuery) *SelectQuery[B] {
+	lock := b.rowLock()
+	for _, f := range factories {
+		lock.Of = append(lock.Of, f.Alias())
+	}
+	return b
+}
+
+func (b *SelectQuery[B]) rowLock() *preformShare.RowLock {
+	if b.lock == nil {
+		b.lock = &preformShare.RowLock{}
+	}
+	return b.lock
+}
+
+func (b SelectQuery[B]) lockedToSql() (string, []interface{}, error) {
+	if _, ok := b.db.(*Tx); !ok {
+		return "", nil, ErrorLockWithoutTx
+	}
+	suffix, err := b.db.GetDialect().RowLock(*b.lock)
+	if err != nil {
+		return "", nil, err
+	}
+	builder, err := b.pagedBuilder()
+	if err != nil {
+		return "", nil, err
+	}
+	if suffix == nil {
+		return builder.ToSql()
+	}
+	return builder.SuffixExpr(suffix).ToSql()
+}
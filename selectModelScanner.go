@@ -12,6 +12,7 @@ type IModelScanner[B any] interface {
 	ScanAny(rows IRows, cols []ICol, max uint64) ([]any, error)
 	ScanBodies(rows IRows, cols []ICol, max uint64) ([]B, error)
 	ScanBodiesFast(rows IRows, cols []ICol, max uint64) ([]B, error)
+	ScanEach(rows IRows, cols []ICol, fn func(*B) error) error
 	ScanBody(row IRow, cols []ICol) (*B, error)
 	ScanRaw(rows IRows, colValTpl []func() (any, func(*any) any), max uint64) (*RowsWithCols, error)
 	ScanStructs(rows IRows, s any) error
@@ -147,6 +148,58 @@ func (b modelScanner[B]) ScanBodiesFast(rows IRows, cols []ICol, max uint64) ([]
 	return res, err
 }
 
+// ScanEach scan row by row with the type-specific scanners of GetRawPtrScanner, fn receives a copy of the body for each row
+// columns without a typed scanner are scanned into the body directly
+func (b modelScanner[B]) ScanEach(rows IRows, cols []ICol, fn func(*B) error) error {
+	var (
+		body       B = b.bodyCreator()
+		mBody        = any(&body).(iModelBody)
+		scanPtrs   []any
+		sortedPtrs []any
+		raw        []any
+		zeros      []any
+		toScanners []func(*any) any
+		typed      []int
+		direct     bool
+		err        error
+	)
+	scanPtrs = any(&body).(hasFieldValuePtrs).FieldValuePtrs()
+	if l := len(cols); l == 0 {
+		sortedPtrs = scanPtrs
+	} else {
+		sortedPtrs = make([]any, l)
+		raw = make([]any, l)
+		zeros = make([]any, l)
+		toScanners = make([]func(*any) any, l)
+		for i, col := range cols {
+			if zeros[i], toScanners[i], direct = col.rawPtrScanner(); direct {
+				sortedPtrs[i] = scanPtrs[col.GetPos()]
+			} else {
+				typed = append(typed, i)
+			}
+		}
+	}
+	for rows.Next() {
+		for _, i := range typed {
+			raw[i] = zeros[i]
+			sortedPtrs[i] = toScanners[i](&raw[i])
+		}
+		err = rows.Scan(sortedPtrs...)
+		if err != nil {
+			return err
+		}
+		for _, i := range typed {
+			cols[i].setValueToBody(mBody, raw[i])
+		}
+		row := body
+		err = fn(&row)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (b modelScanner[B]) ScanBody(row IRow, cols []ICol) (*B, error) {
 	var (
 		body       B = b.bodyCreator()
@@ -0,0 +1,152 @@
+package preform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-preform/squirrel"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrorInvalidCursor = errors.New("invalid page cursor")
+	ErrorPageNullable  = errors.New("page after can not order by nullable columns, NULL never compares in the cursor condition")
+)
+
+type descCol struct {
+	ICol
+}
+
+// OrderDesc mark a column of PageAfter as descending
+func OrderDesc(col ICol) ICol {
+	return descCol{col}
+}
+
+// PageAfter keyset pagination, fetch rows after cursor ordered by orderCols and return the cursor of the next page
+// orderCols must be unique together, e.g. end with the pk, wrap with OrderDesc for descending
+// orderCols must not be nullable, ErrorPageNullable otherwise, coalesce them in a view or prebuilt query instead
+// empty cursor fetch the first page, empty next cursor means no more rows
+// the next cursor is returned whenever the page is full, so if the rows end exactly at the limit, the last call returns no rows
+//
+//	users, next, err := f.Select().Limit(20).PageAfter(cursor, preform.OrderDesc(f.CreatedAt), preform.OrderDesc(f.Id))
+func (b SelectQuery[B]) PageAfter(cursor string, orderCols ...ICol) ([]B, string, error) {
+	var (
+		cols     = make([]ICol, len(orderCols))
+		desc     = make([]bool, len(orderCols))
+		orderBys = make([]string, len(orderCols))
+	)
+	if len(orderCols) == 0 {
+		return nil, "", fmt.Errorf("page after without order columns")
+	}
+	for i, col := range orderCols {
+		if d, ok := col.(descCol); ok {
+			cols[i], desc[i], orderBys[i] = d.ICol, true, d.ICol.Desc()
+		} else {
+			cols[i], orderBys[i] = col, col.Asc()
+		}
+		if def, ok := cols[i].(iColDbDef); ok {
+			if _, nullable := def.dbDef(); nullable {
+				return nil, "", ErrorPageNullable
+			}
+		}
+	}
+	if cursor != "" {
+		values, err := decodePageCursor(cursor, cols)
+		if err != nil {
+			return nil, "", err
+		}
+		b.Where(keysetCond(cols, desc, values, b.db.GetDialect().RowValueCompare()))
+	}
+	b.OrderBy(orderBys...)
+	bodies, err := b.GetAll()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(bodies) == 0 || b.limit == 0 || uint64(len(bodies)) < b.limit {
+		return bodies, "", nil
+	}
+	next, err := encodePageCursor(any(&bodies[len(bodies)-1]).(iModelBody), cols)
+	if err != nil {
+		return nil, "", err
+	}
+	return bodies, next, nil
+}
+
+// keysetCond (a, b) > (?, ?) if all in the same direction and the dialect can compare row values, otherwise a > ? OR (a = ? AND b < ?) ...
+func keysetCond(cols []ICol, desc []bool, values []any, rowValue bool) ICond {
+	var (
+		mixed bool
+	)
+	for _, d := range desc[1:] {
+		if d != desc[0] {
+			mixed = true
+			break
+		}
+	}
+	if len(cols) == 1 || mixed || !rowValue {
+		var (
+			or  = make(squirrel.Or, len(cols))
+			and squirrel.And
+		)
+		for i, col := range cols {
+			if desc[i] {
+				or[i] = append(append(squirrel.And{}, and...), col.Lt(values[i]))
+			} else {
+				or[i] = append(append(squirrel.And{}, and...), col.Gt(values[i]))
+			}
+			and = append(and, col.Eq(values[i]))
+		}
+		return or
+	}
+	var (
+		codes = make([]string, len(cols))
+		marks = make([]string, len(cols))
+		op    = ">"
+	)
+	for i, col := range cols {
+		codes[i] = col.GetCode()
+		marks[i] = "?"
+	}
+	if desc[0] {
+		op = "<"
+	}
+	return squirrel.Expr(fmt.Sprintf("(%s) %s (%s)", strings.Join(codes, ", "), op, strings.Join(marks, ", ")), values...)
+}
+
+func encodePageCursor(body iModelBody, cols []ICol) (string, error) {
+	var (
+		values = make([]any, len(cols))
+	)
+	for i, col := range cols {
+		values[i] = col.getValueFromBody(body)
+	}
+	bs, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
+
+func decodePageCursor(cursor string, cols []ICol) ([]any, error) {
+	var (
+		raws   []json.RawMessage
+		values = make([]any, len(cols))
+	)
+	bs, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrorInvalidCursor
+	}
+	if err = json.Unmarshal(bs, &raws); err != nil || len(raws) != len(cols) {
+		return nil, ErrorInvalidCursor
+	}
+	for i, col := range cols {
+		v := reflect.New(reflect.TypeOf(col.NewValue()))
+		if err = json.Unmarshal(raws[i], v.Interface()); err != nil {
+			return nil, ErrorInvalidCursor
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return values, nil
+}
@@ -7,8 +7,15 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// Ctx run with the transaction in ctx if any, see WithTx, read from the primary if ctx is by ContextWithPrimary
 func (b *SelectQuery[B]) Ctx(ctx context.Context) *SelectQuery[B] {
 	b.ctx = ctx
+	if isPrimaryCtx(ctx) {
+		b.primary = true
+	}
+	if tx := txOfDb(ctx, b.db.Db()); tx != nil {
+		b.db = tx
+	}
 	return b
 }
 
@@ -23,7 +30,7 @@ func (b *SelectQuery[B]) Prepare() (*SelectQuery[B], error) {
 		return b, err
 	}
 
-	b.prepared, err = b.db.RelatedFactory(b.relatedFactoriesForCache).PreparexContext(b.ctx, q)
+	b.prepared, err = b.runner().RelatedFactory(b.relatedFactoriesForCache).PreparexContext(b.ctx, q)
 	if err != nil {
 		return b, err
 	}
@@ -38,7 +45,7 @@ func (b SelectQuery[B]) Query() (*sql.Rows, error) {
 	if b.prepared != nil {
 		return b.prepared.QueryContext(b.ctx, a...)
 	}
-	return b.db.RelatedFactory(b.relatedFactoriesForCache).QueryContext(b.ctx, q, a...)
+	return b.runner().RelatedFactory(b.relatedFactoriesForCache).QueryContext(b.ctx, q, a...)
 }
 
 func (b SelectQuery[B]) Queryx() (*sqlx.Rows, error) {
@@ -49,7 +56,7 @@ func (b SelectQuery[B]) Queryx() (*sqlx.Rows, error) {
 	if b.prepared != nil {
 		return b.prepared.QueryxContext(b.ctx, a...)
 	}
-	return b.db.QueryxContext(b.ctx, q, a...)
+	return b.runner().QueryxContext(b.ctx, q, a...)
 }
 
 type hasScan interface {
@@ -80,7 +87,7 @@ func (b SelectQuery[B]) QueryRow() *sqlRow {
 	if b.prepared != nil {
 		return &sqlRow{hasScan: b.prepared.QueryRowContext(b.ctx, a...)}
 	}
-	return &sqlRow{hasScan: b.db.RelatedFactory(b.relatedFactoriesForCache).QueryRowContext(b.ctx, q, a...)}
+	return &sqlRow{hasScan: b.runner().RelatedFactory(b.relatedFactoriesForCache).QueryRowContext(b.ctx, q, a...)}
 }
 
 func (b SelectQuery[B]) QueryRowx() *sqlRow {
@@ -91,7 +98,7 @@ func (b SelectQuery[B]) QueryRowx() *sqlRow {
 	if b.prepared != nil {
 		return &sqlRow{hasScan: b.prepared.QueryRowxContext(b.ctx, a...)}
 	}
-	return &sqlRow{hasScan: b.db.QueryRowxContext(b.ctx, q, a...)}
+	return &sqlRow{hasScan: b.runner().QueryRowxContext(b.ctx, q, a...)}
 }
 
 func (b SelectQuery[B]) Count(col ...string) (uint64, error) {
@@ -101,12 +108,13 @@ func (b SelectQuery[B]) Count(col ...string) (uint64, error) {
 	if len(col) == 0 {
 		col = []string{"*"}
 	}
+	b.lock = nil //aggregate can't be locked
 
 	q, a, err := b.RemoveColumns().Column(fmt.Sprintf("COUNT(%s)", col[0])).ToSql()
 	if err != nil {
 		return 0, err
 	}
-	rows, err = b.db.QueryContext(b.ctx, q, a...)
+	rows, err = b.runner().QueryContext(b.ctx, q, a...)
 	if err != nil {
 		return 0, err
 	}
@@ -248,7 +256,7 @@ func (b SelectQuery[B]) QueryBody() (*B, error) {
 	if err != nil {
 		return nil, err
 	}
-	bodyPtr, err = b.scanner.ScanBody(b.db.QueryRowContext(b.ctx, q, a...), b.Cols)
+	bodyPtr, err = b.scanner.ScanBody(b.runner().QueryRowContext(b.ctx, q, a...), b.Cols)
 	if len(b.eagerLoaders) != 0 {
 		if err != nil {
 			return nil, err
@@ -0,0 +1,97 @@
+package preform
+
+import (
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+)
+
+const (
+	setOpUnion     = "UNION"
+	setOpUnionAll  = "UNION ALL"
+	setOpIntersect = "INTERSECT"
+	setOpExcept    = "EXCEPT"
+)
+
+// Union combine with another query of the same body, following Where OrderBy Limit Offset apply to the combined result
+func (b *SelectQuery[B]) Union(q *SelectQuery[B]) *SelectQuery[B] {
+	return b.setOperation(setOpUnion, q)
+}
+
+// UnionAll
+func (b *SelectQuery[B]) UnionAll(q *SelectQuery[B]) *SelectQuery[B] {
+	return b.setOperation(setOpUnionAll, q)
+}
+
+// Intersect
+func (b *SelectQuery[B]) Intersect(q *SelectQuery[B]) *SelectQuery[B] {
+	return b.setOperation(setOpIntersect, q)
+}
+
+// Except
+func (b *SelectQuery[B]) Except(q *SelectQuery[B]) *SelectQuery[B] {
+	return b.setOperation(setOpExcept, q)
+}
+
+// setOperation wrap as SELECT * FROM (b op q) AS alias, columns of both queries must be in the same order, b q with OrderBy Limit Offset are wrapped as sub-selects
+func (b *SelectQuery[B]) setOperation(op string, q *SelectQuery[B]) *SelectQuery[B] {
+	var (
+		alias = "combined"
+		qq    = *q //q stays usable as it is
+	)
+	op, err := b.db.GetDialect().SetOperation(op)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.fillFactoryColumns()
+	qq.fillFactoryColumns()
+	if b.queryFactory != nil {
+		alias = b.queryFactory.Alias()
+	}
+	if qq.limit != 0 || qq.offset != 0 {
+		if err = limitOffsetErr(b.db.GetDialect(), qq.limit, qq.offset); err != nil {
+			b.err = err
+			return b
+		}
+	}
+	qq.selectBuilder = qq.setOperand(alias).PlaceholderFormat(squirrel.Question)
+	qq.limit, qq.offset, qq.ordered = 0, 0, false
+	right, args, err := qq.ToSql()
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if b.limit != 0 || b.offset != 0 {
+		if err = limitOffsetErr(b.db.GetDialect(), b.limit, b.offset); err != nil {
+			b.err = err
+			return b
+		}
+	}
+	b.relatedFactoriesForCache = append(b.relatedFactoriesForCache, qq.relatedFactoriesForCache...)
+	b.noCache = b.noCache || qq.noCache
+	b.selectBuilder = b.db.Db().sqStmtBuilder.SelectFast("*").FromSelectFast(b.setOperand(alias).Suffix(op+" "+right, args...), b.db.GetDialect().QuoteIdentifier(alias))
+	b.limit, b.offset, b.ordered = 0, 0, false
+	return b
+}
+
+// setOperand the select builder, as SELECT * FROM (b) AS alias if ordered or paged, which most drivers don't allow in an operand
+func (b *SelectQuery[B]) setOperand(alias string) preformShare.SelectBuilder {
+	if !b.ordered && b.limit == 0 && b.offset == 0 {
+		return b.selectBuilder
+	}
+	return b.db.Db().sqStmtBuilder.SelectFast("*").FromSelectFast(b.selectBuilder, b.db.GetDialect().QuoteIdentifier(alias))
+}
+
+// fillFactoryColumns select all columns before the select builder is wrapped
+func (b *SelectQuery[B]) fillFactoryColumns() {
+	if len(b.Cols) == 0 && len(b.ColValTpl) == 0 && b.queryFactory != nil {
+		var (
+			cols    = b.queryFactory.Columns()
+			colAnys = make([]any, len(cols))
+		)
+		for i, col := range cols {
+			colAnys[i] = col
+		}
+		b.Columns(colAnys...)
+	}
+}
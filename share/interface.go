@@ -68,6 +68,13 @@ type ICol interface {
 type Aggregator string
 type SqlDialectLastInsertIdMethod uint32
 
+type RowLock struct {
+	ForShare   bool
+	SkipLocked bool
+	NoWait     bool
+	Of         []string //table aliases
+}
+
 type IDialect interface {
 	QuoteIdentifier(string) string
 	GetStructure(db *sql.DB, schemasEmptyIsAll ...string) []*Scheme
@@ -80,6 +87,17 @@ type IDialect interface {
 	ParseCustomTypeScan(src any) (dst []string, err error)
 	ParseCustomTypeValue(name string, src ...any) (dst string, err error)
 	CaseStmtToSql(builder squirrel.CaseBuilder, col ICol) (string, []any, error)
+	Upsert(conflictCols, updateCols []string, autoPk string) (suffix squirrel.Sqlizer, lastIdMethod SqlDialectLastInsertIdMethod, err error)
+	Returning(cols []string) (squirrel.Sqlizer, error)
+	RowLock(lock RowLock) (squirrel.Sqlizer, error)
+	SetOperation(op string) (string, error) //UNION, UNION ALL, INTERSECT or EXCEPT as the driver writes it
+	Savepoint(name string) (savepoint, rollbackTo, release string, err error)
+	IsRetryable(err error) bool                                          //serialization failure or deadlock, the transaction can be run again
+	RestartSavepoint() (name string, err error)                          //savepoint to roll back to and run the transaction again in place, e.g. cockroach_restart
+	ReplicaLag() (query string, err error)                               //query seconds the replica is behind
+	Ddl(ddl Ddl) (string, error)                                         //statement of a migration step
+	Paging(limit, offset uint64, ordered bool) (squirrel.Sqlizer, error) //nil to use LIMIT OFFSET
+	RowValueCompare() bool                                               //(a, b) > (?, ?) is supported
 	//condition
 	Eq(col ICol, v any) squirrel.Sqlizer
 	NotEq(col ICol, v any) squirrel.Sqlizer
@@ -89,6 +107,13 @@ type IDialect interface {
 	Lt(col ICol, v any) squirrel.Sqlizer
 	LtOrEq(col ICol, v any) squirrel.Sqlizer
 	Between(col ICol, v1, v2 any) squirrel.Sqlizer
+	NotBetween(col ICol, v1, v2 any) squirrel.Sqlizer
+	In(col ICol, v any) squirrel.Sqlizer //v as list or sub query
+	NotIn(col ICol, v any) squirrel.Sqlizer
+	IsNull(col ICol) squirrel.Sqlizer
+	IsNotNull(col ICol) squirrel.Sqlizer
+	NotLike(col ICol, v any) squirrel.Sqlizer
+	ILike(col ICol, v any) squirrel.Sqlizer //case insensitive like, LOWER() if not native
 
 	ArrayEq(arrCol any, value any) (query string, args []any, err error)
 	ArrayAny(arrCol any, value any) (query string, args []any, err error)
@@ -96,6 +121,13 @@ type IDialect interface {
 	ArrayConcat(arrColA any, arrColB any) (query string, args []any, err error)
 	ArrayContains(arrColA any, arrColB any) (query string, args []any, err error)
 	ArrayContainsBy(arrColA any, arrColB any) (query string, args []any, err error)
+
+	JsonExtract(col ICol, path []string, kind reflect.Kind) (query string, err error) //value at path, as text / number / bool by kind, json for others
+	JsonContains(col ICol, value any) (query string, args []any, err error)           //value marshaled to json, json.RawMessage as it is
+	JsonHasKey(col ICol, path []string) (query string, args []any, err error)
+
+	FullTextMatch(col ICol, text string) (query string, args []any, err error)
+	FullTextRank(col ICol, text string) (query string, args []any, err error) //higher is more relevant
 }
 
 type ISqlizerWithDialect interface {
@@ -21,6 +21,7 @@ type (
 	CustomTypeAttr struct {
 		Name      string
 		Type      string
+		DbType    string
 		NotNull   bool
 		IsScanner bool
 	}
@@ -65,4 +66,31 @@ type (
 		ReverseName  string
 		AssociatedFk *ForeignKey
 	}
+	// Ddl a schema change rendered by IDialect.Ddl for migrations
+	Ddl struct {
+		Kind       DdlKind
+		Table      *Table
+		Column     *Column
+		FromColumn *Column //column before DdlAlterColumn
+		ForeignKey *ForeignKey
+		Scheme     string //of enum or custom type
+		Name       string
+		Values     []string //of enum
+		CustomType *CustomType
+	}
+	DdlKind uint8
+)
+
+const (
+	DdlCreateTable DdlKind = iota
+	DdlDropTable
+	DdlAddColumn
+	DdlDropColumn
+	DdlAlterColumn
+	DdlAddForeignKey
+	DdlDropForeignKey
+	DdlCreateEnum
+	DdlDropEnum
+	DdlCreateCustomType
+	DdlDropCustomType
 )
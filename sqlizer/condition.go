@@ -25,6 +25,44 @@ func (b Between) ToSql() (query string, args []any, err error) {
 	return
 }
 
+type NotBetween map[string][2]any
+
+func (b NotBetween) ToSql() (query string, args []any, err error) {
+	var (
+		l       = len(b)
+		i       = 0
+		queries = make([]string, l)
+	)
+	args = make([]any, l*2)
+	for k, v := range b {
+		queries[i] = fmt.Sprintf("%s NOT BETWEEN ? AND ?", k)
+		args[i*2], args[i*2+1] = v[0], v[1]
+		i++
+	}
+	query = "(" + strings.Join(queries, " AND ") + ")"
+	return
+}
+
+// In list or sub query, the sub query is left as arg to be nested by NestCondSql
+type In struct {
+	Col   string
+	Value any
+	Not   bool
+}
+
+func (i In) ToSql() (query string, args []any, err error) {
+	if _, ok := i.Value.(squirrel.Sqlizer); ok {
+		if i.Not {
+			return fmt.Sprintf("%s NOT IN ?", i.Col), []any{i.Value}, nil
+		}
+		return fmt.Sprintf("%s IN ?", i.Col), []any{i.Value}, nil
+	}
+	if i.Not {
+		return squirrel.NotEq{i.Col: i.Value}.ToSql()
+	}
+	return squirrel.Eq{i.Col: i.Value}.ToSql()
+}
+
 type If struct {
 	Cond any
 	Then any
@@ -0,0 +1,21 @@
+package preformSqlizer
+
+import (
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+)
+
+type FullTextMatch struct {
+	Col  preformShare.ICol
+	Text string
+}
+
+func (h FullTextMatch) WithDialect(d preformShare.IDialect) squirrel.Sqlizer {
+	return preformShare.SqlizerWithDialectWrapper{Sqlizer: func() (string, []any, error) {
+		return d.FullTextMatch(h.Col, h.Text)
+	}}
+}
+
+func (h FullTextMatch) ToSql(dialect preformShare.IDialect) (query string, args []any, err error) {
+	return dialect.FullTextMatch(h.Col, h.Text)
+}
@@ -0,0 +1,36 @@
+package preformSqlizer
+
+import (
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/squirrel"
+)
+
+type JsonContains struct {
+	Col   preformShare.ICol
+	Value any
+}
+
+func (h JsonContains) WithDialect(d preformShare.IDialect) squirrel.Sqlizer {
+	return preformShare.SqlizerWithDialectWrapper{Sqlizer: func() (string, []any, error) {
+		return d.JsonContains(h.Col, h.Value)
+	}}
+}
+
+func (h JsonContains) ToSql(dialect preformShare.IDialect) (query string, args []any, err error) {
+	return dialect.JsonContains(h.Col, h.Value)
+}
+
+type JsonHasKey struct {
+	Col  preformShare.ICol
+	Path []string
+}
+
+func (h JsonHasKey) WithDialect(d preformShare.IDialect) squirrel.Sqlizer {
+	return preformShare.SqlizerWithDialectWrapper{Sqlizer: func() (string, []any, error) {
+		return d.JsonHasKey(h.Col, h.Path)
+	}}
+}
+
+func (h JsonHasKey) ToSql(dialect preformShare.IDialect) (query string, args []any, err error) {
+	return dialect.JsonHasKey(h.Col, h.Path)
+}
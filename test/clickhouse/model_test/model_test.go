@@ -172,6 +172,39 @@ func TestUserSelect(t *testing.T) {
 
 }
 
+func TestUserJson(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		q, _, err := f.Select(f.Id,
+			f.Name.JsonPath("address", "0", "city"),
+			preform.JsonPathOf[int64](f.Name, "Age"),
+			preform.JsonPathOf[uint8](f.Name, "Level"),
+			preform.JsonPathOf[float64](f.Name, "Score"),
+			preform.JsonPathOf[bool](f.Name, "Vip"),
+			preform.JsonPathOf[map[string]any](f.Name, "address"),
+		).Where(f.Name.JsonHasKey("phone")).ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, "JSONExtractString(`User`.`name`, 'address', 1, 'city') AS `city`")
+		assert.Contains(t, q, "JSONExtractInt(`User`.`name`, 'Age') AS `Age`")
+		assert.Contains(t, q, "JSONExtractUInt(`User`.`name`, 'Level') AS `Level`")
+		assert.Contains(t, q, "JSONExtractFloat(`User`.`name`, 'Score') AS `Score`")
+		assert.Contains(t, q, "JSONExtractBool(`User`.`name`, 'Vip') AS `Vip`")
+		assert.Contains(t, q, "JSONExtractRaw(`User`.`name`, 'address') AS `address`")
+		assert.Contains(t, q, "WHERE JSONHas(`User`.`name`, 'phone')")
+	})
+}
+
+func TestUserFullText(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		rank := f.Name.MatchRank("red shoes").SetAlias("Rank")
+		q, args, err := f.Select(f.Id, rank).Where(f.Name.Match("red shoes")).OrderBy(rank.Desc()).ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, "ngramSearchCaseInsensitive(`User`.`name`, $1) AS `Rank`")
+		assert.Contains(t, q, "WHERE hasTokenCaseInsensitive(`User`.`name`, $2) AND hasTokenCaseInsensitive(`User`.`name`, $3)")
+		assert.Contains(t, q, "ORDER BY `Rank` DESC")
+		assert.Equal(t, []any{"red shoes", "red", "shoes"}, args)
+	})
+}
+
 func TestUserSelectRelation(t *testing.T) {
 	user, err := mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs.OrderBy((any(mainModel.PreformTestA.User.UserLogs.TargetFactory())).(*mainModel.FactoryUserLog).RelatedLogId.Desc())).Where(mainModel.PreformTestA.User.Id.Eq(1)).GetOne()
 	assert.Nil(t, err)
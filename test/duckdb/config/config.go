@@ -0,0 +1,19 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/marcboeker/go-duckdb"
+)
+
+var (
+	DuckdbFile = "preform_test.duckdb"
+)
+
+func InitDb(wd string) *sql.DB {
+	db, err := sql.Open("duckdb", fmt.Sprintf("%s/%s", wd, DuckdbFile))
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/go-preform/preform/preformBuilder"
+	"github.com/go-preform/preform/test/duckdb/config"
+	"os"
+	"os/exec"
+)
+
+func main() {
+	wd, _ := os.Getwd()
+	err := os.Remove(fmt.Sprintf("%s/%s", wd, config.DuckdbFile))
+	fmt.Println(err)
+	conn := config.InitDb(wd)
+	prepareSchema(conn)
+	preformBuilder.BuildModel(conn, "mainModel", "mainModel", "preform_test_a", "preform_test_b")
+	_ = conn.Close() //duckdb locks the file per process
+
+	fmt.Println("go test----------------------------")
+	p := fmt.Sprintf("%s/model_test", wd)
+	cmd := exec.Command("go", "test", p, "-root="+wd)
+	cmd.Dir = wd
+	out, err := cmd.CombinedOutput()
+	//if err != nil {
+	//	panic(err)
+	//}
+	fmt.Println(string(out), err)
+}
+
+func prepareSchema(conn *sql.DB) {
+	_, err := conn.Exec(`
+CREATE SCHEMA preform_test_a;
+CREATE SCHEMA preform_test_b;
+CREATE SEQUENCE preform_test_a.user_id_seq;
+CREATE SEQUENCE preform_test_a.user_log_id_seq;
+CREATE SEQUENCE preform_test_a.foo_id_seq;
+
+CREATE TABLE preform_test_a."user" (
+	id INTEGER DEFAULT nextval('preform_test_a.user_id_seq') NOT NULL,
+	"name" VARCHAR NOT NULL,
+	created_by INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	logined_at TIMESTAMP NULL,
+	PRIMARY KEY (id)
+);
+COMMENT ON COLUMN preform_test_a."user".created_by IS 'fk:user.id';
+
+CREATE TABLE preform_test_a.user_manager (
+	user_id INTEGER NOT NULL,
+	manager_id INTEGER NOT NULL,
+	PRIMARY KEY (user_id, manager_id),
+	FOREIGN KEY (user_id) REFERENCES preform_test_a."user" (id),
+	FOREIGN KEY (manager_id) REFERENCES preform_test_a."user" (id)
+);
+
+CREATE TABLE preform_test_a.user_log (
+	id BIGINT DEFAULT nextval('preform_test_a.user_log_id_seq') NOT NULL,
+	user_id INTEGER NOT NULL,
+	related_log_id BIGINT NULL,
+	"type" VARCHAR NOT NULL CHECK ("type" IN ('Register', 'login')),
+	tags VARCHAR[] NOT NULL,
+	PRIMARY KEY (id),
+	FOREIGN KEY (user_id) REFERENCES preform_test_a."user" (id)
+);
+COMMENT ON COLUMN preform_test_a.user_log.related_log_id IS 'fk:user_log.id';
+
+CREATE TABLE preform_test_a.foo (
+	id INTEGER DEFAULT nextval('preform_test_a.foo_id_seq') NOT NULL,
+	fk1 INTEGER NOT NULL,
+	fk2 INTEGER NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (fk1, fk2)
+);
+
+CREATE TABLE preform_test_b.bar (
+	id1 INTEGER NOT NULL,
+	id2 INTEGER NOT NULL,
+	PRIMARY KEY (id1, id2)
+);
+COMMENT ON COLUMN preform_test_b.bar.id1 IS 'fk:preform_test_a.foo.id';
+`)
+	if err != nil {
+		panic(err)
+	}
+}
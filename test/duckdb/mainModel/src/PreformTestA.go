@@ -0,0 +1,88 @@
+package main
+import (
+	preformShare "github.com/go-preform/preform/share"
+	"time"
+	"github.com/go-preform/preform/types"
+	"github.com/go-preform/preform/preformBuilder"
+)
+
+
+
+type PreformTestA_foo struct {
+	preformBuilder.FactoryBuilder[*PreformTestA_foo]
+	Id	preformBuilder.PrimaryKeyDef[int32] `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true" defaultValue:"nextval('preform_test_a.foo_id_seq')"`
+	Fk1	preformBuilder.ColumnDef[int32] `db:"fk1" json:"Fk1" dataType:"INTEGER"`
+	Fk2	preformBuilder.ColumnDef[int32] `db:"fk2" json:"Fk2" dataType:"INTEGER"`
+}
+
+type PreformTestA_user struct {
+	preformBuilder.FactoryBuilder[*PreformTestA_user]
+	Id	preformBuilder.PrimaryKeyDef[int32] `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true" defaultValue:"nextval('preform_test_a.user_id_seq')"`
+	Name	preformBuilder.ColumnDef[string] `db:"name" json:"Name" dataType:"VARCHAR"`
+	CreatedBy	preformBuilder.ForeignKeyDef[int32] `db:"created_by" json:"CreatedBy" dataType:"INTEGER" comment:"fk:user.id"`
+	CreatedAt	preformBuilder.ColumnDef[time.Time] `db:"created_at" json:"CreatedAt" dataType:"TIMESTAMP"`
+	LoginedAt	preformBuilder.ColumnDef[preformTypes.Null[time.Time]] `db:"logined_at" json:"LoginedAt" dataType:"TIMESTAMP"`
+}
+
+type PreformTestA_userLog struct {
+	preformBuilder.FactoryBuilder[*PreformTestA_userLog]
+	Id	preformBuilder.PrimaryKeyDef[int64] `db:"id" json:"Id" dataType:"BIGINT" autoKey:"true" defaultValue:"nextval('preform_test_a.user_log_id_seq')"`
+	UserId	preformBuilder.ForeignKeyDef[int32] `db:"user_id" json:"UserId" dataType:"INTEGER"`
+	RelatedLogId	preformBuilder.ForeignKeyDef[preformTypes.Null[int64]] `db:"related_log_id" json:"RelatedLogId" dataType:"BIGINT" comment:"fk:user_log.id"`
+	Type	preformBuilder.ColumnDef[string] `db:"type" json:"Type" dataType:"VARCHAR"`
+	Tags	preformBuilder.ColumnDef[preformTypes.Array[string]] `db:"tags" json:"Tags" dataType:"VARCHAR[]"`
+}
+
+type PreformTestA_userManager struct {
+	preformBuilder.FactoryBuilder[*PreformTestA_userManager]
+	UserId	preformBuilder.PrimaryKeyDef[int32] `db:"user_id" json:"UserId" dataType:"INTEGER"`
+	ManagerId	preformBuilder.PrimaryKeyDef[int32] `db:"manager_id" json:"ManagerId" dataType:"INTEGER"`
+}
+
+type PreformTestASchema struct {
+	name string
+	foo *PreformTestA_foo
+	user *PreformTestA_user
+	userLog *PreformTestA_userLog
+	userManager *PreformTestA_userManager
+}
+
+var (
+	PreformTestA = PreformTestASchema{name: "PreformTestA"}
+)
+
+func initPreformTestA() (string, []preformShare.IFactoryBuilder, *PreformTestASchema, map[string][]string, map[string]*preformShare.CustomType) {
+
+	//implement IFactoryBuilderWithSetup in a new file if you need to customize the factory
+	
+	PreformTestA.foo = preformBuilder.InitFactoryBuilder(PreformTestA.name, func(d *PreformTestA_foo) {
+		d.SetTableName("foo")
+	})
+	
+	PreformTestA.user = preformBuilder.InitFactoryBuilder(PreformTestA.name, func(d *PreformTestA_user) {
+		d.SetTableName("user")
+		d.Id.SetAssociatedKey(PreformTestA.userManager.UserId, preformBuilder.FkMiddleTable(PreformTestA.userManager, []preformShare.IColDef{PreformTestA.user.Id}, []preformShare.IColDef{PreformTestA.userManager.UserId}, []preformShare.IColDef{PreformTestA.user.Id}, []preformShare.IColDef{PreformTestA.userManager.ManagerId}))
+		d.CreatedBy.SetAssociatedKey(PreformTestA.user.Id, preformBuilder.FkName("comment_created_by_0"))
+	})
+	
+	PreformTestA.userLog = preformBuilder.InitFactoryBuilder(PreformTestA.name, func(d *PreformTestA_userLog) {
+		d.SetTableName("user_log")
+		d.UserId.SetAssociatedKey(PreformTestA.user.Id, preformBuilder.FkName("user_log_user_id_id_fkey"))
+		d.RelatedLogId.SetAssociatedKey(PreformTestA.userLog.Id, preformBuilder.FkName("comment_related_log_id_0"))
+	})
+	
+	PreformTestA.userManager = preformBuilder.InitFactoryBuilder(PreformTestA.name, func(d *PreformTestA_userManager) {
+		d.SetTableName("user_manager")
+	})
+
+	return "preform_test_a",
+		[]preformShare.IFactoryBuilder{
+			PreformTestA.foo,
+			PreformTestA.user,
+			PreformTestA.userLog,
+			PreformTestA.userManager,
+		},
+		&PreformTestA,
+		map[string][]string{},
+        map[string]*preformShare.CustomType{}
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+package types
+
+type UserDetail struct {
+	Age uint32
+}
+
+type UserConfig struct {
+	EnableCookie bool
+}
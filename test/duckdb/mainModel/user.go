@@ -0,0 +1,167 @@
+package mainModel
+
+import (
+	"github.com/go-preform/preform"
+	"time"
+	"github.com/go-preform/preform/types"
+)
+
+var userInit = preform.InitFactory[*FactoryUser, UserBody](func(s *PreformTestASchema) {
+	preform.SetColumn(s.User.Id.Column).AutoIncrement()
+	s.User.UserByUserManagerManagerId.InitMtRelation(PreformTestA.UserManager, []preform.IColFromFactory{s.User.Id}, []preform.IColFromFactory{PreformTestA.UserManager.UserId}, []preform.IColFromFactory{PreformTestA.User.Id}, []preform.IColFromFactory{PreformTestA.UserManager.ManagerId})
+	s.User.UserByUserManagerUserId.InitMtRelation(PreformTestA.UserManager, []preform.IColFromFactory{PreformTestA.User.Id}, []preform.IColFromFactory{PreformTestA.UserManager.ManagerId}, []preform.IColFromFactory{s.User.Id}, []preform.IColFromFactory{PreformTestA.UserManager.UserId})
+	s.User.UserByCommentCreatedBy0.InitRelation(s.User.CreatedBy, s.User.Id)
+	s.User.UsersByCommentCreatedBy0.InitRelation(s.User.Id, s.User.CreatedBy)
+	s.User.SetTableName("user")
+})
+
+type FactoryUser struct {
+	preform.Factory[*FactoryUser, UserBody]
+	Id *preform.PrimaryKey[int32] `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true" defaultValue:"nextval('preform_test_a.user_id_seq')"`
+	Name *preform.Column[string] `db:"name" json:"Name" dataType:"VARCHAR"`
+	CreatedBy *preform.ForeignKey[int32] `db:"created_by" json:"CreatedBy" dataType:"INTEGER" comment:"fk:user.id"`
+	CreatedAt *preform.Column[time.Time] `db:"created_at" json:"CreatedAt" dataType:"TIMESTAMP"`
+	LoginedAt *preform.Column[preformTypes.Null[time.Time]] `db:"logined_at" json:"LoginedAt" dataType:"TIMESTAMP"`
+	
+	//relations
+	UserByUserManagerManagerId *preform.MiddleTable[*UserBody, *FactoryUser, UserBody, UserManagerBody]
+	UserByCommentCreatedBy0 *preform.ToOne[*UserBody, *FactoryUser, UserBody]
+	UserByUserManagerUserId *preform.MiddleTable[*UserBody, *FactoryUser, UserBody, UserManagerBody]
+	UsersByCommentCreatedBy0 *preform.ToMany[*UserBody, *FactoryUser, UserBody]
+	UserLogs *preform.ToMany[*UserBody, *FactoryUserLog, UserLogBody]
+	UserLogsByUserLogUserFkRegister *preform.ToMany[*UserBody, *FactoryUserLog, UserLogBody]
+}
+
+func (f FactoryUser) CloneInstance(factory preform.IFactory) preform.IFactory {
+	var (
+		ff = f
+		cols = factory.Columns()
+	)
+	ff.Factory = *factory.(*preform.Factory[*FactoryUser, UserBody])
+	ff.Factory.Definition = &ff
+	ff.Id = cols[0].(*preform.PrimaryKey[int32] )
+	ff.Name = cols[1].(*preform.Column[string] )
+	ff.CreatedBy = cols[2].(*preform.ForeignKey[int32] )
+	ff.CreatedAt = cols[3].(*preform.Column[time.Time] )
+	ff.LoginedAt = cols[4].(*preform.Column[preformTypes.Null[time.Time]] )
+	return ff.Factory.Definition
+}
+
+
+type UserBody struct {
+	preform.Body[UserBody,*FactoryUser]
+	Id int32 `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true" defaultValue:"nextval('preform_test_a.user_id_seq')"`
+	Name string `db:"name" json:"Name" dataType:"VARCHAR"`
+	CreatedBy int32 `db:"created_by" json:"CreatedBy" dataType:"INTEGER" comment:"fk:user.id"`
+	CreatedAt time.Time `db:"created_at" json:"CreatedAt" dataType:"TIMESTAMP"`
+	LoginedAt preformTypes.Null[time.Time] `db:"logined_at" json:"LoginedAt" dataType:"TIMESTAMP"`
+	
+	UserByUserManagerManagerId []*UserBody
+	UserByCommentCreatedBy0 *UserBody
+	UserByUserManagerUserId []*UserBody
+	UsersByCommentCreatedBy0 []*UserBody
+	UserLogs []*UserLogBody
+	UserLogsByUserLogUserFkRegister []*UserLogBody
+}
+
+func (m UserBody) Factory() *FactoryUser { return m.Body.Factory(PreformTestA.User) }
+
+func (m *UserBody) Insert(cfg ... preform.EditConfig) error { return PreformTestA.User.Insert(m, cfg...) }
+
+func (m *UserBody) Update(cfg ... preform.UpdateConfig) (affected int64, err error) { return PreformTestA.User.UpdateByPk(m, cfg...) }
+
+func (m *UserBody) Delete(cfg ... preform.EditConfig) (affected int64, err error) { return PreformTestA.User.DeleteByPk(m, cfg...) }
+
+func (m UserBody) FieldValueImmutablePtrs() []any { return []any{&m.Id, &m.Name, &m.CreatedBy, &m.CreatedAt, &m.LoginedAt} }
+
+func (m *UserBody) FieldValuePtr(pos int) any { 
+	switch pos {
+		case 0: return &m.Id
+		case 1: return &m.Name
+		case 2: return &m.CreatedBy
+		case 3: return &m.CreatedAt
+		case 4: return &m.LoginedAt
+	}
+	return nil
+}
+
+func (m *UserBody) FieldValuePtrs() []any { 
+	return []any{&m.Id, &m.Name, &m.CreatedBy, &m.CreatedAt, &m.LoginedAt}
+}
+
+func (m *UserBody) RelatedValuePtrs() []any { return []any{&m.UserByUserManagerManagerId, &m.UserByCommentCreatedBy0, &m.UserByUserManagerUserId, &m.UsersByCommentCreatedBy0, &m.UserLogs, &m.UserLogsByUserLogUserFkRegister} }
+
+
+func (m *UserBody) RelatedByPos(pos uint32) any {
+	switch pos {
+			case 0: return &m.UserByUserManagerManagerId
+			case 1: return &m.UserByCommentCreatedBy0
+			case 2: return &m.UserByUserManagerUserId
+			case 3: return &m.UsersByCommentCreatedBy0
+			case 4: return &m.UserLogs
+			case 5: return &m.UserLogsByUserLogUserFkRegister
+	}
+	return nil
+}
+
+
+func (m *UserBody) LoadUserByUserManagerManagerId(noCache ...bool) ([]*UserBody, error) {
+	if len(m.UserByUserManagerManagerId) == 0 || len(noCache) != 0 && noCache[0] {
+		err := PreformTestA.User.UserByUserManagerManagerId.Load(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.UserByUserManagerManagerId, nil
+}
+
+func (m *UserBody) LoadUserByCommentCreatedBy0(noCache ...bool) (*UserBody, error) {
+	if m.UserByCommentCreatedBy0 == nil || len(noCache) != 0 && noCache[0] {
+		err := PreformTestA.User.UserByCommentCreatedBy0.Load(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.UserByCommentCreatedBy0, nil
+}
+
+func (m *UserBody) LoadUserByUserManagerUserId(noCache ...bool) ([]*UserBody, error) {
+	if len(m.UserByUserManagerUserId) == 0 || len(noCache) != 0 && noCache[0] {
+		err := PreformTestA.User.UserByUserManagerUserId.Load(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.UserByUserManagerUserId, nil
+}
+
+func (m *UserBody) LoadUsersByCommentCreatedBy0(noCache ...bool) ([]*UserBody, error) {
+	if len(m.UsersByCommentCreatedBy0) == 0 || len(noCache) != 0 && noCache[0] {
+		err := PreformTestA.User.UsersByCommentCreatedBy0.Load(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.UsersByCommentCreatedBy0, nil
+}
+
+func (m *UserBody) LoadUserLogs(noCache ...bool) ([]*UserLogBody, error) {
+	if len(m.UserLogs) == 0 || len(noCache) != 0 && noCache[0] {
+		err := PreformTestA.User.UserLogs.Load(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.UserLogs, nil
+}
+
+func (m *UserBody) LoadUserLogsByUserLogUserFkRegister(noCache ...bool) ([]*UserLogBody, error) {
+	if len(m.UserLogsByUserLogUserFkRegister) == 0 || len(noCache) != 0 && noCache[0] {
+		err := PreformTestA.User.UserLogsByUserLogUserFkRegister.Load(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.UserLogsByUserLogUserFkRegister, nil
+}
+
@@ -0,0 +1,376 @@
+package model_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/dialect"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/preform/test/duckdb/config"
+	"github.com/go-preform/preform/test/duckdb/mainModel"
+	preformTestUtil "github.com/go-preform/preform/testUtil"
+	preformTypes "github.com/go-preform/preform/types"
+	"github.com/go-preform/squirrel"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+var (
+	conn *sql.DB
+	wd   string
+)
+
+func TestMain(m *testing.M) {
+	wdd := flag.String("root", "", "")
+	flag.Parse()
+	wd, _ = os.Getwd()
+	if wdd != nil && *wdd != "" {
+		wd = *wdd
+	}
+	conn = config.InitDb(wd)
+	os.Exit(m.Run())
+}
+
+func TestInit(t *testing.T) {
+	mainModel.Init(conn)
+	for _, table := range []string{"preform_test_b.bar", "preform_test_a.user_manager", "preform_test_a.user_log", "preform_test_a.foo", `preform_test_a."user"`} {
+		_, err := mainModel.PreformTestA.Exec("DELETE FROM " + table)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, `"user"`, mainModel.PreformTestA.GetDialect().QuoteIdentifier("user"))
+}
+
+func TestSelect(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	q, args, err := f.Select().Where(f.Id.Eq(1)).Limit(10).Offset(20).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `"preform_test_a"."user"`)
+	assert.Contains(t, q, `"id" = $1`)
+	assert.Contains(t, q, "LIMIT 10 OFFSET 20")
+	assert.Len(t, args, 1)
+}
+
+func TestCondition(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	q, args, err := f.Select().Where(f.Name.ILike("TEST%")).Where(f.LoginedAt.IsNull()).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `"name" ILIKE $1`)
+	assert.Contains(t, q, `"logined_at" IS NULL`)
+	assert.Equal(t, []any{"TEST%"}, args)
+
+	q, args, err = f.Select().Where(f.Id.NotIn(f.Select(f.Id).Where(f.CreatedBy.Eq(1)))).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `"id" NOT IN (SELECT `)
+	assert.Len(t, args, 1)
+}
+
+func TestRelationExists(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	q, args, err := f.Select().Where(f.UserLogs.Exists(mainModel.PreformTestA.UserLog.Type.Eq("Login"))).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `EXISTS (SELECT 1 FROM "preform_test_a"."user_log" AS "UserLogs" WHERE ("User"."id" IN ("UserLogs"."user_id") AND "UserLogs"."type" = $1))`)
+	assert.Equal(t, []any{"Login"}, args)
+
+	q, _, err = f.Select().Where(f.UserByUserManagerManagerId.NotExists()).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `NOT EXISTS (SELECT 1 FROM "preform_test_a"."user_manager" AS "UserManager" WHERE ("User"."id" = "UserManager"."user_id"))`)
+
+	q, args, err = f.Select().Where(f.UserByUserManagerManagerId.Exists(f.Name.Eq("boss"))).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `JOIN "preform_test_a"."user" AS "UserByUserManagerManagerId" ON "UserManager"."manager_id" = "UserByUserManagerManagerId"."id" WHERE ("User"."id" = "UserManager"."user_id" AND "UserByUserManagerManagerId"."name" = $1))`)
+	assert.Equal(t, []any{"boss"}, args)
+
+	q, _, err = f.Update().Set(f.Name, "x").Where(f.UserLogs.Exists()).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `WHERE EXISTS (SELECT 1 FROM "preform_test_a"."user_log" AS "UserLogs" WHERE ("preform_test_a"."user"."id" IN ("UserLogs"."user_id")))`)
+
+	q, args, err = f.Update().Set(f.Name, "x").Where(f.UserLogs.Exists(squirrel.Or{mainModel.PreformTestA.UserLog.Type.Eq("login"), f.Name.Eq("boss")})).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `AND ("UserLogs"."type" = $2 OR "preform_test_a"."user"."name" = $3)))`)
+	assert.Equal(t, []any{"x", "login", "boss"}, args)
+
+	q, _, err = f.Delete().Where(f.UserLogs.NotExists()).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `WHERE NOT EXISTS (SELECT 1 FROM "preform_test_a"."user_log" AS "UserLogs" WHERE ("preform_test_a"."user"."id" IN ("UserLogs"."user_id")))`)
+}
+
+func TestArray(t *testing.T) {
+	f := mainModel.PreformTestA.UserLog
+	q, args, err := f.Select().Where(f.Tags.Any("vip")).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `list_contains(`)
+	assert.Contains(t, q, `"tags", $1)`)
+	assert.Equal(t, []any{"vip"}, args)
+
+	q, args, err = f.Select().Where(f.Tags.Contains(preformTypes.Array[string]{"vip", "new"})).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `"tags", ([$1, $2]))`)
+	assert.Equal(t, []any{"vip", "new"}, args)
+
+	q, args, err = f.Select().Where(f.Tags.ContainsBy([]string{"vip", "new"})).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `list_has_all(([$1, $2]), `)
+	assert.Equal(t, []any{"vip", "new"}, args)
+
+	q, _, err = f.Select().Where(f.Tags.HasAny([]string{"vip"})).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `list_has_any(`)
+}
+
+var (
+	firstUserId int32
+)
+
+func TestUserInsert(t *testing.T) {
+	user := mainModel.UserBody{
+		Name:      "test1",
+		CreatedAt: time.Now(),
+		UserLogs: []*mainModel.UserLogBody{
+			{Type: "Register", Tags: preformTypes.Array[string]{"new", "vip"}},
+			{Type: "login", Tags: preformTypes.Array[string]{}},
+		},
+	}
+	err := user.Insert(preform.EditConfig{Cascading: true})
+	assert.Nil(t, err)
+	assert.NotZero(t, user.Id)
+	firstUserId = user.Id
+	assert.Equal(t, user.Id, user.UserLogs[1].UserId)
+
+	logs := []mainModel.UserLogBody{
+		{UserId: user.Id, Type: "login", Tags: preformTypes.Array[string]{"batch"}},
+		{UserId: user.Id, Type: "login", Tags: preformTypes.Array[string]{"batch", "vip"}},
+	}
+	err = mainModel.PreformTestA.UserLog.InsertBatch(logs)
+	assert.Nil(t, err)
+
+	users := []*mainModel.UserBody{
+		{Name: "test2", CreatedBy: user.Id, CreatedAt: time.Now()},
+		{Name: "test3", CreatedBy: user.Id, CreatedAt: time.Now()},
+	}
+	err = mainModel.PreformTestA.User.Insert(users)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id+1, users[0].Id)
+	assert.Equal(t, user.Id+2, users[1].Id)
+
+	err = mainModel.PreformTestA.UserManager.InsertBatch([]mainModel.UserManagerBody{{UserId: users[0].Id, ManagerId: user.Id}, {UserId: users[1].Id, ManagerId: user.Id}})
+	assert.Nil(t, err)
+
+	foos := []*mainModel.FooBody{{Fk1: 1, Fk2: 2}, {Fk1: 3, Fk2: 4}}
+	err = mainModel.PreformTestA.Foo.Insert(foos)
+	assert.Nil(t, err)
+	err = mainModel.PreformTestB.Bar.InsertBatch([]mainModel.BarBody{{Id1: foos[0].Id, Id2: 1}, {Id1: foos[1].Id, Id2: 1}})
+	assert.Nil(t, err)
+}
+
+func TestUserSelect(t *testing.T) {
+	user, err := mainModel.PreformTestA.User.GetOne(firstUserId)
+	assert.Nil(t, err)
+	assert.Equal(t, "test1", user.Name)
+	assert.False(t, user.LoginedAt.Valid)
+
+	users, err := mainModel.PreformTestA.User.Select().Where(mainModel.PreformTestA.User.Id.Gt(firstUserId)).OrderBy(mainModel.PreformTestA.User.Id.Asc()).GetAll()
+	assert.Nil(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, "test2", users[0].Name)
+		assert.Equal(t, firstUserId, users[1].CreatedBy)
+	}
+
+	logs, err := mainModel.PreformTestA.UserLog.Select().Where(mainModel.PreformTestA.UserLog.UserId.Eq(firstUserId)).OrderBy(mainModel.PreformTestA.UserLog.Id.Asc()).GetAll()
+	assert.Nil(t, err)
+	if assert.Len(t, logs, 4) {
+		assert.Equal(t, preformTypes.Array[string]{"new", "vip"}, logs[0].Tags)
+		assert.Len(t, logs[1].Tags, 0)
+		assert.Equal(t, preformTypes.Array[string]{"batch", "vip"}, logs[3].Tags)
+	}
+
+	count, err := mainModel.PreformTestA.User.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), count)
+}
+
+func TestUserSelectRelation(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	user, err := f.Select().Eager(f.UserLogs).Eager(f.UsersByCommentCreatedBy0).Eager(f.UserByUserManagerUserId).GetOne(firstUserId)
+	assert.Nil(t, err)
+	assert.Len(t, user.UserLogs, 4)
+	assert.Len(t, user.UsersByCommentCreatedBy0, 2)
+	assert.Len(t, user.UserByUserManagerUserId, 2)
+
+	logs, err := user.LoadUserLogsByUserLogUserFkRegister()
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+
+	users, err := f.Select().Where(f.Id.Gt(firstUserId)).Eager(f.UserByCommentCreatedBy0).GetAll()
+	assert.Nil(t, err)
+	if assert.Len(t, users, 2) {
+		assert.NotNil(t, users[0].UserByCommentCreatedBy0)
+		assert.NotNil(t, users[1].UserByCommentCreatedBy0)
+	}
+
+	foos, err := mainModel.PreformTestA.Foo.Select().Eager(mainModel.PreformTestA.Foo.Bars).GetAll()
+	assert.Nil(t, err)
+	if assert.Len(t, foos, 2) {
+		assert.Len(t, foos[0].Bars, 1)
+		assert.Len(t, foos[1].Bars, 1)
+	}
+}
+
+func TestUserArray(t *testing.T) {
+	f := mainModel.PreformTestA.UserLog
+	count, err := f.Select().Where(f.Tags.Any("vip")).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+	count, err = f.Select().Where(f.Tags.Contains(preformTypes.Array[string]{"batch", "vip"})).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), count)
+	count, err = f.Select().Where(f.Tags.HasAny([]string{"new", "batch"})).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	//duckdb can't update list columns of a table with a primary key, insert to check the items are bound as they are
+	log := &mainModel.UserLogBody{UserId: firstUserId, Type: "login", Tags: preformTypes.Array[string]{"it's, quoted", "[x]"}}
+	assert.Nil(t, log.Insert())
+	count, err = f.Select().Where(f.Tags.Contains([]string{"it's, quoted", "[x]"})).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), count)
+	log, err = f.GetOne(log.Id)
+	if assert.Nil(t, err) {
+		assert.Equal(t, preformTypes.Array[string]{"it's, quoted", "[x]"}, log.Tags)
+	}
+	_, err = f.Delete().Where(f.Id.Eq(log.Id)).Exec()
+	assert.Nil(t, err)
+}
+
+func TestUserUpsert(t *testing.T) {
+	user, err := mainModel.PreformTestA.User.GetOne(firstUserId + 2)
+	assert.Nil(t, err)
+	user.Name = "test3-1"
+	err = mainModel.PreformTestA.User.Upsert(user, preform.UpsertConfig{UpdateCols: []preform.ICol{mainModel.PreformTestA.User.Name}})
+	assert.Nil(t, err)
+	assert.Equal(t, firstUserId+2, user.Id)
+	user, err = mainModel.PreformTestA.User.GetOne(firstUserId + 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "test3-1", user.Name)
+
+	newUser := &mainModel.UserBody{Name: "test4", CreatedBy: firstUserId, CreatedAt: time.Now()}
+	err = mainModel.PreformTestA.User.Upsert(newUser)
+	assert.Nil(t, err)
+	assert.Equal(t, firstUserId+3, newUser.Id)
+}
+
+func TestGetStructure(t *testing.T) {
+	_, err := conn.Exec(`
+CREATE SCHEMA preform_test_c;
+CREATE TABLE preform_test_c.parent (a INTEGER NOT NULL, b VARCHAR NOT NULL, PRIMARY KEY (a, b));
+CREATE TABLE preform_test_c.child (id BIGINT PRIMARY KEY, a INTEGER NOT NULL, b VARCHAR NOT NULL, tags INTEGER[] NULL, FOREIGN KEY (a, b) REFERENCES preform_test_c.parent (a, b));
+`)
+	assert.Nil(t, err)
+	defer conn.Exec(`DROP SCHEMA preform_test_c CASCADE`)
+	schemes := dialect.NewDuckdbDialect().GetStructure(conn, "preform_test_c")
+	if !assert.Len(t, schemes, 1) || !assert.Len(t, schemes[0].Tables, 2) {
+		return
+	}
+	child, parent := schemes[0].Tables[0], schemes[0].Tables[1]
+	assert.Equal(t, "child", child.Name)
+	assert.True(t, parent.ColumnByName["a"].IsPrimaryKey)
+	assert.Equal(t, int64(2), parent.ColumnByName["b"].PkPos)
+	assert.Equal(t, "preformTypes.Null[preformTypes.Array[int32]]", child.ColumnByName["tags"].GoType)
+	if assert.Len(t, child.ForeignKeys, 1) {
+		for _, fk := range child.ForeignKeys {
+			assert.Equal(t, []*preformShare.Column{child.ColumnByName["a"], child.ColumnByName["b"]}, fk.LocalKeys)
+			assert.Equal(t, []*preformShare.Column{parent.ColumnByName["a"], parent.ColumnByName["b"]}, fk.ForeignKeys)
+		}
+	}
+
+	schemes = dialect.NewDuckdbDialect().GetStructure(conn, "preform_test_a", "preform_test_b")
+	tables := map[string]*preformShare.Table{}
+	for _, scheme := range schemes {
+		for _, table := range scheme.Tables {
+			tables[scheme.Name+"."+table.Name] = table
+		}
+	}
+	userLog := tables["preform_test_a.user_log"]
+	if assert.NotNil(t, userLog) {
+		assert.True(t, userLog.ColumnByName["id"].IsAutoKey)
+		assert.Equal(t, "preformTypes.Array[string]", userLog.ColumnByName["tags"].GoType)
+		assert.Len(t, userLog.ColumnByName["user_id"].ForeignKeys, 1)
+		assert.Len(t, userLog.ColumnByName["related_log_id"].ForeignKeys, 1)
+	}
+	if bar := tables["preform_test_b.bar"]; assert.NotNil(t, bar) && assert.Len(t, bar.ColumnByName["id1"].ForeignKeys, 1) {
+		assert.Equal(t, tables["preform_test_a.foo"].ColumnByName["id"], bar.ColumnByName["id1"].ForeignKeys[0].ForeignKeys[0])
+	}
+}
+
+func TestDialect(t *testing.T) {
+	d := mainModel.PreformTestA.GetDialect()
+	assert.True(t, d.IsRetryable(fmt.Errorf("wrapped: %w", errors.New(`TransactionContext Error: Catalog write-write conflict on alter with "user"`))))
+	assert.True(t, d.IsRetryable(errors.New("TransactionContext Error: Conflict on tuple deletion!")))
+	assert.False(t, d.IsRetryable(errors.New("Constraint Error: Duplicate key \"id: 1\" violates primary key constraint")))
+
+	method, suffix := d.LastInsertIdMethod()
+	assert.Equal(t, dialect.LastInsertIdMethodBySuffix, method)
+	q, _, err := suffix("id").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `RETURNING "id"`, q)
+
+	upsert, _, err := d.Upsert([]string{"id"}, []string{"name"}, "id")
+	assert.Nil(t, err)
+	q, _, err = upsert.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`, q)
+
+	for fn, expected := range map[preformShare.Aggregator]string{
+		dialect.AggMedian:        `MEDIAN("id")`,
+		dialect.AggMode:          `MODE("id")`,
+		dialect.AggStdDev:        `STDDEV("id")`,
+		dialect.AggCountDistinct: `COUNT(DISTINCT "id")`,
+		dialect.AggJson:          `JSON_GROUP_ARRAY("id")`,
+	} {
+		q, _, err = d.Aggregate(fn, `"id"`).ToSql()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, q)
+	}
+	q, args, err := d.Aggregate(dialect.AggGroupConcat, `"name"`, ",").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `STRING_AGG("name", ?)`, q)
+	assert.Equal(t, []any{","}, args)
+
+	_, _, _, err = d.Savepoint("sp1")
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+}
+
+func TestTesters(t *testing.T) {
+	dummyDb := preformTestUtil.NewTestDB("duckdb")
+	mainModel.Init(dummyDb)
+	queryRunner := preformTestUtil.NewTestQueryRunner()
+	mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	dummyUser := mainModel.UserBody{
+		Id:        1,
+		Name:      "dummy",
+		CreatedBy: 1,
+		CreatedAt: time.Now(),
+	}
+	queryRunner.AddToQueryRows([][]driver.Value{{[]string{"id", "name", "created_by", "created_at", "logined_at"}}, {1, dummyUser.Name, 1, dummyUser.CreatedAt, nil}})
+	users, err := mainModel.PreformTestA.User.Select().Limit(1).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	queryRunner.ErrorQueue = append(queryRunner.ErrorQueue, sql.ErrNoRows)
+	_, err = mainModel.PreformTestA.User.GetOne(1)
+	assert.Equal(t, sql.ErrNoRows, err)
+	userScanner := preformTestUtil.NewTestModelScanner[mainModel.UserBody]()
+	mainModel.PreformTestA.User.SetModelScanner(userScanner)
+	userLogScanner := preformTestUtil.NewTestModelScanner[mainModel.UserLogBody]()
+	mainModel.PreformTestA.UserLog.SetModelScanner(userLogScanner)
+	userScanner.BodiesQueue = append(userScanner.BodiesQueue, []mainModel.UserBody{dummyUser})
+	userLogScanner.BodiesQueue = append(userLogScanner.BodiesQueue, []mainModel.UserLogBody{{Id: 9527, UserId: 1}})
+	users, err = mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Len(t, users[0].UserLogs, 1)
+	assert.Equal(t, int64(9527), users[0].UserLogs[0].Id)
+}
@@ -0,0 +1,7 @@
+package config
+
+import "fmt"
+
+var (
+	MssqlConnStr = fmt.Sprintf("sqlserver://%s:%s@%s?database=%s", "sa", "Preform123456", "localhost:1433", "master")
+)
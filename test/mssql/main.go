@@ -0,0 +1,110 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/go-preform/preform/preformBuilder"
+	"github.com/go-preform/preform/test/mssql/config"
+	"os"
+	"os/exec"
+)
+
+// register a sqlserver driver to regenerate the models, e.g. import _ "github.com/microsoft/go-mssqldb"
+// model_test runs on the testUtil driver and needs no server
+
+func main() {
+	msConn, err := sql.Open("sqlserver", config.MssqlConnStr)
+	if err != nil {
+		panic(err)
+	}
+	prepareSchema(msConn)
+	preformBuilder.BuildModel(msConn, "mainModel", "mainModel", "preform_test_a", "preform_test_b")
+
+	fmt.Println("go test----------------------------")
+	d, _ := os.Getwd()
+	p := fmt.Sprintf("%s/model_test", d)
+	cmd := exec.Command("go", "test", p)
+	cmd.Dir = p
+	out, err := cmd.CombinedOutput()
+	//if err != nil {
+	//	panic(err)
+	//}
+	fmt.Println(string(out), err)
+}
+
+func prepareSchema(msConn *sql.DB) {
+	for _, q := range []string{
+		"DROP TABLE IF EXISTS preform_test_b.bar",
+		"DROP TABLE IF EXISTS preform_test_a.user_manager",
+		"DROP TABLE IF EXISTS preform_test_a.user_log",
+		"DROP TABLE IF EXISTS preform_test_a.foo",
+		"DROP TABLE IF EXISTS preform_test_a.[user]",
+		"DROP SCHEMA IF EXISTS preform_test_a",
+		"DROP SCHEMA IF EXISTS preform_test_b",
+		"CREATE SCHEMA preform_test_a",
+		"CREATE SCHEMA preform_test_b",
+	} {
+		_, err := msConn.Exec(q)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	_, err := msConn.Exec(`CREATE TABLE preform_test_a.[user] (
+	id int IDENTITY(1,1) NOT NULL,
+	[name] varchar(255) NOT NULL,
+	created_by int NOT NULL,
+	created_at datetime NOT NULL,
+	logined_at datetime NULL,
+	CONSTRAINT user_PK PRIMARY KEY (id),
+	CONSTRAINT user_FK FOREIGN KEY (created_by) REFERENCES preform_test_a.[user] (id)
+);`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = msConn.Exec(`CREATE TABLE preform_test_a.user_manager (
+	user_id int NOT NULL,
+	manager_id int NOT NULL,
+	CONSTRAINT user_manager_PK PRIMARY KEY (user_id,manager_id),
+	CONSTRAINT staff_FK FOREIGN KEY (user_id) REFERENCES preform_test_a.[user] (id),
+	CONSTRAINT manager_FK FOREIGN KEY (manager_id) REFERENCES preform_test_a.[user] (id)
+);`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = msConn.Exec(`CREATE TABLE preform_test_a.user_log (
+	id bigint IDENTITY(1,1) NOT NULL,
+	user_id int NOT NULL,
+	related_log_id bigint NULL,
+	[type] varchar(16) NOT NULL CHECK ([type] IN ('Register','login')),
+	CONSTRAINT user_log_PK PRIMARY KEY (id),
+	CONSTRAINT user_log_FK FOREIGN KEY (user_id) REFERENCES preform_test_a.[user] (id),
+	CONSTRAINT user_log_related_FK FOREIGN KEY (related_log_id) REFERENCES preform_test_a.user_log (id)
+);`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = msConn.Exec(`CREATE TABLE preform_test_a.foo (
+	id int IDENTITY(1,1) NOT NULL,
+	fk1 int NOT NULL,
+	fk2 int NOT NULL,
+	CONSTRAINT foo_PK PRIMARY KEY (id),
+	CONSTRAINT foo_un UNIQUE (fk1, fk2)
+);`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = msConn.Exec(`CREATE TABLE preform_test_b.bar (
+	id1 int NOT NULL,
+	id2 int NOT NULL,
+	CONSTRAINT bar_PK PRIMARY KEY (id1, id2),
+	CONSTRAINT bar_FK FOREIGN KEY (id1, id2) REFERENCES preform_test_a.foo (fk1, fk2)
+);`)
+	if err != nil {
+		panic(err)
+	}
+}
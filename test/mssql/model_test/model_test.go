@@ -0,0 +1,169 @@
+package model_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/go-preform/preform/dialect"
+	preformShare "github.com/go-preform/preform/share"
+	"github.com/go-preform/preform/test/mssql/mainModel"
+	preformTestUtil "github.com/go-preform/preform/testUtil"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	dummyDb     *sql.DB
+	queryRunner *preformTestUtil.TestQueryRunner
+)
+
+type mssqlError int32
+
+func (e mssqlError) Error() string {
+	return fmt.Sprintf("mssql: error %d", int32(e))
+}
+
+func (e mssqlError) SQLErrorNumber() int32 {
+	return int32(e)
+}
+
+func TestInit(t *testing.T) {
+	dummyDb = preformTestUtil.NewTestDB("sqlserver")
+	mainModel.Init(dummyDb)
+	queryRunner = preformTestUtil.NewTestQueryRunner()
+	mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	mainModel.PreformTestB.SetConn(dummyDb, queryRunner)
+	assert.Equal(t, "[user]", mainModel.PreformTestA.GetDialect().QuoteIdentifier("user"))
+}
+
+func TestSelect(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	q, args, err := f.Select().Where(f.Id.Eq(1)).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, "[preform_test_a].[user]")
+	assert.Contains(t, q, "[id] = @p1")
+	assert.Len(t, args, 1)
+
+	q, _, err = f.Select().Where(f.Id.Gt(1)).Limit(10).Offset(20).ToSql()
+	assert.Nil(t, err)
+	assert.NotContains(t, q, "LIMIT")
+	assert.Contains(t, q, "ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY")
+
+	q, _, err = f.Select().OrderBy(f.Id.Asc()).Limit(10).ToSql()
+	assert.Nil(t, err)
+	assert.NotContains(t, q, "(SELECT NULL)")
+	assert.Contains(t, q, "OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY")
+
+	q, _, err = f.Select().OrderBy(f.Id.Asc()).Offset(5).ToSql()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(q, "OFFSET 5 ROWS"))
+}
+
+func TestPaging(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	q, _, err := f.Select(f.Id, f.Select(f.Name).OrderBy(f.Id.Desc()).Limit(1).AsSubQuery("last_name")).ToSql()
+	assert.Nil(t, err)
+	assert.NotContains(t, q, "LIMIT")
+	assert.Contains(t, q, "OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY) AS last_name")
+
+	q, _, err = f.Select().With("recent", f.Select().Limit(5)).ToSql()
+	assert.Nil(t, err)
+	assert.NotContains(t, q, "LIMIT")
+	assert.Contains(t, q, "ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY)")
+
+	q, _, err = f.Select().Union(f.Select()).Limit(5).ToSql()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(q, "ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY"))
+	_, _, err = f.Select().Limit(5).Union(f.Select()).ToSql()
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+	_, _, err = f.Select().Union(f.Select().Limit(5)).ToSql()
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+
+	_, _, err = f.Update().Set(f.Name, "a").Where(f.Id.Eq(1)).LimitOffset(1, 0).ToSql()
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+	_, _, err = f.Delete().Where(f.Id.Eq(1)).LimitOffset(1, 0).ToSql()
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+}
+
+func TestUserInsert(t *testing.T) {
+	queryRunner.LastIdQueue = append(queryRunner.LastIdQueue, 9527)
+	user := mainModel.UserBody{
+		Name:      "test1",
+		CreatedBy: 1,
+		CreatedAt: time.Now(),
+	}
+	err := user.Insert()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(9527), user.Id)
+
+	logs := []mainModel.UserLogBody{
+		{UserId: 9527, Type: "Register"},
+		{UserId: 9527, Type: "login"},
+	}
+	err = mainModel.PreformTestA.UserLog.InsertBatch(logs)
+	assert.Nil(t, err)
+}
+
+func TestDialect(t *testing.T) {
+	d := mainModel.PreformTestA.GetDialect()
+	assert.True(t, d.IsRetryable(fmt.Errorf("wrapped: %w", mssqlError(1205))))
+	assert.True(t, d.IsRetryable(mssqlError(3960)))
+	assert.False(t, d.IsRetryable(mssqlError(2627)))
+
+	savepoint, rollbackTo, release, err := d.Savepoint("sp1")
+	assert.Nil(t, err)
+	assert.Equal(t, "SAVE TRANSACTION [sp1]", savepoint)
+	assert.Equal(t, "ROLLBACK TRANSACTION [sp1]", rollbackTo)
+	assert.Equal(t, "", release)
+	assert.False(t, d.RowValueCompare())
+
+	var (
+		scheme = &preformShare.Scheme{Name: "preform_test_a"}
+		table  = &preformShare.Table{Name: "memo", Scheme: scheme}
+		id     = &preformShare.Column{Name: "id", Type: "int", IsPrimaryKey: true, IsAutoKey: true, Table: table}
+		body   = &preformShare.Column{Name: "body", Type: "nvarchar", Table: table}
+	)
+	table.Columns = []*preformShare.Column{id, body}
+	q, err := d.Ddl(preformShare.Ddl{Kind: preformShare.DdlCreateTable, Table: table})
+	assert.Nil(t, err)
+	assert.Contains(t, q, "[id] int IDENTITY(1,1) NOT NULL")
+	assert.Contains(t, q, "[body] nvarchar(255) NOT NULL")
+	q, err = d.Ddl(preformShare.Ddl{Kind: preformShare.DdlAddColumn, Table: table, Column: &preformShare.Column{Name: "note", Type: "varchar", Nullable: true}})
+	assert.Nil(t, err)
+	assert.Equal(t, "ALTER TABLE [preform_test_a].[memo] ADD [note] varchar(255)", q)
+	q, err = d.Ddl(preformShare.Ddl{Kind: preformShare.DdlAlterColumn, Table: table, FromColumn: body, Column: &preformShare.Column{Name: "body", Type: "nvarchar", Nullable: true}})
+	assert.Nil(t, err)
+	assert.Equal(t, "ALTER TABLE [preform_test_a].[memo] ALTER COLUMN [body] nvarchar(255) NULL", q)
+}
+
+func TestTesters(t *testing.T) {
+	mainModel.Init(dummyDb)
+	queryRunner := preformTestUtil.NewTestQueryRunner()
+	mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	dummyUser := mainModel.UserBody{
+		Id:        1,
+		Name:      "dummy",
+		CreatedBy: 1,
+		CreatedAt: time.Now(),
+	}
+	queryRunner.AddToQueryRows([][]driver.Value{{[]string{"id", "name", "created_by", "created_at", "logined_at"}}, {1, dummyUser.Name, 1, dummyUser.CreatedAt, nil}})
+	users, err := mainModel.PreformTestA.User.Select().Limit(1).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	queryRunner.ErrorQueue = append(queryRunner.ErrorQueue, sql.ErrNoRows)
+	_, err = mainModel.PreformTestA.User.GetOne(1)
+	assert.Equal(t, sql.ErrNoRows, err)
+	userScanner := preformTestUtil.NewTestModelScanner[mainModel.UserBody]()
+	mainModel.PreformTestA.User.SetModelScanner(userScanner)
+	userLogScanner := preformTestUtil.NewTestModelScanner[mainModel.UserLogBody]()
+	mainModel.PreformTestA.UserLog.SetModelScanner(userLogScanner)
+	userScanner.BodiesQueue = append(userScanner.BodiesQueue, []mainModel.UserBody{dummyUser})
+	userLogScanner.BodiesQueue = append(userLogScanner.BodiesQueue, []mainModel.UserLogBody{{Id: 9527, UserId: 1}})
+	users, err = mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Len(t, users[0].UserLogs, 1)
+	assert.Equal(t, int64(9527), users[0].UserLogs[0].Id)
+}
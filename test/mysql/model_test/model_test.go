@@ -2,6 +2,7 @@ package model_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"github.com/go-preform/preform"
@@ -13,6 +14,7 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -181,6 +183,34 @@ func TestUserSelect(t *testing.T) {
 
 }
 
+func TestUserJson(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		q, args, err := f.Select(f.Id, f.Name.JsonPath("address", "0", "city"), preform.JsonPathOf[int64](f.Name, "Age")).
+			Where(f.Name.JsonContains(map[string]string{"city": "HK"})).
+			Where(f.Name.JsonContains("vip")).
+			Where(f.Name.JsonContainsRaw(`[1, 2]`)).
+			Where(f.Name.JsonHasKey("phone")).ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, "JSON_UNQUOTE(JSON_EXTRACT(`User`.`name`, '$.address[0].city')) AS `city`")
+		assert.Contains(t, q, "JSON_EXTRACT(`User`.`name`, '$.Age') AS `Age`")
+		assert.Contains(t, q, "JSON_CONTAINS(`User`.`name`, ?) AND JSON_CONTAINS(`User`.`name`, ?) AND JSON_CONTAINS(`User`.`name`, ?)")
+		assert.Contains(t, q, "JSON_CONTAINS_PATH(`User`.`name`, 'one', '$.phone')")
+		assert.Equal(t, []any{`{"city":"HK"}`, `"vip"`, `[1,2]`}, args)
+	})
+}
+
+func TestUserFullText(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		rank := f.Name.MatchRank("red shoes").SetAlias("Rank")
+		q, args, err := f.Select(f.Id, rank).Where(f.Name.Match("red shoes")).OrderBy(rank.Desc()).ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, "MATCH (`User`.`name`) AGAINST (? IN NATURAL LANGUAGE MODE) AS `Rank`")
+		assert.Contains(t, q, "WHERE MATCH (`User`.`name`) AGAINST (? IN NATURAL LANGUAGE MODE)")
+		assert.Contains(t, q, "ORDER BY `Rank` DESC")
+		assert.Equal(t, []any{"red shoes", "red shoes"}, args)
+	})
+}
+
 func TestUserSelectRelation(t *testing.T) {
 	user, err := mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).GetOne(1)
 	assert.Nil(t, err)
@@ -261,6 +291,56 @@ func TestUserUpdate(t *testing.T) {
 	assert.Equal(t, int64(3), affected)
 }
 
+func TestUserVersion(t *testing.T) {
+	var (
+		f   = mainModel.PreformTestA.User
+		cfg = preform.UpdateConfig{Cols: []preform.ICol{f.Name}}
+	)
+	f.SetVersion(f.CreatedAt)
+	defer f.SetVersion(nil)
+
+	// DATETIME keeps seconds only, the version is read back as stored
+	// updates within the same second change no row, which mysql reports as 0 affected, but the row still matched
+	user, err := f.GetOne(4)
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		affected, err := user.Update(cfg)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), affected)
+	}
+	assert.Equal(t, 0, user.CreatedAt.Nanosecond())
+	stored, err := f.GetOne(4)
+	assert.Nil(t, err)
+	assert.True(t, stored.CreatedAt.Equal(user.CreatedAt))
+}
+
+func TestUserUpsert(t *testing.T) {
+	var (
+		f       = mainModel.PreformTestA.User
+		version string
+	)
+	assert.Nil(t, myConn.QueryRow("SELECT VERSION()").Scan(&version))
+	assert.Nil(t, mainModel.PreformTestA.DetectDialect(context.Background()))
+	suffix, _, err := mainModel.PreformTestA.Db().GetDialect().Upsert([]string{"id"}, []string{"name"}, "id")
+	assert.Nil(t, err)
+	q, _, err := suffix.ToSql()
+	assert.Nil(t, err)
+	if strings.HasPrefix(version, "5.") || strings.Contains(version, "MariaDB") {
+		assert.Equal(t, "ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `id` = LAST_INSERT_ID(`id`)", q)
+	} else {
+		assert.Equal(t, "AS `new` ON DUPLICATE KEY UPDATE `name` = `new`.`name`, `id` = LAST_INSERT_ID(`id`)", q)
+	}
+
+	user, err := f.GetOne(4)
+	assert.Nil(t, err)
+	user.Name = "test4-1"
+	assert.Nil(t, f.Upsert(user, preform.UpsertConfig{UpdateCols: []preform.ICol{f.Name}}))
+	assert.Equal(t, int32(4), user.Id)
+	user, err = f.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, "test4-1", user.Name)
+}
+
 func TestUserDelete(t *testing.T) {
 	affected, err := mainModel.PreformTestA.User.Delete().Where(mainModel.PreformTestA.User.Id.Gt(4)).Exec()
 	assert.Nil(t, err)
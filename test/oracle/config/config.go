@@ -0,0 +1,7 @@
+package config
+
+import "fmt"
+
+var (
+	OracleConnStr = fmt.Sprintf("oracle://%s:%s@%s/%s", "system", "Preform123456", "localhost:1521", "FREEPDB1")
+)
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/go-preform/preform/preformBuilder"
+	"github.com/go-preform/preform/test/oracle/config"
+	"os"
+	"os/exec"
+)
+
+// register an oracle driver to regenerate the models, e.g. import _ "github.com/sijms/go-ora/v2"
+// model_test runs on the testUtil driver and needs no server
+
+func main() {
+	oraConn, err := sql.Open("oracle", config.OracleConnStr)
+	if err != nil {
+		panic(err)
+	}
+	prepareSchema(oraConn)
+	preformBuilder.BuildModel(oraConn, "mainModel", "mainModel", "preform_test_a", "preform_test_b")
+
+	fmt.Println("go test----------------------------")
+	d, _ := os.Getwd()
+	p := fmt.Sprintf("%s/model_test", d)
+	cmd := exec.Command("go", "test", p)
+	cmd.Dir = p
+	out, err := cmd.CombinedOutput()
+	//if err != nil {
+	//	panic(err)
+	//}
+	fmt.Println(string(out), err)
+}
+
+// prepareSchema identifiers are quoted to keep them in lower case
+func prepareSchema(oraConn *sql.DB) {
+	for _, q := range []string{
+		`BEGIN EXECUTE IMMEDIATE 'DROP USER "preform_test_b" CASCADE'; EXCEPTION WHEN OTHERS THEN NULL; END;`,
+		`BEGIN EXECUTE IMMEDIATE 'DROP USER "preform_test_a" CASCADE'; EXCEPTION WHEN OTHERS THEN NULL; END;`,
+		`CREATE USER "preform_test_a" IDENTIFIED BY "Preform123456" QUOTA UNLIMITED ON USERS`,
+		`CREATE USER "preform_test_b" IDENTIFIED BY "Preform123456" QUOTA UNLIMITED ON USERS`,
+	} {
+		_, err := oraConn.Exec(q)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	_, err := oraConn.Exec(`CREATE TABLE "preform_test_a"."user" (
+	"id" NUMBER(9) GENERATED BY DEFAULT AS IDENTITY,
+	"name" VARCHAR2(255) NOT NULL,
+	"created_by" NUMBER(9) NOT NULL,
+	"created_at" TIMESTAMP NOT NULL,
+	"logined_at" TIMESTAMP NULL,
+	CONSTRAINT "user_PK" PRIMARY KEY ("id"),
+	CONSTRAINT "user_FK" FOREIGN KEY ("created_by") REFERENCES "preform_test_a"."user" ("id")
+)`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = oraConn.Exec(`CREATE TABLE "preform_test_a"."user_manager" (
+	"user_id" NUMBER(9) NOT NULL,
+	"manager_id" NUMBER(9) NOT NULL,
+	CONSTRAINT "user_manager_PK" PRIMARY KEY ("user_id", "manager_id"),
+	CONSTRAINT "staff_FK" FOREIGN KEY ("user_id") REFERENCES "preform_test_a"."user" ("id") ON DELETE CASCADE,
+	CONSTRAINT "manager_FK" FOREIGN KEY ("manager_id") REFERENCES "preform_test_a"."user" ("id") ON DELETE CASCADE
+)`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = oraConn.Exec(`CREATE TABLE "preform_test_a"."user_log" (
+	"id" NUMBER(18) GENERATED BY DEFAULT AS IDENTITY,
+	"user_id" NUMBER(9) NOT NULL,
+	"related_log_id" NUMBER(18) NULL,
+	"type" VARCHAR2(16) NOT NULL CHECK ("type" IN ('Register', 'login')),
+	CONSTRAINT "user_log_PK" PRIMARY KEY ("id"),
+	CONSTRAINT "user_log_FK" FOREIGN KEY ("user_id") REFERENCES "preform_test_a"."user" ("id"),
+	CONSTRAINT "user_log_related_FK" FOREIGN KEY ("related_log_id") REFERENCES "preform_test_a"."user_log" ("id")
+)`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = oraConn.Exec(`CREATE TABLE "preform_test_a"."foo" (
+	"id" NUMBER(9) GENERATED BY DEFAULT AS IDENTITY,
+	"fk1" NUMBER(9) NOT NULL,
+	"fk2" NUMBER(9) NOT NULL,
+	CONSTRAINT "foo_PK" PRIMARY KEY ("id"),
+	CONSTRAINT "foo_un" UNIQUE ("fk1", "fk2")
+)`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = oraConn.Exec(`GRANT REFERENCES ON "preform_test_a"."foo" TO "preform_test_b"`)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = oraConn.Exec(`CREATE TABLE "preform_test_b"."bar" (
+	"id1" NUMBER(9) NOT NULL,
+	"id2" NUMBER(9) NOT NULL,
+	CONSTRAINT "bar_PK" PRIMARY KEY ("id1", "id2"),
+	CONSTRAINT "bar_FK" FOREIGN KEY ("id1", "id2") REFERENCES "preform_test_a"."foo" ("fk1", "fk2")
+)`)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,320 @@
+package model_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/go-preform/preform/cachedQueryRunner"
+	"github.com/go-preform/preform/dialect"
+	"github.com/go-preform/preform/test/oracle/mainModel"
+	preformTestUtil "github.com/go-preform/preform/testUtil"
+	preformTracer "github.com/go-preform/preform/tracer"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	dummyDb     *sql.DB
+	queryRunner *preformTestUtil.TestQueryRunner
+)
+
+type godrorError int
+
+func (e godrorError) Error() string {
+	return fmt.Sprintf("ORA-%05d", int(e))
+}
+
+func (e godrorError) Code() int {
+	return int(e)
+}
+
+func TestInit(t *testing.T) {
+	dummyDb = preformTestUtil.NewTestDB("oracle")
+	mainModel.Init(dummyDb)
+	queryRunner = preformTestUtil.NewTestQueryRunner()
+	mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	mainModel.PreformTestB.SetConn(dummyDb, queryRunner)
+	assert.Equal(t, `"user"`, mainModel.PreformTestA.GetDialect().QuoteIdentifier("user"))
+}
+
+func TestSelect(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	q, args, err := f.Select().Where(f.Id.Eq(1)).ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `"preform_test_a"."user"`)
+	assert.Contains(t, q, `"id" = :1`)
+	assert.Len(t, args, 1)
+
+	q, _, err = f.Select().Where(f.Id.Gt(1)).Limit(10).Offset(20).ToSql()
+	assert.Nil(t, err)
+	assert.NotContains(t, q, "LIMIT")
+	assert.Contains(t, q, "OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY")
+
+	q, _, err = f.Select().OrderBy(f.Id.Asc()).Limit(10).ToSql()
+	assert.Nil(t, err)
+	assert.NotContains(t, q, "OFFSET")
+	assert.Contains(t, q, "FETCH FIRST 10 ROWS ONLY")
+
+	queryRunner.AddToQueryRows([][]driver.Value{{[]string{"id", "name", "created_by", "created_at", "logined_at"}}, {3, "test3", 1, time.Now(), nil}})
+	users, _, err := f.Select().Limit(1).PageAfter(base64.RawURLEncoding.EncodeToString([]byte("[1,2]")), f.CreatedBy, f.Id)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	q = queryRunner.Queries[len(queryRunner.Queries)-1]
+	assert.NotContains(t, q, ") > (")
+	assert.Contains(t, q, " OR ")
+}
+
+func TestUserInsert(t *testing.T) {
+	queryRunner.LastIdQueue = append(queryRunner.LastIdQueue, 9527)
+	user := mainModel.UserBody{
+		Name:      "test1",
+		CreatedBy: 1,
+		CreatedAt: time.Now(),
+	}
+	err := user.Insert()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(9527), user.Id)
+}
+
+func TestInsertOutParam(t *testing.T) {
+	var (
+		fake   = &fakeOracle{}
+		conn   = sql.OpenDB(fake)
+		runner = map[string]func(){
+			"wrapper": func() { mainModel.PreformTestA.SetConn(conn) },
+			"tracer": func() {
+				mainModel.PreformTestA.SetConn(conn)
+				mainModel.PreformTestA.SetTracerToDb(preformTracer.NewPlainTracer(0, time.Hour))
+			},
+			"cached": func() {
+				mainModel.PreformTestA.SetConn(conn, cachedQueryRunner.NewUnsafeCachedQueryRunner(sqlx.NewDb(conn, "godror")))
+			},
+		}
+	)
+	defer mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	for name, use := range runner {
+		use()
+		fake.outId++
+		user := mainModel.UserBody{
+			Name:      "test1",
+			CreatedBy: 1,
+			CreatedAt: time.Now(),
+		}
+		assert.Nil(t, user.Insert(), name)
+		assert.Equal(t, int32(fake.outId), user.Id, name)
+		q, args := fake.queries[len(fake.queries)-1], fake.args[len(fake.args)-1]
+		assert.True(t, strings.HasSuffix(q, fmt.Sprintf(`RETURNING "id" INTO :%d`, len(args))), name, q)
+		assert.NotContains(t, q, "?", name)
+		if assert.NotEmpty(t, args, name) {
+			out, ok := args[len(args)-1].Value.(sql.Out)
+			if assert.True(t, ok, name) {
+				assert.IsType(t, new(int64), out.Dest, name)
+			}
+		}
+		mainModel.PreformTestA.SetTracerToDb(nil)
+	}
+}
+
+func TestGetStructure(t *testing.T) {
+	fake := &fakeOracle{rows: [][][]driver.Value{
+		{{"OWNER", "TABLE_NAME", "TABLE_TYPE"}, {"APP", "ACCOUNT", "TABLE"}, {"APP", "ORDERS", "TABLE"}},
+		{{"OWNER", "TABLE_NAME", "COLUMN_NAME", "DATA_TYPE", "DATA_PRECISION", "DATA_SCALE", "NULLABLE", "DATA_DEFAULT", "IDENTITY_COLUMN", "COMMENTS"},
+			{"APP", "ACCOUNT", "ID", "NUMBER", nil, nil, "N", nil, "YES", nil},
+			{"APP", "ACCOUNT", "AGE", "NUMBER", int64(3), int64(0), "Y", nil, "NO", nil},
+			{"APP", "ACCOUNT", "BALANCE", "NUMBER", int64(12), int64(2), "N", nil, "NO", nil},
+			{"APP", "ACCOUNT", "NOTE", "CLOB", nil, nil, "Y", "NULL ", "NO", nil},
+			{"APP", "ORDERS", "ID", "NUMBER", int64(10), int64(0), "N", "APP.ORDERS_SEQ.NEXTVAL ", "NO", nil},
+			{"APP", "ORDERS", "ACCOUNT_ID", "NUMBER", nil, nil, "N", nil, "NO", nil},
+			{"APP", "ORDERS", "CREATED_AT", "DATE", nil, nil, "N", "SYSDATE", "NO", nil},
+		},
+		{{"OWNER", "TABLE_NAME", "COLUMN_NAME", "POSITION"}, {"APP", "ACCOUNT", "ID", int64(1)}, {"APP", "ORDERS", "ID", int64(1)}},
+		{{"CONSTRAINT_NAME", "OWNER", "TABLE_NAME", "COLUMN_NAME", "OWNER", "TABLE_NAME", "COLUMN_NAME"}, {"ORDERS_ACCOUNT_FK", "APP", "ORDERS", "ACCOUNT_ID", "APP", "ACCOUNT", "ID"}},
+	}}
+	schemes := dialect.NewOracleDialect().GetStructure(sql.OpenDB(fake), "APP")
+	assert.Len(t, fake.queries, 4)
+	assert.Contains(t, fake.queries[0], "OWNER IN (:1)")
+	if !assert.Len(t, schemes, 1) || !assert.Len(t, schemes[0].Tables, 2) {
+		return
+	}
+	account, orders := schemes[0].Tables[0], schemes[0].Tables[1]
+	col := account.ColumnByName["ID"]
+	assert.True(t, col.IsAutoKey)
+	assert.True(t, col.IsPrimaryKey)
+	assert.Equal(t, "int64", col.GoType)
+	assert.Equal(t, "preformTypes.Null[int16]", account.ColumnByName["AGE"].GoType)
+	assert.Equal(t, "preformTypes.Rat", account.ColumnByName["BALANCE"].GoType)
+	assert.Equal(t, "preformTypes.Null[string]", account.ColumnByName["NOTE"].GoType)
+	assert.False(t, account.ColumnByName["NOTE"].DefaultValue.Valid)
+	col = orders.ColumnByName["ID"]
+	assert.True(t, col.IsAutoKey)
+	assert.Equal(t, "int64", col.GoType)
+	assert.Equal(t, "preformTypes.Rat", orders.ColumnByName["ACCOUNT_ID"].GoType)
+	assert.False(t, orders.ColumnByName["CREATED_AT"].IsAutoKey)
+	assert.Equal(t, "time.Time", orders.ColumnByName["CREATED_AT"].GoType)
+	if fk, ok := orders.ForeignKeys["ORDERS_ACCOUNT_FK"]; assert.True(t, ok) {
+		assert.Equal(t, orders.ColumnByName["ACCOUNT_ID"], fk.LocalKeys[0])
+		assert.Equal(t, account.ColumnByName["ID"], fk.ForeignKeys[0])
+	}
+}
+
+func TestDialect(t *testing.T) {
+	d := mainModel.PreformTestA.GetDialect()
+	assert.True(t, d.IsRetryable(fmt.Errorf("wrapped: %w", godrorError(60))))
+	assert.True(t, d.IsRetryable(godrorError(8177)))
+	assert.False(t, d.IsRetryable(godrorError(1)))
+	assert.True(t, d.IsRetryable(errors.New("ORA-08177: can't serialize access for this transaction")))
+	assert.False(t, d.IsRetryable(errors.New("ORA-00001: unique constraint violated")))
+
+	savepoint, rollbackTo, release, err := d.Savepoint("sp1")
+	assert.Nil(t, err)
+	assert.Equal(t, `SAVEPOINT "sp1"`, savepoint)
+	assert.Equal(t, `ROLLBACK TO SAVEPOINT "sp1"`, rollbackTo)
+	assert.Equal(t, "", release)
+
+	method, suffix := d.LastInsertIdMethod()
+	assert.Equal(t, dialect.LastInsertIdMethodByOutParam, method)
+	q, _, err := suffix("id").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `RETURNING "id" INTO ?`, q)
+
+	q, _, err = d.Aggregate(dialect.AggGroupConcat, `"name"`, ",").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, `LISTAGG("name", ',') WITHIN GROUP (ORDER BY NULL)`, q)
+
+	assert.False(t, d.RowValueCompare())
+
+	op, err := d.SetOperation("EXCEPT")
+	assert.Nil(t, err)
+	assert.Equal(t, "MINUS", op)
+}
+
+func TestTesters(t *testing.T) {
+	mainModel.Init(dummyDb)
+	queryRunner := preformTestUtil.NewTestQueryRunner()
+	mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	dummyUser := mainModel.UserBody{
+		Id:        1,
+		Name:      "dummy",
+		CreatedBy: 1,
+		CreatedAt: time.Now(),
+	}
+	queryRunner.AddToQueryRows([][]driver.Value{{[]string{"id", "name", "created_by", "created_at", "logined_at"}}, {1, dummyUser.Name, 1, dummyUser.CreatedAt, nil}})
+	users, err := mainModel.PreformTestA.User.Select().Limit(1).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	queryRunner.ErrorQueue = append(queryRunner.ErrorQueue, sql.ErrNoRows)
+	_, err = mainModel.PreformTestA.User.GetOne(1)
+	assert.Equal(t, sql.ErrNoRows, err)
+	userScanner := preformTestUtil.NewTestModelScanner[mainModel.UserBody]()
+	mainModel.PreformTestA.User.SetModelScanner(userScanner)
+	userLogScanner := preformTestUtil.NewTestModelScanner[mainModel.UserLogBody]()
+	mainModel.PreformTestA.UserLog.SetModelScanner(userLogScanner)
+	userScanner.BodiesQueue = append(userScanner.BodiesQueue, []mainModel.UserBody{dummyUser})
+	userLogScanner.BodiesQueue = append(userLogScanner.BodiesQueue, []mainModel.UserLogBody{{Id: 9527, UserId: 1}})
+	users, err = mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Len(t, users[0].UserLogs, 1)
+	assert.Equal(t, int64(9527), users[0].UserLogs[0].Id)
+}
+
+// fakeOracle records what reaches the driver, binds sql.Out to outId and answers queries by rows in order, first row is the column names
+type fakeOracle struct {
+	queries []string
+	args    [][]driver.NamedValue
+	rows    [][][]driver.Value
+	outId   int64
+}
+
+func (f *fakeOracle) Connect(context.Context) (driver.Conn, error) {
+	return fakeOracleConn{f}, nil
+}
+
+func (f *fakeOracle) Driver() driver.Driver {
+	return f
+}
+
+func (f *fakeOracle) Open(string) (driver.Conn, error) {
+	return fakeOracleConn{f}, nil
+}
+
+func (f *fakeOracle) TestDriverName() string {
+	return "oracle"
+}
+
+type fakeOracleConn struct {
+	*fakeOracle
+}
+
+func (c fakeOracleConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c fakeOracleConn) Close() error {
+	return nil
+}
+
+func (c fakeOracleConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c fakeOracleConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.(sql.Out); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+func (c fakeOracleConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.queries = append(c.queries, query)
+	c.args = append(c.args, args)
+	for _, arg := range args {
+		if out, ok := arg.Value.(sql.Out); ok {
+			*out.Dest.(*int64) = c.outId
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakeOracleConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.queries = append(c.queries, query)
+	c.args = append(c.args, args)
+	if len(c.rows) == 0 {
+		return nil, errors.New("no rows queued")
+	}
+	rows := &fakeOracleRows{values: c.rows[0][1:]}
+	for _, col := range c.rows[0][0] {
+		rows.cols = append(rows.cols, col.(string))
+	}
+	c.rows = c.rows[1:]
+	return rows, nil
+}
+
+type fakeOracleRows struct {
+	cols   []string
+	values [][]driver.Value
+}
+
+func (r *fakeOracleRows) Columns() []string {
+	return r.cols
+}
+
+func (r *fakeOracleRows) Close() error {
+	return nil
+}
+
+func (r *fakeOracleRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,79 @@
+package mainModel
+
+import (
+	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/types"
+	"time"
+)
+
+var UserTree = preform.IniPrebuildQueryFactory[*UserTreeFactory, UserTreeBody](func(d *UserTreeFactory) {
+	d.User = d.PreformTestASchema.User.SetAlias("User").(*FactoryUser)
+	d.WithRecursive(d.User, d.PreformTestASchema.User.Id.Eq(2), d.PreformTestASchema.User.CreatedBy).
+		SetSrc(d.User).DefineCols(
+		preform.SetPrebuildQueryCol(d, d.User.Id.SetAlias("UserId"), d.UserId),
+		preform.SetPrebuildQueryCol(d, d.User.Name.SetAlias("UserName"), d.UserName),
+		preform.SetPrebuildQueryCol(d, d.User.ManagerIds.SetAlias("UserManagerIds"), d.UserManagerIds),
+		preform.SetPrebuildQueryCol(d, d.User.CreatedBy.SetAlias("UserCreatedBy"), d.UserCreatedBy),
+		preform.SetPrebuildQueryCol(d, d.User.CreatedAt.SetAlias("UserCreatedAt"), d.UserCreatedAt),
+		preform.SetPrebuildQueryCol(d, d.User.LoginedAt.SetAlias("UserLoginedAt"), d.UserLoginedAt),
+		preform.SetPrebuildQueryCol(d, d.User.Detail.SetAlias("UserDetail"), d.UserDetail),
+		preform.SetPrebuildQueryCol(d, d.User.Config.SetAlias("UserConfig"), d.UserConfig),
+		preform.SetPrebuildQueryCol(d, d.User.ExtraConfig.SetAlias("UserExtraConfig"), d.UserExtraConfig),
+	)
+})
+
+type UserTreeFactory struct {
+	preform.PrebuildQueryFactory[*UserTreeFactory, UserTreeBody]
+	//schema src
+	PreformTestASchema *PreformTestASchema
+
+	//factory src
+	User *FactoryUser
+	
+	//columns
+	UserId *preform.PrebuildQueryCol[int32, preform.NoAggregation]
+	UserName *preform.PrebuildQueryCol[string, preform.NoAggregation]
+	UserManagerIds *preform.PrebuildQueryCol[preformTypes.Array[int32], preform.NoAggregation]
+	UserCreatedBy *preform.PrebuildQueryCol[int32, preform.NoAggregation]
+	UserCreatedAt *preform.PrebuildQueryCol[time.Time, preform.NoAggregation]
+	UserLoginedAt *preform.PrebuildQueryCol[preformTypes.Null[time.Time], preform.NoAggregation]
+	UserDetail *preform.PrebuildQueryCol[preformTypes.Null[preformTypes.JsonRaw[interface {}]], preform.NoAggregation]
+	UserConfig *preform.PrebuildQueryCol[preformTypes.JsonRaw[interface {}], preform.NoAggregation]
+	UserExtraConfig *preform.PrebuildQueryCol[preformTypes.JsonRaw[interface {}], preform.NoAggregation]
+}
+
+type UserTreeBody struct {
+	preform.QueryBody[UserTreeBody, *UserTreeFactory]
+	UserId int32 `db:"UserId" json:"Id" dataType:"int4" autoKey:"true"`
+	UserName string `db:"UserName" json:"Name" dataType:"varchar"`
+	UserManagerIds preformTypes.Array[int32] `db:"UserManagerIds" json:"ManagerIds" dataType:"_int4"`
+	UserCreatedBy int32 `db:"UserCreatedBy" json:"CreatedBy" dataType:"int4"`
+	UserCreatedAt time.Time `db:"UserCreatedAt" json:"CreatedAt" dataType:"timestamptz"`
+	UserLoginedAt preformTypes.Null[time.Time] `db:"UserLoginedAt" json:"LoginedAt" dataType:"timestamptz"`
+	UserDetail preformTypes.Null[preformTypes.JsonRaw[interface {}]] `db:"UserDetail" json:"Detail" dataType:"jsonb"`
+	UserConfig preformTypes.JsonRaw[interface {}] `db:"UserConfig" json:"Config" dataType:"jsonb" defaultValue:"'{}'::json"`
+	UserExtraConfig preformTypes.JsonRaw[interface {}] `db:"UserExtraConfig" json:"ExtraConfig" dataType:"jsonb" defaultValue:"'{}'::json"`
+}
+
+func (m UserTreeBody) Factory() *UserTreeFactory { return UserTree }
+
+func (m *UserTreeBody) FieldValuePtr(pos int) any { 
+	switch pos {
+		case 0: return &m.UserId
+		case 1: return &m.UserName
+		case 2: return &m.UserManagerIds
+		case 3: return &m.UserCreatedBy
+		case 4: return &m.UserCreatedAt
+		case 5: return &m.UserLoginedAt
+		case 6: return &m.UserDetail
+		case 7: return &m.UserConfig
+		case 8: return &m.UserExtraConfig
+	}
+	return nil
+}
+
+func (m *UserTreeBody) FieldValuePtrs() []any { 
+	return []any{&m.UserId, &m.UserName, &m.UserManagerIds, &m.UserCreatedBy, &m.UserCreatedAt, &m.UserLoginedAt, &m.UserDetail, &m.UserConfig, &m.UserExtraConfig}
+}
+
+
@@ -11,6 +11,10 @@ func init() {
 		builder.From(pta.user).InnerJoinByForeignKey(pta.userLog.UserId).Where(pta.userLog.UserId.NotEq(2))
 		return builder
 	}))
+	PrebuildQueries = append(PrebuildQueries, preformBuilder.BuildQuery("user_tree", func(builder *preformBuilder.QueryBuilder, pta *PreformTestASchema) *preformBuilder.QueryBuilder {
+		builder.WithRecursive(pta.user, pta.user.Id.Eq(2), pta.user.CreatedBy).From(pta.user)
+		return builder
+	}))
 }
 
 func (p *PreformTestA_user) Setup() (skipAutoSetter bool) {
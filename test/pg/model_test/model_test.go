@@ -2,9 +2,13 @@ package model_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/dialect"
+	preformShare "github.com/go-preform/preform/share"
 	"github.com/go-preform/preform/test/pg/config"
 	"github.com/go-preform/preform/test/pg/mainModel"
 	"github.com/go-preform/preform/test/pg/mainModel/src/types"
@@ -14,7 +18,9 @@ import (
 	"github.com/rs/zerolog"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -206,6 +212,345 @@ func TestUserSelect(t *testing.T) {
 
 }
 
+func TestUserJson(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		var (
+			age     = preform.JsonField(f.Detail, func(d *types.UserDetail) *uint32 { return &d.Age })
+			ageRaw  = preform.JsonPathOf[preformTypes.JsonRaw[uint32]](f.Detail, "Age").SetAlias("AgeRaw")
+			ageText = f.Detail.JsonPath("Age")
+			query   = f.Select(f.Id, ageText, ageRaw).Where(age.Gt(10)).Where(f.Detail.JsonContains(map[string]uint32{"Age": 11}))
+		)
+		q, args, err := query.ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, `"User"."detail"->>'Age' AS "Age"`)
+		assert.Contains(t, q, `"User"."detail"->'Age' AS "AgeRaw"`)
+		assert.Contains(t, q, `("User"."detail"->>'Age')::numeric > $1`)
+		assert.Contains(t, q, `"User"."detail" @> $2::jsonb`)
+		assert.Equal(t, []any{10, `{"Age":11}`}, args)
+		rows, err := query.QueryRaw()
+		assert.Nil(t, err)
+		assert.Len(t, rows.Rows, 1)
+		assert.EqualValues(t, 1, rows.Rows[0][0])
+		assert.Equal(t, "11", rows.Rows[0][1])
+		assert.Equal(t, uint32(11), rows.Rows[0][2].(*preformTypes.JsonRaw[uint32]).Get())
+
+		users, err := f.Select().Where(f.Detail.JsonContainsRaw(`{"Age": 11}`)).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		users, err = f.Select().Where(f.Detail.JsonContains(map[string]uint32{"Age": 12})).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+		users, err = f.Select().Where(f.Detail.JsonContains("Age")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+		users, err = f.Select().Where(f.Detail.JsonHasKey("Age")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	})
+}
+
+func TestUserFullText(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		users, err := f.Select().Where(f.Name.Match("test1")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int32(1), users[0].Id)
+		users, err = f.Select().Where(f.Name.Match("test1 test2")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+		rank := f.Name.MatchRank("test2").SetAlias("Rank")
+		assert.Equal(t, `"Rank" DESC`, rank.Desc())
+		rows, err := f.Select(f.Id, rank).OrderBy(rank.Desc(), f.Id.Asc()).QueryRaw()
+		assert.Nil(t, err)
+		assert.Len(t, rows.Rows, 5)
+		assert.EqualValues(t, 2, rows.Rows[0][0])
+		assert.Greater(t, rows.Rows[0][1], float64(0))
+		assert.EqualValues(t, 0, rows.Rows[1][1])
+	})
+}
+
+func TestUserSetOperation(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		users, err := f.Select().Where(f.Id.Eq(1)).Union(f.Select().Where(f.Id.Eq(2))).OrderBy(f.Id.Desc()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, int32(2), users[0].Id)
+		assert.Equal(t, int32(1), users[1].Id)
+
+		users, err = f.Select().Where(f.Id.Lt(4)).Except(f.Select().Where(f.Id.Eq(2))).OrderBy(f.Id.Asc()).Limit(1).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int32(1), users[0].Id)
+	})
+}
+
+func TestUserWith(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		child := f.SetAlias("Child").(*mainModel.FactoryUser)
+		users, err := f.Select().WithRecursive(f.Alias(), f.Select().Where(f.Id.Eq(2)), child.Select().CrossJoin(`"User"`).Where(child.CreatedBy.Eq(f.Id))).OrderBy(f.Id.Asc()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 3)
+		assert.Equal(t, int32(2), users[0].Id)
+		assert.Equal(t, int32(3), users[1].Id)
+		assert.Equal(t, int32(5), users[2].Id)
+	})
+
+	users, err := mainModel.UserTree.Select().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+}
+
+func TestUserLock(t *testing.T) {
+	_, err := mainModel.PreformTestA.User.Select().ForUpdate().GetAll()
+	assert.Equal(t, preform.ErrorLockWithoutTx, err)
+
+	tx, err := mainModel.PreformTestA.BeginTx(context.Background())
+	assert.Nil(t, err)
+	users, err := mainModel.PreformTestA.User.Select().Where(mainModel.PreformTestA.User.Id.Eq(1)).Tx(tx).ForUpdate().Of(mainModel.PreformTestA.User).SkipLocked().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	cnt, err := mainModel.PreformTestA.User.Select().Tx(tx).ForShare().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	assert.Nil(t, tx.Rollback())
+}
+
+func TestTxSavepoint(t *testing.T) {
+	var (
+		f   = mainModel.PreformTestA.User
+		ctx = context.Background()
+	)
+	tx, err := mainModel.PreformTestA.BeginTx(ctx)
+	assert.Nil(t, err)
+	nested, err := tx.BeginTx(ctx)
+	assert.Nil(t, err)
+	_, err = f.Update().Set(f.Name, "savepoint").Where(f.Id.Eq(1)).Exec(nested)
+	assert.Nil(t, err)
+	assert.Nil(t, nested.Rollback())
+
+	nested, err = tx.BeginTx(ctx)
+	assert.Nil(t, err)
+	_, err = f.Update().Set(f.Name, "savepoint").Where(f.Id.Eq(2)).Exec(nested)
+	assert.Nil(t, err)
+	assert.Nil(t, nested.Commit())
+	assert.ErrorIs(t, nested.Commit(), preform.ErrorTxDone)
+
+	users, err := f.Select().Tx(tx).Where(f.Name.Eq("savepoint")).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int32(2), users[0].Id)
+	assert.Nil(t, tx.Rollback())
+
+	users, err = f.Select().Where(f.Name.Eq("savepoint")).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 0)
+}
+
+func TestWithTx(t *testing.T) {
+	var (
+		f        = mainModel.PreformTestA.User
+		errAbort = errors.New("abort")
+	)
+	err := mainModel.PreformTestA.WithTx(context.Background(), func(ctx context.Context) error {
+		_, err := f.Update().Ctx(ctx).Set(f.Name, "withTx").Where(f.Id.Eq(1)).Exec()
+		assert.Nil(t, err)
+		return mainModel.PreformTestA.WithTx(ctx, func(ctx context.Context) error {
+			assert.NotNil(t, preform.TxFromCtx(ctx))
+			user, err := f.Select().Ctx(ctx).GetOne(1)
+			assert.Nil(t, err)
+			assert.Equal(t, "withTx", user.Name)
+			return errAbort
+		})
+	})
+	assert.ErrorIs(t, err, errAbort)
+	user, err := f.GetOne(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "test1", user.Name)
+}
+
+type serializationFailure struct{}
+
+func (serializationFailure) Error() string    { return "could not serialize access" }
+func (serializationFailure) SQLState() string { return "40001" }
+
+func TestWithTxRetry(t *testing.T) {
+	var (
+		attempts int
+		policy   = &preform.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+	)
+	err := mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return serializationFailure{}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	attempts = 0
+	err = mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return serializationFailure{}
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, attempts)
+	attempts = 0
+	err = mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return errors.New("not retryable")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestReplica(t *testing.T) {
+	var (
+		f          = mainModel.PreformTestA.User
+		replica, _ = sql.Open("pgx", config.PgConnStr)
+	)
+	mainModel.PreformTestA.SetReplicas(replica)
+	defer mainModel.PreformTestA.SetReplicas()
+	cnt, err := f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	assert.Nil(t, replica.Close())
+	_, err = f.Select().Count()
+	assert.NotNil(t, err)
+	cnt, err = f.Select().Primary().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	user, err := f.Select().Ctx(preform.ContextWithPrimary(context.Background())).Eager(f.UserLogs).GetOne(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(user.UserLogs))
+	mainModel.PreformTestA.SetReplicaHealthCheck(time.Millisecond, time.Minute)
+	defer mainModel.PreformTestA.SetReplicaHealthCheck(0, 0)
+	time.Sleep(50 * time.Millisecond)
+	cnt, err = f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+}
+
+func TestMigrator(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		memo  = &preformShare.Table{Name: "memo", Scheme: &preformShare.Scheme{Name: "preform_test_a"}}
+		id    = &preformShare.Column{Name: "id", Type: "INTEGER", IsPrimaryKey: true, PkPos: 1, Table: memo}
+		text  = &preformShare.Column{Name: "text", Type: "TEXT", Table: memo}
+		table = "preform_migration_test"
+	)
+	memo.Columns = []*preformShare.Column{id, text}
+	memo.ColumnByName = map[string]*preformShare.Column{"id": id, "text": text}
+	createMemo, err := mainModel.PreformTestA.GetDialect().Ddl(preformShare.Ddl{Kind: preformShare.DdlCreateTable, Table: memo})
+	assert.Nil(t, err)
+	dropMemo, err := mainModel.PreformTestA.GetDialect().Ddl(preformShare.Ddl{Kind: preformShare.DdlDropTable, Table: memo})
+	assert.Nil(t, err)
+	migrator := mainModel.PreformTestA.NewMigrator(fstest.MapFS{
+		"20240101000000_memo.up.sql":   {Data: []byte(createMemo + ";\n")},
+		"20240101000000_memo.down.sql": {Data: []byte(dropMemo + ";\n")},
+		"20240102000000_seed.up.sql":   {Data: []byte("-- seed\nINSERT INTO preform_test_a.memo (id, text) VALUES (1, 'a');\nINSERT INTO preform_test_a.memo (id, text) VALUES (2, 'b');\n")},
+		"20240102000000_seed.down.sql": {Data: []byte("DELETE FROM preform_test_a.memo;\n")},
+	}, table)
+	applied, err := migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"20240101000000", "20240102000000"}, applied)
+	applied, err = migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(applied))
+	var cnt int
+	assert.Nil(t, mainModel.PreformTestA.QueryRow("SELECT COUNT(*) FROM preform_test_a.memo").Scan(&cnt))
+	assert.Equal(t, 2, cnt)
+	reverted, err := migrator.Down(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"20240102000000", "20240101000000"}, reverted)
+	applied, err = migrator.Applied(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(applied))
+	_, err = mainModel.PreformTestA.Exec("DROP TABLE " + table)
+	assert.Nil(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	report, err := mainModel.PreformTestA.Verify(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, report.Ok(), report.Error())
+	_, err = mainModel.PreformTestA.Exec(`ALTER TABLE preform_test_a."user" ADD COLUMN verify_extra INTEGER NULL`)
+	assert.Nil(t, err)
+	report, err = mainModel.PreformTestA.Verify(context.Background())
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(report.Drifts)) {
+		assert.Equal(t, preform.DriftExtraColumn, report.Drifts[0].Kind)
+		assert.Equal(t, "verify_extra", report.Drifts[0].Column)
+	}
+	_, err = mainModel.PreformTestA.Exec(`ALTER TABLE preform_test_a."user" DROP COLUMN verify_extra`)
+	assert.Nil(t, err)
+}
+
+func TestUserEach(t *testing.T) {
+	var ids []int32
+	err := mainModel.PreformTestA.User.Select().OrderBy(mainModel.PreformTestA.User.Id.Asc()).Each(func(user *mainModel.UserBody) error {
+		ids = append(ids, user.Id)
+		if len(ids) == 3 {
+			return preform.ErrorStopEach
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, ids)
+
+	cnt := 0
+	err = mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).EachBatch(2).Each(func(user *mainModel.UserBody) error {
+		cnt++
+		if user.Id == 1 {
+			assert.Len(t, user.UserLogs, 2)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, cnt)
+}
+
+func TestUserPageAfter(t *testing.T) {
+	var (
+		f      = mainModel.PreformTestA.User
+		ids    []int32
+		cursor string
+	)
+	for i := 0; i < 5; i++ {
+		users, next, err := f.Select().Limit(2).PageAfter(cursor, f.Id)
+		assert.Nil(t, err)
+		for _, user := range users {
+			ids = append(ids, user.Id)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []int32{1, 2, 3, 4, 5}, ids)
+
+	users, next, err := f.Select().Limit(3).PageAfter("", preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+	assert.Equal(t, int32(3), users[2].Id)
+	users, next, err = f.Select().Limit(3).PageAfter(next, preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, int32(1), users[1].Id)
+	assert.Equal(t, "", next)
+
+	users, next, err = f.Select().Where(f.Id.Gt(1)).Limit(3).PageAfter("", f.CreatedBy, preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+	assert.Equal(t, []int32{4, 2, 5}, []int32{users[0].Id, users[1].Id, users[2].Id})
+	users, _, err = f.Select().Where(f.Id.Gt(1)).Limit(3).PageAfter(next, f.CreatedBy, preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int32(3), users[0].Id)
+
+	_, _, err = f.Select().PageAfter("bad", f.Id)
+	assert.ErrorIs(t, err, preform.ErrorInvalidCursor)
+}
+
 func TestUserSelectRelation(t *testing.T) {
 	user, err := mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).GetOne(1)
 	assert.Nil(t, err)
@@ -311,6 +656,80 @@ func TestUserUpdate(t *testing.T) {
 
 }
 
+func TestUserSoftDelete(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	f.SetSoftDelete(f.LoginedAt)
+	defer f.SetSoftDelete(nil)
+
+	user, err := f.GetOne(4)
+	assert.Nil(t, err)
+	affected, err := user.Delete()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+	_, err = f.GetOne(4)
+	assert.NotNil(t, err)
+	cnt, err := f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), cnt)
+	cnt, err = f.Select().WithTrashed().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	users, err := f.Select().OnlyTrashed().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int32(4), users[0].Id)
+	assert.True(t, users[0].LoginedAt.Valid)
+
+	affected, err = f.Delete().Where(f.Id.Eq(4)).Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), affected)
+	q, _, err := f.Delete().Where(f.Id.Eq(4)).ToSql()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(q, "UPDATE"))
+	q, _, err = f.Delete().ForceDelete().Where(f.Id.Eq(4)).ToSql()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(q, "DELETE"))
+
+	affected, err = f.Update().Set(f.LoginedAt, nil).Where(f.Id.Eq(4)).Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+}
+
+func TestUserVersion(t *testing.T) {
+	var (
+		f        = mainModel.PreformTestA.User
+		cfg      = preform.UpdateConfig{Cols: []preform.ICol{f.Name}}
+		staleErr preform.ErrStaleObject
+	)
+	f.SetVersion(f.CreatedBy)
+	defer f.SetVersion(nil)
+
+	user, err := f.GetOne(4)
+	assert.Nil(t, err)
+	stale, err := f.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), user.CreatedBy)
+	user.Name = "test4-v"
+	affected, err := user.Update(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int32(2), user.CreatedBy)
+
+	stale.Name = "stale"
+	_, err = stale.Update(cfg)
+	assert.ErrorAs(t, err, &staleErr)
+	user, err = f.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, "test4-v", user.Name)
+	assert.Equal(t, int32(2), user.CreatedBy)
+
+	f.SetVersion(nil)
+	user.Name = "test4"
+	user.CreatedBy = 1
+	_, err = user.Update(preform.UpdateConfig{Cols: []preform.ICol{f.Name, f.CreatedBy}})
+	assert.Nil(t, err)
+}
+
 func TestUserDelete(t *testing.T) {
 	affected, err := mainModel.PreformTestA.User.Delete().Where(mainModel.PreformTestA.User.Id.Gt(4)).Exec()
 	assert.Nil(t, err)
@@ -329,6 +748,87 @@ func TestUserDelete(t *testing.T) {
 	assert.Len(t, allUsers, 3)
 }
 
+func TestUserUpsert(t *testing.T) {
+	user, err := mainModel.PreformTestA.User.GetOne(4)
+	assert.Nil(t, err)
+	user.Name = "test4-1"
+	err = mainModel.PreformTestA.User.Upsert(user, preform.UpsertConfig{UpdateCols: []preform.ICol{mainModel.PreformTestA.User.Name}})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), user.Id)
+
+	user, err = mainModel.PreformTestA.User.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, "test4-1", user.Name)
+
+	newUser := &mainModel.UserBody{
+		Name:       "test6",
+		ManagerIds: preformTypes.Array[int32]{1},
+		CreatedBy:  1,
+	}
+	err = mainModel.PreformTestA.User.Upsert(newUser)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(6), newUser.Id)
+
+	allUsers, err := mainModel.PreformTestA.User.Select().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, allUsers, 4)
+
+	existingFoo := &mainModel.FooBody{Fk1: 3, Fk2: 4}
+	err = mainModel.PreformTestA.Foo.Upsert(existingFoo, preform.UpsertConfig{ConflictCols: []preform.ICol{mainModel.PreformTestA.Foo.Fk1, mainModel.PreformTestA.Foo.Fk2}})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), existingFoo.Id)
+}
+
+func TestUserReturning(t *testing.T) {
+	users, err := mainModel.PreformTestA.User.Update().Set(mainModel.PreformTestA.User.Name, "test1").Where(mainModel.PreformTestA.User.Id.Eq(1)).ExecReturning([]preform.ICol{mainModel.PreformTestA.User.Id, mainModel.PreformTestA.User.Name})
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int32(1), users[0].Id)
+	assert.Equal(t, "test1", users[0].Name)
+
+	tx, err := mainModel.PreformTestA.BeginTx(context.Background())
+	assert.Nil(t, err)
+	users, err = mainModel.PreformTestA.User.Update().Set(mainModel.PreformTestA.User.Name, "returning").Where(mainModel.PreformTestA.User.Id.Eq(1)).ExecReturning(nil, tx)
+	assert.Nil(t, err)
+	assert.Equal(t, "returning", users[0].Name)
+	assert.Nil(t, tx.Rollback())
+	user, err := mainModel.PreformTestA.User.GetOne(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "test1", user.Name)
+
+	users, err = mainModel.PreformTestA.User.Delete().Where(mainModel.PreformTestA.User.Id.Eq(6)).ExecReturning(nil)
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "test6", users[0].Name)
+
+	foo := &mainModel.FooBody{Fk1: 7, Fk2: 8}
+	assert.Nil(t, foo.Insert())
+	mainModel.FooHookCalls = nil
+	foos, err := mainModel.PreformTestA.Foo.Delete().Where(mainModel.PreformTestA.Foo.Id.Eq(foo.Id)).ExecReturning(nil)
+	assert.Nil(t, err)
+	assert.Len(t, foos, 1)
+	assert.Equal(t, []string{"AfterDelete"}, mainModel.FooHookCalls)
+}
+
+func TestFooHook(t *testing.T) {
+	var (
+		f   = mainModel.PreformTestA.Foo
+		foo = &mainModel.FooBody{Fk1: 5, Fk2: 6}
+	)
+	mainModel.FooHookCalls = nil
+	assert.Nil(t, foo.Insert())
+	foo.Fk2 = 7
+	_, err := foo.Update()
+	assert.Nil(t, err)
+	_, err = foo.Delete()
+	assert.Nil(t, err)
+	err = f.Insert(&mainModel.FooBody{})
+	assert.ErrorIs(t, err, mainModel.ErrorFooWithoutBar)
+	_, err = f.Update().SetBodies(&mainModel.FooBody{Id: 1, Fk1: 1, Fk2: 2}).Columns(f.Fk1, f.Fk2).Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"BeforeInsert", "AfterInsert", "BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete", "BeforeInsert", "BeforeUpdate", "AfterUpdate"}, mainModel.FooHookCalls)
+}
+
 func TestPrebuildQuery(t *testing.T) {
 	users, err := mainModel.UserAndLog.Select().GetAll()
 	assert.Nil(t, err)
@@ -379,3 +879,70 @@ func TestTesters(t *testing.T) {
 	assert.Equal(t, fakeUUid, users[0].UserLogs[0].Id)
 
 }
+
+func TestCockroachdb(t *testing.T) {
+	dummyDb := preformTestUtil.NewTestDB("cockroachdb")
+	mainModel.Init(dummyDb)
+	queryRunner := preformTestUtil.NewTestQueryRunner()
+	mainModel.PreformTestA.SetConn(dummyDb, queryRunner)
+	d := mainModel.PreformTestA.GetDialect()
+	name, err := d.RestartSavepoint()
+	assert.Nil(t, err)
+	assert.Equal(t, "cockroach_restart", name)
+	_, err = d.ReplicaLag()
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+
+	var (
+		attempts int
+		policy   = &preform.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	)
+	err = mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		assert.NotNil(t, preform.TxFromCtx(ctx))
+		if attempts == 1 {
+			return serializationFailure{}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+
+	tx, err := mainModel.PreformTestA.BeginTx(context.Background())
+	assert.Nil(t, err)
+	attempts = 0
+	queryRunner.ErrorQueue = append(queryRunner.ErrorQueue, nil, serializationFailure{}) //SAVEPOINT then RELEASE fails
+	err = tx.Retry(policy, func(ctx context.Context) error {
+		attempts++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	attempts = 0
+	err = tx.Retry(policy, func(ctx context.Context) error {
+		attempts++
+		return errors.New("not retryable")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Nil(t, tx.Rollback())
+
+	_, err = dialect.NewYugabytedbDialect().RestartSavepoint()
+	assert.Equal(t, dialect.ErrorNotSupport, err)
+}
+
+func TestDetectDialect(t *testing.T) {
+	d := preform.DbFromNative(pgxConn)
+	assert.Nil(t, d.DetectDialect(context.Background()))
+	_, err := d.GetDialect().RestartSavepoint()
+	assert.Equal(t, dialect.ErrorNotSupport, err) //postgresql
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, d.DetectDialect(ctx), context.Canceled)
+
+	dummy := preform.DbFromNative(preformTestUtil.NewTestDB("cockroachdb"))
+	assert.Nil(t, dummy.DetectDialect(ctx)) //picked by the driver name, nothing to query
+	name, err := dummy.GetDialect().RestartSavepoint()
+	assert.Nil(t, err)
+	assert.Equal(t, "cockroach_restart", name)
+}
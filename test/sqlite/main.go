@@ -22,7 +22,7 @@ func main() {
 	fmt.Println("go test----------------------------")
 	d, _ := os.Getwd()
 	p := fmt.Sprintf("%s/model_test", d)
-	cmd := exec.Command("go", "test", p, "-root="+d)
+	cmd := exec.Command("go", "test", "-tags=sqlite_json,sqlite_fts5", p, "-root="+d)
 	cmd.Dir = d
 	out, err := cmd.CombinedOutput()
 	//if err != nil {
@@ -64,6 +64,13 @@ CREATE TABLE preform_test_a.foo (
 	fk INTEGER NOT NULL --fk:preform_test_b.bar.id
 );
 
+CREATE TABLE preform_test_a.post (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	title TEXT NOT NULL,
+	version INTEGER NOT NULL,
+	deleted_at TEXT NULL
+);
+
 CREATE TABLE preform_test_b.bar (
 	id INTEGER PRIMARY KEY NOT NULL
 );
@@ -0,0 +1,78 @@
+package mainModel
+
+import (
+	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/types"
+)
+
+var FilteredUser = preform.IniPrebuildQueryFactory[*FilteredUserFactory, FilteredUserBody](func(d *FilteredUserFactory) {
+	d.User = d.PreformTestASchema.User.SetAlias("User").(*FactoryUser)
+	d.SetSrc(d.User).DefineCols(
+		preform.SetPrebuildQueryCol(d, d.User.Id.SetAlias("UserId"), d.UserId),
+		preform.SetPrebuildQueryCol(d, d.User.Name.SetAlias("UserName"), d.UserName),
+		preform.SetPrebuildQueryCol(d, d.User.CreatedBy.SetAlias("UserCreatedBy"), d.UserCreatedBy),
+		preform.SetPrebuildQueryCol(d, d.User.CreatedAt.SetAlias("UserCreatedAt"), d.UserCreatedAt),
+		preform.SetPrebuildQueryCol(d, d.User.LoginedAt.SetAlias("UserLoginedAt"), d.UserLoginedAt),
+		preform.SetPrebuildQueryCol(d, d.User.Detail.SetAlias("UserDetail"), d.UserDetail),
+		preform.SetPrebuildQueryCol(d, d.User.Config.SetAlias("UserConfig"), d.UserConfig),
+		preform.SetPrebuildQueryCol(d, d.User.ExtraConfig.SetAlias("UserExtraConfig"), d.UserExtraConfig),
+	).
+	PreSetWhere(d.PreformTestASchema.User.Id.In(1, 2, 3, 4)).
+		PreSetWhere(d.PreformTestASchema.User.Id.NotIn([]any{3})).
+		PreSetWhere(d.PreformTestASchema.User.LoginedAt.IsNull()).
+		PreSetWhere(d.PreformTestASchema.User.Name.ILike("TEST%")).
+		PreSetWhere(d.PreformTestASchema.User.CreatedBy.NotBetween(100, 200))
+})
+
+type FilteredUserFactory struct {
+	preform.PrebuildQueryFactory[*FilteredUserFactory, FilteredUserBody]
+	//schema src
+	PreformTestASchema *PreformTestASchema
+
+	//factory src
+	User *FactoryUser
+	
+	//columns
+	UserId *preform.PrebuildQueryCol[int64, preform.NoAggregation]
+	UserName *preform.PrebuildQueryCol[string, preform.NoAggregation]
+	UserCreatedBy *preform.PrebuildQueryCol[int64, preform.NoAggregation]
+	UserCreatedAt *preform.PrebuildQueryCol[preformTypes.SqliteTime, preform.NoAggregation]
+	UserLoginedAt *preform.PrebuildQueryCol[preformTypes.Null[preformTypes.SqliteTime], preform.NoAggregation]
+	UserDetail *preform.PrebuildQueryCol[preformTypes.Null[preformTypes.JsonRaw[interface {}]], preform.NoAggregation]
+	UserConfig *preform.PrebuildQueryCol[preformTypes.JsonRaw[interface {}], preform.NoAggregation]
+	UserExtraConfig *preform.PrebuildQueryCol[preformTypes.JsonRaw[interface {}], preform.NoAggregation]
+}
+
+type FilteredUserBody struct {
+	preform.QueryBody[FilteredUserBody, *FilteredUserFactory]
+	UserId int64 `db:"UserId" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	UserName string `db:"UserName" json:"Name" dataType:"TEXT"`
+	UserCreatedBy int64 `db:"UserCreatedBy" json:"CreatedBy" dataType:"INTEGER"`
+	UserCreatedAt preformTypes.SqliteTime `db:"UserCreatedAt" json:"CreatedAt" dataType:"datetime" comment:"type:datetime"`
+	UserLoginedAt preformTypes.Null[preformTypes.SqliteTime] `db:"UserLoginedAt" json:"LoginedAt" dataType:"datetime" comment:"type:datetime"`
+	UserDetail preformTypes.Null[preformTypes.JsonRaw[interface {}]] `db:"UserDetail" json:"Detail" dataType:"jsonb" defaultValue:"NULL"`
+	UserConfig preformTypes.JsonRaw[interface {}] `db:"UserConfig" json:"Config" dataType:"jsonb"`
+	UserExtraConfig preformTypes.JsonRaw[interface {}] `db:"UserExtraConfig" json:"ExtraConfig" dataType:"jsonb"`
+}
+
+func (m FilteredUserBody) Factory() *FilteredUserFactory { return FilteredUser }
+
+func (m *FilteredUserBody) FieldValuePtr(pos int) any { 
+	switch pos {
+		case 0: return &m.UserId
+		case 1: return &m.UserName
+		case 2: return &m.UserCreatedBy
+		case 3: return &m.UserCreatedAt
+		case 4: return &m.UserLoginedAt
+		case 5: return &m.UserDetail
+		case 6: return &m.UserConfig
+		case 7: return &m.UserExtraConfig
+	}
+	return nil
+}
+
+func (m *FilteredUserBody) FieldValuePtrs() []any { 
+	return []any{&m.UserId, &m.UserName, &m.UserCreatedBy, &m.UserCreatedAt, &m.UserLoginedAt, &m.UserDetail, &m.UserConfig, &m.UserExtraConfig}
+}
+
+
@@ -11,10 +11,11 @@ type PreformTestASchema struct {
 	User *FactoryUser
 	UserLog *FactoryUserLog
 	Foo *FactoryFoo
+	Post *FactoryPost
 }
 
 func (s *PreformTestASchema) Factories() []preform.IFactory {
-	return []preform.IFactory{s.User, s.UserLog, s.Foo} 
+	return []preform.IFactory{s.User, s.UserLog, s.Foo, s.Post} 
 }
 
 func (s *PreformTestASchema) Clone(name string, db...*sql.DB) preform.ISchema {
@@ -49,6 +50,7 @@ func initPreformTestA(conn *sql.DB, name string, queryRunnerForTest ... preformS
 	s.User = userInit()
 	s.UserLog = userLogInit()
 	s.Foo = fooInit()
+	s.Post = postInit()
 	if name == "" {
 		name = "preform_test_a"
 	}
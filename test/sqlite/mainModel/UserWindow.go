@@ -0,0 +1,53 @@
+package mainModel
+
+import (
+	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/types"
+)
+
+var UserWindow = preform.IniPrebuildQueryFactory[*UserWindowFactory, UserWindowBody](func(d *UserWindowFactory) {
+	d.User = d.PreformTestASchema.User.SetAlias("User").(*FactoryUser)
+	d.SetSrc(d.User).DefineCols(
+		preform.SetPrebuildQueryCol(d, d.User.Id.SetAlias("UserId"), d.UserId),
+		preform.SetPrebuildQueryCol(d, d.User.Id.SetAlias("Rn").RowNumber().Over(preform.OrderBy(preform.OrderDesc(d.User.Id))).SetAlias("Rn"), d.Rn),
+		preform.SetPrebuildQueryCol(d, d.User.Id.SetAlias("PrevId").Lag(1).Over(preform.OrderBy(d.User.Id)).SetAlias("PrevId"), d.PrevId),
+	)
+})
+
+type UserWindowFactory struct {
+	preform.PrebuildQueryFactory[*UserWindowFactory, UserWindowBody]
+	//schema src
+	PreformTestASchema *PreformTestASchema
+
+	//factory src
+	User *FactoryUser
+	
+	//columns
+	UserId *preform.PrebuildQueryCol[int64, preform.NoAggregation]
+	Rn *preform.PrebuildQueryCol[int64, preform.NoAggregation]
+	PrevId *preform.PrebuildQueryCol[preformTypes.Null[int64], preform.NoAggregation]
+}
+
+type UserWindowBody struct {
+	preform.QueryBody[UserWindowBody, *UserWindowFactory]
+	UserId int64 `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	Rn int64 `db:"Rn"`
+	PrevId preformTypes.Null[int64] `db:"PrevId"`
+}
+
+func (m UserWindowBody) Factory() *UserWindowFactory { return UserWindow }
+
+func (m *UserWindowBody) FieldValuePtr(pos int) any { 
+	switch pos {
+		case 0: return &m.UserId
+		case 1: return &m.Rn
+		case 2: return &m.PrevId
+	}
+	return nil
+}
+
+func (m *UserWindowBody) FieldValuePtrs() []any { 
+	return []any{&m.UserId, &m.Rn, &m.PrevId}
+}
+
+
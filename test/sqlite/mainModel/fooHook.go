@@ -0,0 +1,45 @@
+package mainModel
+
+import (
+	"context"
+	"errors"
+	"github.com/go-preform/preform"
+)
+
+var (
+	ErrorFooWithoutBar = errors.New("foo without bar")
+	FooHookCalls       []string
+)
+
+func (m *FooBody) BeforeInsert(ctx context.Context, tx *preform.Tx) error {
+	FooHookCalls = append(FooHookCalls, "BeforeInsert")
+	if m.Fk == 0 {
+		return ErrorFooWithoutBar
+	}
+	return nil
+}
+
+func (m *FooBody) AfterInsert(ctx context.Context, tx *preform.Tx) error {
+	FooHookCalls = append(FooHookCalls, "AfterInsert")
+	return nil
+}
+
+func (m *FooBody) BeforeUpdate(ctx context.Context, tx *preform.Tx) error {
+	FooHookCalls = append(FooHookCalls, "BeforeUpdate")
+	return nil
+}
+
+func (m *FooBody) AfterUpdate(ctx context.Context, tx *preform.Tx) error {
+	FooHookCalls = append(FooHookCalls, "AfterUpdate")
+	return nil
+}
+
+func (m *FooBody) BeforeDelete(ctx context.Context, tx *preform.Tx) error {
+	FooHookCalls = append(FooHookCalls, "BeforeDelete")
+	return nil
+}
+
+func (m *FooBody) AfterDelete(ctx context.Context, tx *preform.Tx) error {
+	FooHookCalls = append(FooHookCalls, "AfterDelete")
+	return nil
+}
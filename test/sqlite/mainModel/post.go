@@ -0,0 +1,79 @@
+package mainModel
+
+import (
+	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/types"
+)
+
+var postInit = preform.InitFactory[*FactoryPost, PostBody](func(s *PreformTestASchema) {
+	preform.SetColumn(s.Post.Id.Column).AutoIncrement()
+	s.Post.SetSoftDelete(s.Post.DeletedAt)
+	s.Post.SetVersion(s.Post.Version)
+	s.Post.SetTableName("post")
+})
+
+type FactoryPost struct {
+	preform.Factory[*FactoryPost, PostBody]
+	Id *preform.PrimaryKey[int64] `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	Title *preform.Column[string] `db:"title" json:"Title" dataType:"TEXT"`
+	Version *preform.Column[int64] `db:"version" json:"Version" dataType:"INTEGER"`
+	DeletedAt *preform.Column[preformTypes.Null[string]] `db:"deleted_at" json:"DeletedAt" dataType:"TEXT"`
+}
+
+func (f FactoryPost) CloneInstance(factory preform.IFactory) preform.IFactory {
+	var (
+		ff = f
+		cols = factory.Columns()
+	)
+	ff.Factory = *factory.(*preform.Factory[*FactoryPost, PostBody])
+	ff.Factory.Definition = &ff
+	ff.Id = cols[0].(*preform.PrimaryKey[int64] )
+	ff.Title = cols[1].(*preform.Column[string] )
+	ff.Version = cols[2].(*preform.Column[int64] )
+	ff.DeletedAt = cols[3].(*preform.Column[preformTypes.Null[string]] )
+	return ff.Factory.Definition
+}
+
+
+type PostBody struct {
+	preform.Body[PostBody,*FactoryPost]
+	Id int64 `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	Title string `db:"title" json:"Title" dataType:"TEXT"`
+	Version int64 `db:"version" json:"Version" dataType:"INTEGER"`
+	DeletedAt preformTypes.Null[string] `db:"deleted_at" json:"DeletedAt" dataType:"TEXT"`
+}
+
+func (m PostBody) Factory() *FactoryPost { return m.Body.Factory(PreformTestA.Post) }
+
+func (m *PostBody) Insert(cfg ... preform.EditConfig) error { return PreformTestA.Post.Insert(m, cfg...) }
+
+func (m *PostBody) Update(cfg ... preform.UpdateConfig) (affected int64, err error) { return PreformTestA.Post.UpdateByPk(m, cfg...) }
+
+func (m *PostBody) Delete(cfg ... preform.EditConfig) (affected int64, err error) { return PreformTestA.Post.DeleteByPk(m, cfg...) }
+
+func (m PostBody) FieldValueImmutablePtrs() []any { return []any{&m.Id, &m.Title, &m.Version, &m.DeletedAt} }
+
+func (m *PostBody) FieldValuePtr(pos int) any { 
+	switch pos {
+		case 0: return &m.Id
+		case 1: return &m.Title
+		case 2: return &m.Version
+		case 3: return &m.DeletedAt
+	}
+	return nil
+}
+
+func (m *PostBody) FieldValuePtrs() []any { 
+	return []any{&m.Id, &m.Title, &m.Version, &m.DeletedAt}
+}
+
+func (m *PostBody) RelatedValuePtrs() []any { return []any{} }
+
+
+func (m *PostBody) RelatedByPos(pos uint32, toSet ...any) bool {
+	return false
+}
+
+
+
+
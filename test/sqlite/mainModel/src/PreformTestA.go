@@ -34,11 +34,20 @@ type PreformTestA_foo struct {
 	Fk	preformBuilder.ForeignKeyDef[int64] `db:"fk" json:"Fk" dataType:"INTEGER" comment:"fk:preform_test_b.bar.id"`
 }
 
+type PreformTestA_post struct {
+	preformBuilder.FactoryBuilder[*PreformTestA_post]
+	Id	preformBuilder.PrimaryKeyDef[int64] `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	Title	preformBuilder.ColumnDef[string] `db:"title" json:"Title" dataType:"TEXT"`
+	Version	preformBuilder.ColumnDef[int64] `db:"version" json:"Version" dataType:"INTEGER"`
+	DeletedAt	preformBuilder.ColumnDef[preformTypes.Null[string]] `db:"deleted_at" json:"DeletedAt" dataType:"TEXT"`
+}
+
 type PreformTestASchema struct {
 	name string
 	user *PreformTestA_user
 	userLog *PreformTestA_userLog
 	foo *PreformTestA_foo
+	post *PreformTestA_post
 }
 
 var (
@@ -64,12 +73,17 @@ func initPreformTestA() (string, []preformShare.IFactoryBuilder, *PreformTestASc
 		d.SetTableName("foo")
 		d.Fk.SetAssociatedKey(PreformTestB.bar.Id, preformBuilder.FkName("comment_fk_0"))
 	})
+	
+	PreformTestA.post = preformBuilder.InitFactoryBuilder(PreformTestA.name, func(d *PreformTestA_post) {
+		d.SetTableName("post")
+	})
 
 	return "preform_test_a",
 		[]preformShare.IFactoryBuilder{
 			PreformTestA.user,
 			PreformTestA.userLog,
 			PreformTestA.foo,
+			PreformTestA.post,
 		},
 		&PreformTestA,
 		map[string][]string{},
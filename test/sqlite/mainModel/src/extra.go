@@ -11,6 +11,27 @@ func init() {
 		builder.From(pta.user).InnerJoinByForeignKey(pta.userLog.UserId).Where(pta.userLog.UserId.NotEq(2))
 		return builder
 	}))
+	PrebuildQueries = append(PrebuildQueries, preformBuilder.BuildQuery("user_tree", func(builder *preformBuilder.QueryBuilder, pta *PreformTestASchema) *preformBuilder.QueryBuilder {
+		builder.WithRecursive(pta.user, pta.user.Id.Eq(2), pta.user.CreatedBy).From(pta.user)
+		return builder
+	}))
+	PrebuildQueries = append(PrebuildQueries, preformBuilder.BuildQuery("filtered_user", func(builder *preformBuilder.QueryBuilder, pta *PreformTestASchema) *preformBuilder.QueryBuilder {
+		builder.From(pta.user).
+			Where(pta.user.Id.In(1, 2, 3, 4)).
+			Where(pta.user.Id.NotIn([]int64{3})).
+			Where(pta.user.LoginedAt.IsNull()).
+			Where(pta.user.Name.ILike("TEST%")).
+			Where(pta.user.CreatedBy.NotBetween(100, 200))
+		return builder
+	}))
+	PrebuildQueries = append(PrebuildQueries, preformBuilder.BuildQuery("user_window", func(builder *preformBuilder.QueryBuilder, pta *PreformTestASchema) *preformBuilder.QueryBuilder {
+		builder.From(pta.user).Cols(
+			pta.user.Id,
+			pta.user.Id.RowNumber().Over(preformBuilder.OrderByDesc(pta.user.Id)).SetAlias("Rn"),
+			pta.user.Id.Lag(1).Over(preformBuilder.OrderBy(pta.user.Id)).SetAlias("PrevId"),
+		)
+		return builder
+	}))
 }
 
 func (p *PreformTestA_user) Setup() (skipAutoSetter bool) {
@@ -20,6 +41,12 @@ func (p *PreformTestA_user) Setup() (skipAutoSetter bool) {
 	return false
 }
 
+func (p *PreformTestA_post) Setup() (skipAutoSetter bool) {
+	p.SetSoftDelete(p.DeletedAt)
+	p.SetVersion(p.Version)
+	return false
+}
+
 func (p *PreformTestA_userLog) Setup() (skipAutoSetter bool) {
 	p.SetTableName("user_log")
 	p.Id.RelatedFk(&PreformTestA.userLog.RelatedLogId)
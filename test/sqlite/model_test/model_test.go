@@ -2,32 +2,43 @@ package model_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/go-preform/preform"
+	"github.com/go-preform/preform/cachedQueryRunner"
+	"github.com/go-preform/preform/dialect"
+	"github.com/go-preform/preform/preformBuilder"
+	preformShare "github.com/go-preform/preform/share"
 	"github.com/go-preform/preform/test/sqlite/config"
 	"github.com/go-preform/preform/test/sqlite/mainModel"
 	"github.com/go-preform/preform/test/sqlite/mainModel/src/types"
 	preformTestUtil "github.com/go-preform/preform/testUtil"
 	preformTracer "github.com/go-preform/preform/tracer"
 	preformTypes "github.com/go-preform/preform/types"
+	"github.com/go-preform/squirrel"
+	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
 var (
 	conn *sql.DB
+	wd   string
 )
 
 func TestMain(m *testing.M) {
 	wdd := flag.String("root", "", "")
 	flag.Parse()
-	wd, _ := os.Getwd()
+	wd, _ = os.Getwd()
 	if wdd != nil && *wdd != "" {
 		wd = *wdd
 	}
@@ -188,6 +199,708 @@ func TestUserSelect(t *testing.T) {
 
 }
 
+func TestUserCondition(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		users, err := f.Select().Where(f.Id.In(1, 3)).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		users, err = f.Select().Where(f.Id.NotIn([]int64{1, 2})).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 3)
+		users, err = f.Select().Where(f.Id.In(f.Select(f.Id).Where(f.Id.Lt(3)))).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		users, err = f.Select().Where(f.Id.NotIn(f.Select(f.Id).Where(f.Id.Lt(3)))).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 3)
+		users, err = f.Select().Where(f.Detail.IsNull()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 4)
+		users, err = f.Select().Where(f.Detail.IsNotNull()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		users, err = f.Select().Where(f.Name.ILike("TEST%")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 5)
+		users, err = f.Select().Where(f.Name.NotLike("test1%")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 4)
+		users, err = f.Select().Where(f.Id.NotBetween(2, 4)).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+	})
+}
+
+func TestUserJson(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		users, err := f.Select().Where(preform.JsonField(f.Detail, func(d *types.UserDetail) *uint32 { return &d.Age }).Gt(10)).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int64(1), users[0].Id)
+		users, err = f.Select().Where(f.Detail.JsonHasKey("Age")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		users, err = f.Select().Where(f.Detail.JsonHasKey("Name")).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+		rows, err := f.Select(f.Id, preform.JsonPathOf[int64](f.Detail, "Age")).OrderBy(f.Id.Asc()).QueryRaw()
+		assert.Nil(t, err)
+		assert.Len(t, rows.Rows, 5)
+		assert.EqualValues(t, 11, rows.Rows[0][1])
+		assert.EqualValues(t, 0, rows.Rows[1][1])
+	})
+}
+
+func TestUserFullText(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		rank := f.Name.MatchRank("red shoes").SetAlias("Rank")
+		q, args, err := f.Select(f.Id, rank).Where(f.Name.Match("red shoes")).OrderBy(rank.Desc()).ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, `-bm25("User") AS "Rank"`)
+		assert.Contains(t, q, `WHERE "User"."name" MATCH $1`)
+		assert.Contains(t, q, `ORDER BY "Rank" DESC`)
+		assert.Equal(t, []any{"red shoes"}, args)
+	})
+}
+
+func TestUserWindow(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		rows, err := f.Select(
+			f.Id,
+			f.Id.RowNumber().Over(preform.OrderBy(f.Id.Desc())).SetAlias("Rn"),
+			f.Id.Lag(1).Over(preform.OrderBy(f.Id)).SetAlias("PrevId"),
+			f.Id.RowNumber().Over(preform.PartitionBy(f.CreatedBy), preform.OrderBy(f.Id)).SetAlias("SiblingNo"),
+			f.Id.Aggregate(dialect.AggMax).Over(preform.OrderBy(f.Id), preform.RowsBetween(preform.Preceding(1), preform.Following(1))).SetAlias("NextMax"),
+		).OrderBy(f.Id.Asc()).QueryRaw()
+		assert.Nil(t, err)
+		assert.Len(t, rows.Rows, 5)
+		assert.EqualValues(t, 5, rows.Rows[0][1])
+		assert.EqualValues(t, 1, rows.Rows[4][1])
+		assert.Equal(t, preformTypes.Null[int64]{}, *rows.Rows[0][2].(*preformTypes.Null[int64]))
+		assert.Equal(t, preformTypes.NewNull[int64](1), *rows.Rows[1][2].(*preformTypes.Null[int64]))
+		assert.EqualValues(t, 1, rows.Rows[1][3])
+		assert.EqualValues(t, 2, rows.Rows[3][3])
+		assert.EqualValues(t, 4, rows.Rows[2][4])
+		assert.EqualValues(t, 5, rows.Rows[4][4])
+
+		wrapped := f.Id.SetAlias("Uid")
+		rows, err = f.Select(
+			f.Id,
+			wrapped.Lag(1).Over(preform.OrderBy(f.Id)).SetAlias("PrevUid"),
+			wrapped.Lead(1).Over(preform.OrderBy(f.Id)).SetAlias("NextUid"),
+		).OrderBy(f.Id.Asc()).QueryRaw()
+		assert.Nil(t, err)
+		assert.Len(t, rows.Rows, 5)
+		assert.Equal(t, preformTypes.Null[int64]{}, *rows.Rows[0][1].(*preformTypes.Null[int64]))
+		assert.Equal(t, preformTypes.NewNull[int64](1), *rows.Rows[1][1].(*preformTypes.Null[int64]))
+		assert.Equal(t, preformTypes.NewNull[int64](5), *rows.Rows[3][2].(*preformTypes.Null[int64]))
+		assert.Equal(t, preformTypes.Null[int64]{}, *rows.Rows[4][2].(*preformTypes.Null[int64]))
+	})
+
+	q, _, err := mainModel.UserWindow.Select().ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `ROW_NUMBER() OVER (ORDER BY "User"."id" DESC) AS "Rn"`)
+	assert.Contains(t, q, `LAG("User"."id",1) OVER (ORDER BY "User"."id") AS "PrevId"`)
+	windows, err := mainModel.UserWindow.Select().OrderBy(mainModel.UserWindow.UserId.Asc()).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, windows, 5)
+	assert.Equal(t, int64(5), windows[0].Rn)
+	assert.False(t, windows[0].PrevId.Valid)
+	assert.Equal(t, preformTypes.NewNull[int64](windows[0].UserId), windows[1].PrevId)
+}
+
+func TestUserSetOperation(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		users, err := f.Select().Where(f.Id.Eq(1)).Union(f.Select().Where(f.Id.Eq(2))).OrderBy(f.Id.Desc()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, int64(2), users[0].Id)
+		assert.Equal(t, int64(1), users[1].Id)
+
+		users, err = f.Select().Where(f.Id.Lt(4)).Except(f.Select().Where(f.Id.Eq(2))).OrderBy(f.Id.Asc()).Limit(1).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int64(1), users[0].Id)
+
+		right := f.Select().Where(f.Id.Gt(1))
+		users, err = f.Select().Where(f.Id.Lt(3)).Intersect(right).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int64(2), users[0].Id)
+		cnt, err := right.Count()
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(4), cnt)
+
+		// ordered or paged operands are wrapped as sub-selects
+		users, err = f.Select().OrderBy(f.Id.Asc()).Limit(1).UnionAll(f.Select().OrderBy(f.Id.Desc()).Limit(1).Offset(1)).OrderBy(f.Id.Asc()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, int64(1), users[0].Id)
+		assert.Equal(t, int64(4), users[1].Id)
+	})
+}
+
+func TestUserWith(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		child := f.SetAlias("Child").(*mainModel.FactoryUser)
+		users, err := f.Select().WithRecursive(f.Alias(), f.Select().Where(f.Id.Eq(2)), child.Select().CrossJoin(`"User"`).Where(child.CreatedBy.Eq(f.Id))).OrderBy(f.Id.Asc()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 3)
+		assert.Equal(t, int64(2), users[0].Id)
+		assert.Equal(t, int64(3), users[1].Id)
+		assert.Equal(t, int64(5), users[2].Id)
+
+		// every row joins itself again, only ends as duplicates are dropped
+		users, err = f.Select().WithRecursiveDistinct(f.Alias(), f.Select().Where(f.Id.Eq(2)), child.Select().CrossJoin(`"User"`).Where(squirrel.Or{child.CreatedBy.Eq(f.Id), child.Id.Eq(f.Id)})).OrderBy(f.Id.Asc()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 3)
+	})
+
+	users, err := mainModel.UserTree.Select().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+}
+
+func TestUserLock(t *testing.T) {
+	_, err := mainModel.PreformTestA.User.Select().ForUpdate().GetAll()
+	assert.Equal(t, preform.ErrorLockWithoutTx, err)
+
+	tx, err := mainModel.PreformTestA.BeginTx(context.Background())
+	assert.Nil(t, err)
+	users, err := mainModel.PreformTestA.User.Select().Where(mainModel.PreformTestA.User.Id.Eq(1)).Tx(tx).ForUpdate().SkipLocked().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	cnt, err := mainModel.PreformTestA.User.Select().Tx(tx).ForShare().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	assert.Nil(t, tx.Rollback())
+}
+
+func TestTxSavepoint(t *testing.T) {
+	var (
+		f   = mainModel.PreformTestA.User
+		ctx = context.Background()
+	)
+	tx, err := mainModel.PreformTestA.BeginTx(ctx)
+	assert.Nil(t, err)
+	nested, err := tx.BeginTx(ctx)
+	assert.Nil(t, err)
+	_, err = f.Update().Set(f.Name, "savepoint").Where(f.Id.Eq(1)).Exec(nested)
+	assert.Nil(t, err)
+	assert.Nil(t, nested.Rollback())
+
+	nested, err = tx.BeginTx(ctx)
+	assert.Nil(t, err)
+	_, err = f.Update().Set(f.Name, "savepoint").Where(f.Id.Eq(2)).Exec(nested)
+	assert.Nil(t, err)
+	assert.Nil(t, nested.Commit())
+	assert.ErrorIs(t, nested.Commit(), preform.ErrorTxDone)
+
+	users, err := f.Select().Tx(tx).Where(f.Name.Eq("savepoint")).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int64(2), users[0].Id)
+
+	_, err = tx.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	assert.ErrorIs(t, err, preform.ErrorNestedTxOptions)
+	nested, err = mainModel.PreformTestA.BeginTx(preform.ContextWithTx(ctx, tx))
+	assert.Nil(t, err)
+	_, err = f.Update().Set(f.Name, "savepoint").Where(f.Id.Eq(3)).Exec(nested)
+	assert.Nil(t, err)
+	assert.Nil(t, nested.Rollback())
+	cnt, err := f.Select().Tx(tx).Where(f.Name.Eq("savepoint")).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), cnt)
+	assert.Nil(t, tx.Rollback())
+
+	users, err = f.Select().Where(f.Name.Eq("savepoint")).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 0)
+}
+
+func TestWithTx(t *testing.T) {
+	var (
+		f        = mainModel.PreformTestA.User
+		errAbort = errors.New("abort")
+	)
+	err := mainModel.PreformTestA.WithTx(context.Background(), func(ctx context.Context) error {
+		_, err := f.Update().Ctx(ctx).Set(f.Name, "withTx").Where(f.Id.Eq(1)).Exec()
+		assert.Nil(t, err)
+		return mainModel.PreformTestA.WithTx(ctx, func(ctx context.Context) error {
+			assert.NotNil(t, preform.TxFromCtx(ctx))
+			user, err := f.Select().Ctx(ctx).GetOne(1)
+			assert.Nil(t, err)
+			assert.Equal(t, "withTx", user.Name)
+			return errAbort
+		})
+	})
+	assert.ErrorIs(t, err, errAbort)
+	user, err := f.GetOne(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "test1", user.Name)
+}
+
+func TestWithTxRetry(t *testing.T) {
+	var (
+		attempts int
+		policy   = &preform.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+		writer   = bytes.NewBuffer(nil)
+	)
+	// Retry is an optional IRetryTracer, the chain passes it on to the tracers implementing it
+	mainModel.PreformTestA.SetTracerToDb(preformTracer.NewChainTracer(preformTracer.NewZeroLogTracer(zerolog.New(writer), 7, 0)))
+	defer mainModel.PreformTestA.SetTracerToDb(preformTracer.NewChainTracer(preformTracer.NewZeroLogTracer(zerolog.New(zerolog.NewConsoleWriter()), 7, 0)))
+	err := mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("database is locked")
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Contains(t, writer.String(), `"attempt":1`)
+	assert.NotContains(t, writer.String(), `"attempt":2`)
+	attempts = 0
+	err = mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return errors.New("database is locked")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, attempts)
+	attempts = 0
+	err = mainModel.PreformTestA.WithTxRetry(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		return errors.New("not retryable")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// per ctx, WithTx and BeginTx take the policy over the default one
+	mainModel.PreformTestA.Db().SetRetryPolicy(policy)
+	defer mainModel.PreformTestA.Db().SetRetryPolicy(nil)
+	attempts = 0
+	err = mainModel.PreformTestA.WithTx(preform.ContextWithRetryPolicy(context.Background(), &preform.RetryPolicy{MaxAttempts: 2}), func(ctx context.Context) error {
+		attempts++
+		return errors.New("database is locked")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, attempts)
+	attempts = 0
+	err = mainModel.PreformTestA.WithTx(preform.ContextWithRetryPolicy(context.Background(), nil), func(ctx context.Context) error {
+		attempts++
+		return errors.New("database is locked")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+	tx, err := mainModel.PreformTestA.BeginTx(context.Background())
+	assert.Nil(t, err)
+	assert.Same(t, policy, tx.RetryPolicy())
+	assert.Nil(t, tx.Rollback())
+	ctxPolicy := &preform.RetryPolicy{MaxAttempts: 2}
+	tx, err = mainModel.PreformTestA.BeginTx(preform.ContextWithRetryPolicy(context.Background(), ctxPolicy))
+	assert.Nil(t, err)
+	assert.Same(t, ctxPolicy, tx.RetryPolicy())
+	assert.Nil(t, tx.Rollback())
+}
+
+func TestReplica(t *testing.T) {
+	var (
+		f       = mainModel.PreformTestA.User
+		replica = config.InitDb(wd)
+	)
+	replica.SetMaxOpenConns(1)
+	_, err := replica.Exec(fmt.Sprintf("attach database '%s/preform_test_a.db' as 'preform_test_a';", wd))
+	assert.Nil(t, err)
+	mainModel.PreformTestA.SetReplicas(replica)
+	defer mainModel.PreformTestA.SetReplicas()
+	cnt, err := f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			mainModel.PreformTestA.SetReplicas()
+			mainModel.PreformTestA.SetReplicas(replica)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		cnt, err = f.Select().Count()
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(5), cnt)
+	}
+	<-done
+	assert.Nil(t, replica.Close())
+	_, err = f.Select().Count()
+	assert.NotNil(t, err)
+	cnt, err = f.Select().Primary().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	user, err := f.Select().Ctx(preform.ContextWithPrimary(context.Background())).Eager(f.UserLogs).GetOne(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(user.UserLogs))
+	mainModel.PreformTestA.SetReplicaHealthCheck(time.Millisecond, 0)
+	defer mainModel.PreformTestA.SetReplicaHealthCheck(0, 0)
+	time.Sleep(50 * time.Millisecond)
+	cnt, err = f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+}
+
+func TestReplicaQueryRunner(t *testing.T) {
+	var (
+		a       = mainModel.PreformTestA
+		f       = a.User
+		replica = config.InitDb(wd)
+	)
+	replica.SetMaxOpenConns(1)
+	_, err := replica.Exec(fmt.Sprintf("attach database '%s/preform_test_a.db' as 'preform_test_a';", wd))
+	assert.Nil(t, err)
+	defer func() {
+		a.SetConn(conn)
+		a.SetTracerToDb(preformTracer.NewChainTracer(preformTracer.NewZeroLogTracer(zerolog.New(zerolog.NewConsoleWriter()), 7, 0)))
+	}()
+	a.SetConn(conn, cachedQueryRunner.NewUnsafeCachedQueryRunner(sqlx.NewDb(conn, "sqlite3")))
+	a.SetReplicas(replica)
+	defer a.SetReplicas()
+	cnt, err := f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	// the replica reads through the cache of the primary runner
+	assert.Nil(t, replica.Close())
+	cnt, err = f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	assert.Panics(t, func() {
+		a.SetConn(conn, preform.DbFromNative(conn))
+		a.SetReplicas(replica)
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		memo  = &preformShare.Table{Name: "memo", Scheme: &preformShare.Scheme{Name: "preform_test_a"}}
+		id    = &preformShare.Column{Name: "id", Type: "INTEGER", IsPrimaryKey: true, PkPos: 1, Table: memo}
+		text  = &preformShare.Column{Name: "text", Type: "TEXT", Table: memo}
+		table = "preform_test_a.preform_migration_test"
+	)
+	memo.Columns = []*preformShare.Column{id, text}
+	memo.ColumnByName = map[string]*preformShare.Column{"id": id, "text": text}
+	createMemo, err := mainModel.PreformTestA.GetDialect().Ddl(preformShare.Ddl{Kind: preformShare.DdlCreateTable, Table: memo})
+	assert.Nil(t, err)
+	dropMemo, err := mainModel.PreformTestA.GetDialect().Ddl(preformShare.Ddl{Kind: preformShare.DdlDropTable, Table: memo})
+	assert.Nil(t, err)
+	migrator := mainModel.PreformTestA.NewMigrator(fstest.MapFS{
+		"20240101000000_memo.up.sql":   {Data: []byte(createMemo + ";\n")},
+		"20240101000000_memo.down.sql": {Data: []byte(dropMemo + ";\n")},
+		"20240102000000_seed.up.sql":   {Data: []byte("-- seed\nINSERT INTO preform_test_a.memo (id, text) VALUES (1, 'a');\nINSERT INTO preform_test_a.memo (id, text) VALUES (2, 'b');\n")},
+		"20240102000000_seed.down.sql": {Data: []byte("DELETE FROM preform_test_a.memo;\n")},
+	}, table)
+	applied, err := migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"20240101000000", "20240102000000"}, applied)
+	applied, err = migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(applied))
+	var cnt int
+	assert.Nil(t, mainModel.PreformTestA.QueryRow("SELECT COUNT(*) FROM preform_test_a.memo").Scan(&cnt))
+	assert.Equal(t, 2, cnt)
+	reverted, err := migrator.Down(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"20240102000000", "20240101000000"}, reverted)
+	applied, err = migrator.Applied(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(applied))
+	_, err = mainModel.PreformTestA.Exec("DROP TABLE " + table)
+	assert.Nil(t, err)
+}
+
+type migrationTeam struct {
+	preformBuilder.FactoryBuilder[*migrationTeam]
+	Id   preformBuilder.PrimaryKeyDef[int64] `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	Name preformBuilder.ColumnDef[string]    `db:"name" json:"Name" dataType:"TEXT"`
+}
+
+type migrationMember struct {
+	preformBuilder.FactoryBuilder[*migrationMember]
+	Id     preformBuilder.PrimaryKeyDef[int64]                 `db:"id" json:"Id" dataType:"INTEGER" autoKey:"true"`
+	TeamId preformBuilder.ForeignKeyDef[int64]                 `db:"team_id" json:"TeamId" dataType:"INTEGER"`
+	Role   preformBuilder.ColumnDef[preformTypes.Null[string]] `db:"role" json:"Role" dataType:"TEXT"`
+	Status preformBuilder.ColumnDef[string]                    `db:"status" json:"Status" dataType:"member_status"`
+}
+
+// migrationSchema member listed before the team it references
+func migrationSchema(t *testing.T, name string) preformBuilder.MigrationSchema {
+	var (
+		team = preformBuilder.InitFactoryBuilder(name, func(d *migrationTeam) {
+			d.SetTableName("team")
+		})
+		member = preformBuilder.InitFactoryBuilder(name, func(d *migrationMember) {
+			d.SetTableName("member")
+			d.TeamId.SetAssociatedKey(team.Id)
+		})
+		factories = []preformShare.IFactoryBuilder{member, team}
+	)
+	preformBuilder.PrepareSchema("migration", t.TempDir(), name, name, factories)
+	return preformBuilder.MigrationSchema{Name: name, Factories: factories, Enums: map[string][]string{"member_status": {"active", "left"}}}
+}
+
+func TestDiffMigration(t *testing.T) {
+	var (
+		schema = migrationSchema(t, "preform_migration")
+		pg     = dialect.NewPostgresqlDialect()
+	)
+	up, down, err := preformBuilder.DiffMigration(pg, nil, schema)
+	assert.Nil(t, err)
+	assertMigration(t, []string{
+		`CREATE TYPE "preform_migration"."member_status" AS ENUM ('active', 'left');`,
+		`CREATE TABLE "preform_migration"."team" (`, // referenced table first
+		`CREATE TABLE "preform_migration"."member" (`,
+	}, up)
+	assertMigration(t, []string{
+		`DROP TABLE "preform_migration"."member";`,
+		`DROP TABLE "preform_migration"."team";`,
+		`DROP TYPE "preform_migration"."member_status";`,
+	}, down)
+	assert.Contains(t, up[2], `CONSTRAINT "fk_member_team_id_id" FOREIGN KEY ("team_id") REFERENCES "preform_migration"."team" ("id")`)
+
+	var (
+		scheme = &preformShare.Scheme{Name: "preform_migration", Enums: map[string][]string{"old_status": {"a"}}}
+		member = &preformShare.Table{Name: "member", Scheme: scheme, ColumnByName: map[string]*preformShare.Column{}, ForeignKeys: map[string]*preformShare.ForeignKey{}}
+		legacy = &preformShare.Table{Name: "legacy", Scheme: scheme, ColumnByName: map[string]*preformShare.Column{}, ForeignKeys: map[string]*preformShare.ForeignKey{}}
+	)
+	for _, col := range []*preformShare.Column{
+		{Name: "id", Type: "INTEGER", IsPrimaryKey: true, PkPos: 1, IsAutoKey: true},
+		{Name: "team_id", Type: "BIGINT"},
+		{Name: "role", Type: "TEXT"},
+		{Name: "nickname", Type: "TEXT", Nullable: true},
+	} {
+		col.Table = member
+		member.Columns = append(member.Columns, col)
+		member.ColumnByName[col.Name] = col
+	}
+	legacyId := &preformShare.Column{Name: "id", Type: "INTEGER", IsPrimaryKey: true, PkPos: 1, Table: legacy}
+	legacy.Columns, legacy.ColumnByName["id"] = []*preformShare.Column{legacyId}, legacyId
+	member.ForeignKeys["fk_member_legacy"] = &preformShare.ForeignKey{Name: "fk_member_legacy", LocalKeys: []*preformShare.Column{member.ColumnByName["team_id"]}, ForeignKeys: []*preformShare.Column{legacyId}}
+	scheme.Tables = []*preformShare.Table{member, legacy}
+	up, down, err = preformBuilder.DiffMigration(pg, []*preformShare.Scheme{scheme}, schema)
+	assert.Nil(t, err)
+	assertMigration(t, []string{
+		`CREATE TYPE "preform_migration"."member_status" AS ENUM ('active', 'left');`,
+		`CREATE TABLE "preform_migration"."team" (`,
+		`ALTER TABLE "preform_migration"."member" ADD COLUMN "status" member_status NOT NULL;`,
+		`ALTER TABLE "preform_migration"."member" ALTER COLUMN "team_id" TYPE INTEGER USING "team_id"::INTEGER;`,
+		`ALTER TABLE "preform_migration"."member" ALTER COLUMN "role" DROP NOT NULL;`,
+		`ALTER TABLE "preform_migration"."member" DROP CONSTRAINT "fk_member_legacy";`,
+		`ALTER TABLE "preform_migration"."member" ADD CONSTRAINT "fk_member_team_id_id" FOREIGN KEY ("team_id") REFERENCES "preform_migration"."team" ("id");`,
+		`ALTER TABLE "preform_migration"."member" DROP COLUMN "nickname";`,
+		`DROP TABLE "preform_migration"."legacy";`,
+		`DROP TYPE "preform_migration"."old_status";`,
+	}, up)
+	assertMigration(t, []string{
+		`CREATE TYPE "preform_migration"."old_status" AS ENUM ('a');`,
+		`CREATE TABLE "preform_migration"."legacy" (`,
+		`ALTER TABLE "preform_migration"."member" ADD COLUMN "nickname" TEXT;`,
+		`ALTER TABLE "preform_migration"."member" DROP CONSTRAINT "fk_member_team_id_id";`,
+		`ALTER TABLE "preform_migration"."member" ADD CONSTRAINT "fk_member_legacy" FOREIGN KEY ("team_id") REFERENCES "preform_migration"."legacy" ("id");`,
+		`ALTER TABLE "preform_migration"."member" ALTER COLUMN "role" SET NOT NULL;`,
+		`ALTER TABLE "preform_migration"."member" ALTER COLUMN "team_id" TYPE BIGINT USING "team_id"::BIGINT;`,
+		`ALTER TABLE "preform_migration"."member" DROP COLUMN "status";`,
+		`DROP TABLE "preform_migration"."team";`,
+		`DROP TYPE "preform_migration"."member_status";`,
+	}, down)
+
+	// alter column is not supported by sqlite, left as a comment
+	up, _, err = preformBuilder.DiffMigration(mainModel.PreformTestA.GetDialect(), []*preformShare.Scheme{scheme}, schema)
+	assert.Nil(t, err)
+	assert.Contains(t, up, "-- alter column is not supported by the dialect, preform_migration.member.role")
+
+	// defaults as reported by the db and the custom migrator table make no statements
+	current := migrationCurrent("preform_migration", "my_migration")
+	current.Tables[0].ColumnByName["name"].DefaultValue = sql.NullString{String: "''::text", Valid: true}
+	schema.MigrationTable = "preform_migration.my_migration"
+	up, down, err = preformBuilder.DiffMigration(pg, []*preformShare.Scheme{current}, schema)
+	assert.Nil(t, err)
+	assert.Empty(t, up)
+	assert.Empty(t, down)
+	schema.MigrationTable = ""
+	up, _, err = preformBuilder.DiffMigration(pg, []*preformShare.Scheme{current}, schema)
+	assert.Nil(t, err)
+	assertMigration(t, []string{`DROP TABLE "preform_migration"."my_migration";`}, up)
+}
+
+// migrationCurrent scheme matching migrationSchema with a migrator table
+func migrationCurrent(name, migrationTable string) *preformShare.Scheme {
+	var (
+		scheme = &preformShare.Scheme{Name: name, Enums: map[string][]string{"member_status": {"active", "left"}}}
+		tables = map[string][]*preformShare.Column{
+			"team":         {{Name: "id", Type: "INTEGER", IsPrimaryKey: true, PkPos: 1, IsAutoKey: true}, {Name: "name", Type: "TEXT"}},
+			"member":       {{Name: "id", Type: "INTEGER", IsPrimaryKey: true, PkPos: 1, IsAutoKey: true}, {Name: "team_id", Type: "INTEGER"}, {Name: "role", Type: "TEXT", Nullable: true}, {Name: "status", Type: "member_status"}},
+			migrationTable: {{Name: "version", Type: "VARCHAR(32)", IsPrimaryKey: true, PkPos: 1}},
+		}
+	)
+	for _, tableName := range []string{"team", "member", migrationTable} {
+		table := &preformShare.Table{Name: tableName, Scheme: scheme, ColumnByName: map[string]*preformShare.Column{}, ForeignKeys: map[string]*preformShare.ForeignKey{}}
+		for _, col := range tables[tableName] {
+			col.Table = table
+			table.Columns = append(table.Columns, col)
+			table.ColumnByName[col.Name] = col
+		}
+		scheme.Tables = append(scheme.Tables, table)
+	}
+	scheme.Tables[1].ForeignKeys["fk_member_team_id_id"] = &preformShare.ForeignKey{Name: "fk_member_team_id_id", LocalKeys: []*preformShare.Column{scheme.Tables[1].ColumnByName["team_id"]}, ForeignKeys: []*preformShare.Column{scheme.Tables[0].ColumnByName["id"]}}
+	return scheme
+}
+
+// assertMigration statements start with expected, multi-line ones by their first line
+func assertMigration(t *testing.T, expected, statements []string) {
+	if assert.Equal(t, len(expected), len(statements), strings.Join(statements, "\n")) {
+		for i, q := range statements {
+			assert.True(t, strings.HasPrefix(q, expected[i]), q)
+		}
+	}
+}
+
+func TestBuildMigration(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		dir    = t.TempDir()
+		schema = migrationSchema(t, "main")
+	)
+	schema.Enums = nil //not supported by sqlite
+	migrationConn, err := sql.Open("sqlite3", dir+"/migration.db")
+	assert.Nil(t, err)
+	defer migrationConn.Close()
+	version, err := preformBuilder.BuildMigration(migrationConn, dir+"/migrations", "init team", schema)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", version)
+	up, err := os.ReadFile(fmt.Sprintf("%s/migrations/%s_init_team.up.sql", dir, version))
+	assert.Nil(t, err)
+	assert.Less(t, strings.Index(string(up), `CREATE TABLE "main"."team"`), strings.Index(string(up), `CREATE TABLE "main"."member"`))
+	applied, err := preform.DbFromNative(migrationConn).NewMigrator(os.DirFS(dir+"/migrations"), "my_migration").Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{version}, applied)
+	// the migrator table is left alone
+	schema.MigrationTable = "my_migration"
+	version, err = preformBuilder.BuildMigration(migrationConn, dir+"/migrations", "nothing", schema)
+	assert.Nil(t, err)
+	assert.Equal(t, "", version)
+}
+
+func TestVerify(t *testing.T) {
+	report, err := mainModel.PreformTestA.Verify(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, report.Ok(), report.Error())
+	var postSql string
+	assert.Nil(t, mainModel.PreformTestA.QueryRow(`SELECT sql FROM preform_test_a.sqlite_master WHERE name = 'post'`).Scan(&postSql))
+	_, err = mainModel.PreformTestA.Exec(`ALTER TABLE preform_test_a.post ADD COLUMN verify_extra INTEGER NULL`)
+	assert.Nil(t, err)
+	report, err = mainModel.PreformTestA.Verify(context.Background())
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(report.Drifts)) {
+		assert.Equal(t, preform.DriftExtraColumn, report.Drifts[0].Kind)
+		assert.Equal(t, "post", report.Drifts[0].Table)
+		assert.Equal(t, "verify_extra", report.Drifts[0].Column)
+	}
+	// no DROP COLUMN before sqlite 3.35, recreate the table instead
+	_, err = mainModel.PreformTestA.Exec(`DROP TABLE preform_test_a.post`)
+	assert.Nil(t, err)
+	_, err = mainModel.PreformTestA.Exec(strings.Replace(postSql, "CREATE TABLE ", "CREATE TABLE preform_test_a.", 1))
+	assert.Nil(t, err)
+	report, err = mainModel.PreformTestA.Verify(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, report.Ok(), report.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = mainModel.PreformTestA.Verify(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUserEach(t *testing.T) {
+	var ids []int64
+	err := mainModel.PreformTestA.User.Select().OrderBy(mainModel.PreformTestA.User.Id.Asc()).Each(func(user *mainModel.UserBody) error {
+		ids = append(ids, user.Id)
+		if len(ids) == 3 {
+			return preform.ErrorStopEach
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, 0, conn.Stats().InUse)
+
+	errFn := errors.New("fn")
+	err = mainModel.PreformTestA.User.Select().Each(func(user *mainModel.UserBody) error {
+		return errFn
+	})
+	assert.Equal(t, errFn, err)
+	assert.Equal(t, 0, conn.Stats().InUse)
+
+	tx, err := mainModel.PreformTestA.BeginTx(context.Background())
+	assert.Nil(t, err)
+	err = mainModel.PreformTestA.User.Select().Tx(tx).Eager(mainModel.PreformTestA.User.UserLogs).Each(func(user *mainModel.UserBody) error {
+		return nil
+	})
+	assert.Equal(t, preform.ErrorEachEagerTx, err)
+	assert.Nil(t, tx.Rollback())
+	err = mainModel.PreformTestA.WithTx(context.Background(), func(ctx context.Context) error {
+		return mainModel.PreformTestA.User.Select().Ctx(ctx).Eager(mainModel.PreformTestA.User.UserLogs).Each(func(user *mainModel.UserBody) error {
+			return nil
+		})
+	})
+	assert.Equal(t, preform.ErrorEachEagerTx, err)
+
+	testUserIterate(t)
+}
+
+func TestUserPageAfter(t *testing.T) {
+	var (
+		f      = mainModel.PreformTestA.User
+		ids    []int64
+		cursor string
+	)
+	for i := 0; i < 5; i++ {
+		users, next, err := f.Select().Limit(2).PageAfter(cursor, f.Id)
+		assert.Nil(t, err)
+		for _, user := range users {
+			ids = append(ids, user.Id)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	users, next, err := f.Select().Limit(3).PageAfter("", preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+	assert.Equal(t, int64(3), users[2].Id)
+	users, next, err = f.Select().Limit(3).PageAfter(next, preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, int64(1), users[1].Id)
+	assert.Equal(t, "", next)
+
+	users, next, err = f.Select().Where(f.Id.Gt(1)).Limit(3).PageAfter("", f.CreatedBy, preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 3)
+	assert.Equal(t, []int64{4, 2, 5}, []int64{users[0].Id, users[1].Id, users[2].Id})
+	users, _, err = f.Select().Where(f.Id.Gt(1)).Limit(3).PageAfter(next, f.CreatedBy, preform.OrderDesc(f.Id))
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int64(3), users[0].Id)
+
+	_, _, err = f.Select().PageAfter("bad", f.Id)
+	assert.ErrorIs(t, err, preform.ErrorInvalidCursor)
+	_, _, err = f.Select().Limit(2).PageAfter("", f.LoginedAt, f.Id)
+	assert.ErrorIs(t, err, preform.ErrorPageNullable)
+	_, _, err = f.Select().Limit(2).PageAfter("", preform.OrderDesc(f.SetAlias("U").(*mainModel.FactoryUser).LoginedAt), f.Id)
+	assert.ErrorIs(t, err, preform.ErrorPageNullable)
+}
+
 func TestUserSelectRelation(t *testing.T) {
 	user, err := mainModel.PreformTestA.User.Select().Eager(mainModel.PreformTestA.User.UserLogs).GetOne(1)
 	assert.Nil(t, err)
@@ -221,6 +934,44 @@ func TestUserSelectRelation(t *testing.T) {
 	assert.NotNil(t, foos[1].Bar)
 }
 
+func TestUserRelationExists(t *testing.T) {
+	mainModel.PreformTestA.User.Use(func(f *mainModel.FactoryUser) {
+		logType := mainModel.PreformTestA.UserLog.Type
+		users, err := f.Select().Where(f.UserLogs.Exists()).GetAll() //both logs are of user 1
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		users, err = f.Select().Where(f.UserLogs.Exists(logType.Eq(2))).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		users, err = f.Select().Where(f.UserLogs.NotExists()).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 4)
+		users, err = f.Select().Where(f.UserLogsByUserLogUserFkRegister.Exists(logType.Eq(2))).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		users, err = f.Select().Where(f.UserLogs.Exists(squirrel.Or{logType.Eq(2), logType.Eq(5)})).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		users, err = f.Select().Where(f.UserLogs.Exists(squirrel.Or{logType.Eq(4), squirrel.And{logType.Eq(5), f.Id.Eq(1)}})).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+		users, err = f.Select().Where(f.UserLogs.Exists(logType.Or(logType.Eq(4), logType.Eq(2)))).GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		q, _, err := f.Select().Where(f.UserLogs.Exists(logType.Or(logType.Eq(4), f.Name.Eq("test1")))).ToSql()
+		assert.Nil(t, err)
+		assert.Contains(t, q, `("UserLogs"."type" = $1 OR "User"."name" = $2)`)
+
+		affected, err := f.Update().Set(f.CreatedAt, preformTypes.SqliteTime(time.Now())).Where(f.UserLogs.Exists(squirrel.Or{logType.Eq(2), f.Name.Eq("test1")})).Exec()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), affected)
+		affected, err = f.Delete().Where(f.UserLogs.NotExists()).Where(f.Id.Eq(0)).Exec()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), affected)
+	})
+}
+
 func TestUserUpdate(t *testing.T) {
 	user, err := mainModel.PreformTestA.User.GetOne(5)
 	assert.Nil(t, err)
@@ -267,6 +1018,157 @@ func TestUserUpdate(t *testing.T) {
 	assert.Equal(t, int64(3), affected)
 }
 
+func TestUserSoftDelete(t *testing.T) {
+	f := mainModel.PreformTestA.User
+	f.SetSoftDelete(f.LoginedAt, func() any { return preformTypes.SqliteTime(time.Now()) })
+	defer f.SetSoftDelete(nil)
+
+	user, err := f.GetOne(4)
+	assert.Nil(t, err)
+	affected, err := user.Delete()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+	_, err = f.GetOne(4)
+	assert.NotNil(t, err)
+	cnt, err := f.Select().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), cnt)
+	cnt, err = f.Select().WithTrashed().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), cnt)
+	users, err := f.Select().OnlyTrashed().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int64(4), users[0].Id)
+	assert.True(t, users[0].LoginedAt.Valid)
+
+	affected, err = f.Delete().Where(f.Id.Eq(4)).Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), affected)
+	q, _, err := f.Delete().Where(f.Id.Eq(4)).ToSql()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(q, "UPDATE"))
+	q, _, err = f.Delete().ForceDelete().Where(f.Id.Eq(4)).ToSql()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(q, "DELETE"))
+
+	affected, err = f.Update().Set(f.LoginedAt, nil).Where(f.Id.Eq(4)).Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	// joined, eager loaded and exists relations skip soft deleted rows too
+	fl := mainModel.PreformTestA.UserLog
+	joined, err := fl.Select().JoinRelation(fl.UserByUserLogUserFk).Count()
+	assert.Nil(t, err)
+	exists, err := fl.Select().Where(fl.UserByUserLogUserFk.Exists()).Count()
+	assert.Nil(t, err)
+	_, err = f.Delete().Where(f.Id.Eq(1)).Exec()
+	assert.Nil(t, err)
+	logs, err := fl.Select().Where(fl.UserId.Eq(1)).Eager(fl.UserByUserLogUserFk).GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+	for _, log := range logs {
+		assert.Nil(t, log.UserByUserLogUserFk)
+	}
+	cnt, err = fl.Select().JoinRelation(fl.UserByUserLogUserFk).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, joined-2, cnt)
+	cnt, err = fl.Select().Where(fl.UserByUserLogUserFk.Exists()).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, exists-2, cnt)
+	_, err = f.Update().Set(f.LoginedAt, nil).Where(f.Id.Eq(1)).Exec()
+	assert.Nil(t, err)
+}
+
+func TestUserVersion(t *testing.T) {
+	var (
+		f        = mainModel.PreformTestA.User
+		cfg      = preform.UpdateConfig{Cols: []preform.ICol{f.Name}}
+		staleErr preform.ErrStaleObject
+	)
+	assert.Panics(t, func() { f.SetVersion(f.LoginedAt) })
+	assert.Panics(t, func() { f.SetVersion(f.Name) })
+	assert.Nil(t, f.VersionColumn())
+	f.SetVersion(f.CreatedBy)
+	defer f.SetVersion(nil)
+
+	user, err := f.GetOne(4)
+	assert.Nil(t, err)
+	stale, err := f.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), user.CreatedBy)
+	user.Name = "test4-v"
+	affected, err := user.Update(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(2), user.CreatedBy)
+
+	stale.Name = "stale"
+	_, err = stale.Update(cfg)
+	assert.ErrorAs(t, err, &staleErr)
+	user, err = f.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, "test4-v", user.Name)
+	assert.Equal(t, int64(2), user.CreatedBy)
+
+	f.SetVersion(nil)
+	user.Name = "test4"
+	user.CreatedBy = 1
+	_, err = user.Update(preform.UpdateConfig{Cols: []preform.ICol{f.Name, f.CreatedBy}})
+	assert.Nil(t, err)
+
+	// time version is read back as stored, so the body keeps matching it
+	f.SetVersion(f.CreatedAt)
+	user, err = f.GetOne(4)
+	assert.Nil(t, err)
+	stale, err = f.GetOne(4)
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = user.Update(cfg)
+		assert.Nil(t, err)
+	}
+	stored, err := f.GetOne(4)
+	assert.Nil(t, err)
+	assert.True(t, time.Time(stored.CreatedAt).Equal(time.Time(user.CreatedAt)))
+	_, err = stale.Update(cfg)
+	assert.ErrorAs(t, err, &staleErr)
+}
+
+func TestPost(t *testing.T) {
+	var (
+		f        = mainModel.PreformTestA.Post
+		staleErr preform.ErrStaleObject
+	)
+	assert.Equal(t, f.DeletedAt.DbName(), f.SoftDeleteColumn().DbName())
+
+	post := &mainModel.PostBody{Title: "post1", Version: 1}
+	assert.Nil(t, post.Insert())
+	stale, err := f.GetOne(post.Id)
+	assert.Nil(t, err)
+	post.Title = "post1-v"
+	affected, err := post.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(2), post.Version)
+	stale.Title = "stale"
+	_, err = stale.Update()
+	assert.ErrorAs(t, err, &staleErr)
+
+	affected, err = post.Delete()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+	_, err = f.GetOne(post.Id)
+	assert.NotNil(t, err)
+	posts, err := f.Select().WithTrashed().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, "post1-v", posts[0].Title)
+	assert.True(t, posts[0].DeletedAt.Valid)
+	affected, err = f.Delete().ForceDelete().Where(f.Id.Eq(post.Id)).Exec()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), affected)
+}
+
 func TestUserDelete(t *testing.T) {
 	affected, err := mainModel.PreformTestA.User.Delete().Where(mainModel.PreformTestA.User.Id.Gt(4)).Exec()
 	assert.Nil(t, err)
@@ -285,6 +1187,85 @@ func TestUserDelete(t *testing.T) {
 	assert.Len(t, allUsers, 3)
 }
 
+func TestUserUpsert(t *testing.T) {
+	user, err := mainModel.PreformTestA.User.GetOne(4)
+	assert.Nil(t, err)
+	user.Name = "test4-1"
+	err = mainModel.PreformTestA.User.Upsert(user, preform.UpsertConfig{UpdateCols: []preform.ICol{mainModel.PreformTestA.User.Name}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), user.Id)
+
+	user, err = mainModel.PreformTestA.User.GetOne(4)
+	assert.Nil(t, err)
+	assert.Equal(t, "test4-1", user.Name)
+
+	newUser := &mainModel.UserBody{
+		Name:      "test6",
+		CreatedBy: 1,
+	}
+	err = mainModel.PreformTestA.User.Upsert(newUser)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), newUser.Id)
+
+	allUsers, err := mainModel.PreformTestA.User.Select().GetAll()
+	assert.Nil(t, err)
+	assert.Len(t, allUsers, 4)
+
+	_, err = mainModel.PreformTestA.Exec(`CREATE UNIQUE INDEX preform_test_a.user_name_un ON "user"(name);`)
+	assert.Nil(t, err)
+	defer mainModel.PreformTestA.Exec("DROP INDEX preform_test_a.user_name_un;")
+	existingUser := &mainModel.UserBody{
+		Name:      "test6",
+		CreatedBy: 2,
+	}
+	err = mainModel.PreformTestA.User.Upsert(existingUser, preform.UpsertConfig{ConflictCols: []preform.ICol{mainModel.PreformTestA.User.Name}, UpdateCols: []preform.ICol{mainModel.PreformTestA.User.CreatedBy}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), existingUser.Id)
+	user, err = mainModel.PreformTestA.User.GetOne(6)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), user.CreatedBy)
+
+	// primary keys are left out of the default update columns
+	existingUser = &mainModel.UserBody{
+		Id:        99,
+		Name:      "test6",
+		CreatedBy: 3,
+	}
+	err = mainModel.PreformTestA.User.Insert(existingUser, preform.EditConfig{NoAutoPrimaryKey: true, OnConflict: &preform.UpsertConfig{ConflictCols: []preform.ICol{mainModel.PreformTestA.User.Name}}})
+	assert.Nil(t, err)
+	user, err = mainModel.PreformTestA.User.GetOne(6)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), user.CreatedBy)
+}
+
+func TestFooHook(t *testing.T) {
+	var (
+		f   = mainModel.PreformTestA.Foo
+		foo = &mainModel.FooBody{Fk: 1}
+	)
+	mainModel.FooHookCalls = nil
+	assert.Nil(t, foo.Insert())
+	foo.Fk = 2
+	_, err := foo.Update()
+	assert.Nil(t, err)
+	_, err = foo.Delete()
+	assert.Nil(t, err)
+	err = f.Insert(&mainModel.FooBody{})
+	assert.ErrorIs(t, err, mainModel.ErrorFooWithoutBar)
+	bar := &mainModel.BarBody{Id: 3, Foos: []*mainModel.FooBody{{}}}
+	assert.Nil(t, bar.Insert(preform.EditConfig{Cascading: true}))
+	assert.Equal(t, int64(3), bar.Foos[0].Fk)
+	assert.Equal(t, []string{"BeforeInsert", "AfterInsert", "BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete", "BeforeInsert", "BeforeInsert", "AfterInsert"}, mainModel.FooHookCalls)
+
+	// builders run hooks on the bodies they have only, none without SetBodies, see the pg test for ExecReturning
+	mainModel.FooHookCalls = nil
+	_, err = f.Update().Set(f.Fk, 3).Where(f.Fk.Eq(3)).Exec()
+	assert.Nil(t, err)
+	_, err = f.Delete().Where(f.Id.Eq(bar.Foos[0].Id)).Exec()
+	assert.Nil(t, err)
+	assert.Nil(t, mainModel.FooHookCalls)
+}
+
 func TestPrebuildQuery(t *testing.T) {
 	userLogs, err := mainModel.UserAndLog.Select().GetAll()
 	assert.Nil(t, err)
@@ -300,6 +1281,27 @@ func TestPrebuildQuery(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, userLogs, 1)
 	assert.Equal(t, int64(0), userLogs[0].UserId)
+
+	q, args, err := mainModel.FilteredUser.Select().ToSql()
+	assert.Nil(t, err)
+	assert.Contains(t, q, `"User"."id" IN ($1,$2,$3,$4)`)
+	assert.Contains(t, q, `"User"."id" NOT IN ($5)`)
+	assert.Contains(t, q, `"User"."logined_at" IS NULL`)
+	assert.Contains(t, q, `LOWER("User"."name") LIKE LOWER($6)`)
+	assert.Contains(t, q, `"User"."created_by" NOT BETWEEN $7 AND $8`)
+	assert.Equal(t, []any{1, 2, 3, 4, 3, "TEST%", 100, 200}, args)
+	users, err := mainModel.FilteredUser.Select().GetAll()
+	assert.Nil(t, err)
+	f := mainModel.PreformTestA.User
+	count, err := f.Select().Where(f.Id.In(1, 2, 3, 4)).Where(f.Id.NotIn(3)).Where(f.LoginedAt.IsNull()).Where(f.Name.ILike("test%")).Where(f.CreatedBy.NotBetween(100, 200)).Count()
+	assert.Nil(t, err)
+	assert.NotZero(t, count)
+	assert.Len(t, users, int(count))
+
+	assert.Panics(t, func() { preformBuilder.NewConditionForBuilder[int64](nil).In(&preformBuilder.QueryBuilder{}) })
+	assert.Panics(t, func() {
+		preformBuilder.NewConditionForBuilder[int64](nil).NotIn([]any{1, &preformBuilder.QueryBuilder{}})
+	})
 }
 
 func TestTesters(t *testing.T) {
@@ -0,0 +1,9 @@
+//go:build !go1.23
+
+package model_test
+
+import (
+	"testing"
+)
+
+func testUserIterate(t *testing.T) {}
@@ -0,0 +1,33 @@
+//go:build go1.23
+
+package model_test
+
+import (
+	"github.com/go-preform/preform/test/sqlite/mainModel"
+	"github.com/go-preform/squirrel"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// testUserIterate run by TestUserEach, Iterate needs go 1.23
+func testUserIterate(t *testing.T) {
+	var ids []int64
+	for user, err := range mainModel.PreformTestA.User.Select().OrderBy(mainModel.PreformTestA.User.Id.Asc()).Iterate() {
+		assert.Nil(t, err)
+		ids = append(ids, user.Id)
+		if len(ids) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.Equal(t, 0, conn.Stats().InUse)
+
+	cnt := 0
+	for user, err := range mainModel.PreformTestA.User.Select().Where(squirrel.Expr("no_such_col = 1")).Iterate() {
+		cnt++
+		assert.Nil(t, user)
+		assert.NotNil(t, err)
+	}
+	assert.Equal(t, 1, cnt)
+	assert.Equal(t, 0, conn.Stats().InUse)
+}
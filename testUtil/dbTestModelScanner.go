@@ -101,6 +101,21 @@ func (t *TestModelScanner[B]) ScanBodiesFast(rows preform.IRows, cols []preform.
 	return t.popBodies(), err
 }
 
+func (t *TestModelScanner[B]) ScanEach(rows preform.IRows, cols []preform.ICol, fn func(*B) error) error {
+	err := t.popError()
+	if err != nil {
+		return err
+	}
+	bodies := t.popBodies()
+	for i := range bodies {
+		err = fn(&bodies[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *TestModelScanner[B]) ScanBody(row preform.IRow, cols []preform.ICol) (*B, error) {
 	err := t.popError()
 	if err != nil {
@@ -108,6 +108,7 @@ type TestQueryRunner struct {
 	StmtQueue     []*sql.Stmt
 	QueryRows     []*sql.Rows
 	QueryxRows    []*sqlx.Rows
+	Queries       []string //queries run, in order
 }
 
 func NewTestQueryRunner() *TestQueryRunner {
@@ -189,6 +190,7 @@ func (t *testQueryRunnerResult) RowsAffected() (int64, error) {
 }
 
 func (t *TestQueryRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.Queries = append(t.Queries, query)
 	return &testQueryRunnerResult{parent: t}, t.popError()
 }
 
@@ -197,6 +199,7 @@ func (t *TestQueryRunner) Query(query string, args ...interface{}) (*sql.Rows, e
 }
 
 func (t *TestQueryRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	t.Queries = append(t.Queries, query)
 	// handle fake rows in test IModelScanner
 	if len(t.QueryRows) == 0 {
 		return &sql.Rows{}, t.popError()
@@ -216,6 +219,7 @@ type Row struct {
 }
 
 func (t *TestQueryRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	t.Queries = append(t.Queries, query)
 	// handle fake rows in test IModelScanner
 	rows := &sql.Rows{}
 	if len(t.QueryRows) > 0 {
@@ -238,6 +242,7 @@ func (t *TestQueryRunner) Queryx(query string, args ...interface{}) (*sqlx.Rows,
 }
 
 func (t *TestQueryRunner) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	t.Queries = append(t.Queries, query)
 	// handle fake rows in test IModelScanner
 	if len(t.QueryxRows) == 0 {
 		return &sqlx.Rows{}, t.popError()
@@ -256,6 +261,7 @@ func (t *TestQueryRunner) QueryRowxContext(ctx context.Context, query string, ar
 }
 
 func (t *TestQueryRunner) InsertAndReturnAutoId(ctx context.Context, lastIdMethod preformShare.SqlDialectLastInsertIdMethod, query string, args ...any) (int64, error) {
+	t.Queries = append(t.Queries, query)
 	return t.popLastId(), t.popError()
 }
 
@@ -267,4 +273,8 @@ func (t *TestQueryRunner) RelatedFactory([]preformShare.IQueryFactory) preformSh
 	return t
 }
 
+func (t *TestQueryRunner) ReplicaOf(preformShare.DbQueryRunner) preformShare.QueryRunner {
+	return t
+}
+
 func (t *TestQueryRunner) IsTester() {}
@@ -80,6 +80,14 @@ func (l *chainTracer) HealthLoop(ctx context.Context, db preform.DB) {
 	}
 }
 
+func (l *chainTracer) Retry(ctx context.Context, driver string, attempt int, wait time.Duration, err error) {
+	for _, tracer := range l.tracers {
+		if rt, ok := tracer.(preform.IRetryTracer); ok {
+			rt.Retry(ctx, driver, attempt, wait, err)
+		}
+	}
+}
+
 func (l *chainTracer) SetLv(logLv preformShare.LogLv) {
 	for _, tracer := range l.tracers {
 		tracer.SetLv(logLv)
@@ -119,6 +127,10 @@ func (l plainTracer) Error(driver, msg string, err error) {
 	fmt.Printf("Preform Error %s %s %v\n", driver, msg, err)
 }
 
+func (l plainTracer) Retry(ctx context.Context, driver string, attempt int, wait time.Duration, err error) {
+	fmt.Printf("Preform Retry %s attempt: %d wait: %v %v\n", driver, attempt, wait, err)
+}
+
 func (l plainTracer) Trace(ctx context.Context, driver, query, txId string, args ...any) (context.Context, func(err error) func(fetched bool, err error)) {
 	return l.trace(ctx, driver, query, txId, args...)
 }
@@ -210,6 +222,10 @@ func (l zeroLogTracer) Error(driver, msg string, err error) {
 	l.logger.Error().Str("driver", driver).Str("msg", msg).Err(err).Msg("Error")
 }
 
+func (l zeroLogTracer) Retry(ctx context.Context, driver string, attempt int, wait time.Duration, err error) {
+	l.logger.Warn().Str("driver", driver).Int("attempt", attempt).Dur("wait", wait).Err(err).Msg("Retry")
+}
+
 func (l zeroLogTracer) Trace(ctx context.Context, driver, query, txId string, args ...any) (context.Context, func(err error) func(fetched bool, err error)) {
 	return l.trace(ctx, driver, query, txId, args...)
 }
@@ -343,6 +359,11 @@ func (l otelTracer) Error(driver, msg string, err error) {
 	}
 }
 
+// Retry added as an event of the span in ctx
+func (l otelTracer) Retry(ctx context.Context, driver string, attempt int, wait time.Duration, err error) {
+	trace.SpanFromContext(ctx).AddEvent("Preform Retry", trace.WithAttributes(attribute.String("driver", driver), attribute.Int("attempt", attempt), attribute.String("wait", wait.String()), attribute.String("error", err.Error())))
+}
+
 func (l otelTracer) Trace(ctx context.Context, driver, query, txId string, args ...any) (context.Context, func(err error) func(fetched bool, err error)) {
 	return l.trace(ctx, driver, query, txId, args...)
 }